@@ -0,0 +1,82 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package auth
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultRefreshMargin is how long before their expiry tokens are refreshed,
+// so that requests sent right before the expiry do not fail, and to allow
+// for clock skew with Docker Hub.
+const DefaultRefreshMargin = time.Minute
+
+// tokenCache caches the token of a token provider until shortly before it
+// expires. Token providers hold their own lock while refreshing the token,
+// so that only one refresh runs at a time.
+type tokenCache struct {
+	mu            sync.Mutex
+	token         string
+	refreshAt     time.Time
+	refreshMargin time.Duration
+}
+
+func newTokenCache() tokenCache {
+	return tokenCache{refreshMargin: DefaultRefreshMargin}
+}
+
+// SetRefreshMargin sets how long before its expiry the token is refreshed.
+func (c *tokenCache) SetRefreshMargin(margin time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.refreshMargin = margin
+}
+
+// InvalidateToken drops the cached token if it is still token, for example
+// after Docker Hub rejected it, so that the next request gets a new one.
+func (c *tokenCache) InvalidateToken(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token == token {
+		c.token = ""
+	}
+}
+
+// cached returns the cached token, unless it is due for a refresh.
+func (c *tokenCache) cached() (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token == "" || !time.Now().Before(c.refreshAt) {
+		return "", false
+	}
+	return c.token, true
+}
+
+// store caches a token expiring at expiry. Tokens living for less than
+// twice the margin are refreshed halfway through their lifetime instead.
+func (c *tokenCache) store(token string, expiry time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	refreshAt := expiry.Add(-c.refreshMargin)
+	if halfway := now.Add(expiry.Sub(now) / 2); refreshAt.Before(halfway) {
+		refreshAt = halfway
+	}
+	c.token = token
+	c.refreshAt = refreshAt
+}
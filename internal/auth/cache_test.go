@@ -0,0 +1,77 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package auth
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTokenCache(t *testing.T) {
+	tests := []struct {
+		name       string
+		margin     time.Duration
+		lifetime   time.Duration
+		wantCached bool
+		// wantRefreshIn is how long after storing the token it is due for a
+		// refresh.
+		wantRefreshIn time.Duration
+	}{
+		{name: "long lived", margin: time.Minute, lifetime: time.Hour, wantCached: true, wantRefreshIn: 59 * time.Minute},
+		{name: "no margin", margin: 0, lifetime: time.Hour, wantCached: true, wantRefreshIn: time.Hour},
+		{name: "short lived", margin: time.Minute, lifetime: time.Minute, wantCached: true, wantRefreshIn: 30 * time.Second},
+		{name: "expired", margin: time.Minute, lifetime: -time.Second, wantCached: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := newTokenCache()
+			cache.SetRefreshMargin(tt.margin)
+			now := time.Now()
+			cache.store("token", now.Add(tt.lifetime))
+
+			token, ok := cache.cached()
+			if ok != tt.wantCached {
+				t.Fatalf("got cached %v, want %v", ok, tt.wantCached)
+			}
+			if !ok {
+				return
+			}
+			if token != "token" {
+				t.Errorf("got token %q, want token", token)
+			}
+			if refreshIn := cache.refreshAt.Sub(now); refreshIn < tt.wantRefreshIn-time.Second || refreshIn > tt.wantRefreshIn+time.Second {
+				t.Errorf("got refresh in %s, want %s", refreshIn, tt.wantRefreshIn)
+			}
+		})
+	}
+}
+
+func TestTokenCacheInvalidate(t *testing.T) {
+	cache := newTokenCache()
+	cache.store("new", time.Now().Add(time.Hour))
+
+	// A token that was already replaced is ignored.
+	cache.InvalidateToken("old")
+	if token, ok := cache.cached(); !ok || token != "new" {
+		t.Fatalf("got %q, %v, want the new token to stay cached", token, ok)
+	}
+
+	cache.InvalidateToken("new")
+	if _, ok := cache.cached(); ok {
+		t.Fatal("got a cached token after invalidating it")
+	}
+}
@@ -19,24 +19,91 @@ package auth
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/docker/cli/cli/config"
 	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/cli/config/credentials"
 	"github.com/go-jose/go-jose/v3/jwt"
 )
 
+// credentialHelperPrefix is the prefix of the programs implementing the
+// credential helper protocol.
+const credentialHelperPrefix = "docker-credential-"
+
 // ConfigStore wraps the  config file and provides credential access methods
 type ConfigStore struct {
 	configFile *configfile.ConfigFile
+	// loadErr is why the default config file could not be loaded. Reading
+	// credentials from the config file fails with it.
+	loadErr error
+}
+
+// ConfigStoreOptions configures where LoadConfigStore reads credentials from.
+type ConfigStoreOptions struct {
+	// ConfigFile is the path of the config file, which must exist. Defaults
+	// to config.json in the DOCKER_CONFIG directory, or in ~/.docker.
+	ConfigFile string
+	// CredentialHelper is the credential helper to read all credentials
+	// with, such as "pass" or "docker-credential-pass", instead of the
+	// credsStore and credHelpers of the config file.
+	CredentialHelper string
 }
 
-// NewConfigStore creates a new ConfigStore with the default  config file
-func NewConfigStore() *ConfigStore {
-	return &ConfigStore{
-		configFile: config.LoadDefaultConfigFile(os.Stderr),
+// LoadConfigStore creates a new ConfigStore from the given options. A config
+// file given in the options that cannot be read is an error. The default
+// config file is optional: when it cannot be read, the store is empty and
+// LoadError reports why.
+func LoadConfigStore(opts ConfigStoreOptions) (*ConfigStore, error) {
+	var configFile *configfile.ConfigFile
+	var loadErr error
+	if opts.ConfigFile != "" {
+		f, err := os.Open(opts.ConfigFile)
+		if err != nil {
+			return nil, fmt.Errorf("open config file: %w", err)
+		}
+		defer f.Close()
+
+		configFile = configfile.New(opts.ConfigFile)
+		if err := configFile.LoadFromReader(f); err != nil {
+			return nil, fmt.Errorf("parse config file %s: %w", opts.ConfigFile, err)
+		}
+	} else {
+		// DOCKER_CONFIG is read here rather than through config.Dir, which
+		// only reads it once per process.
+		dir := os.Getenv("DOCKER_CONFIG")
+		if dir == "" {
+			dir = config.Dir()
+		}
+		var err error
+		if configFile, err = config.Load(dir); err != nil {
+			configFile = configfile.New(filepath.Join(dir, config.ConfigFileName))
+			loadErr = err
+		}
 	}
+
+	if opts.CredentialHelper != "" {
+		configFile.CredentialsStore = strings.TrimPrefix(opts.CredentialHelper, credentialHelperPrefix)
+		configFile.CredentialHelpers = nil
+		// The credential helper does not need the config file.
+		loadErr = nil
+	} else if !configFile.ContainsAuth() {
+		configFile.CredentialsStore = credentials.DetectDefaultStore(configFile.CredentialsStore)
+	}
+
+	return &ConfigStore{configFile: configFile, loadErr: loadErr}, nil
+}
+
+// LoadError returns why the default config file could not be loaded, or nil.
+func (c *ConfigStore) LoadError() error {
+	return c.loadErr
+}
+
+// ConfigFile returns the path of the config file.
+func (c *ConfigStore) ConfigFile() string {
+	return c.configFile.GetFilename()
 }
 
 // GetCredentialStorePullTokens retrieves the user credentials the the user is
@@ -50,6 +117,9 @@ func (c *ConfigStore) GetCredentialStorePullTokens(registryEntry string) (string
 func (c *ConfigStore) GetCredentialStoreAccessTokens(registryEntry string) (string, string, error) {
 	accessTokenKey := c.getAccessTokenConfigKey(registryEntry)
 	username, accessToken, err := c.readUserCreds(accessTokenKey)
+	if c.loadErr != nil {
+		return "", "", err
+	}
 	if err == nil && accessToken != "" {
 		// Check if the accessToken is a valid JWT and not expired
 		if isJWTAcceptable(accessToken) {
@@ -63,6 +133,9 @@ func (c *ConfigStore) GetCredentialStoreAccessTokens(registryEntry string) (stri
 // readUserCreds reads user credentials from the config file for a given
 // registry entry
 func (c *ConfigStore) readUserCreds(registryEntry string) (string, string, error) {
+	if c.loadErr != nil {
+		return "", "", fmt.Errorf("load config file: %w", c.loadErr)
+	}
 	authConfig, err := c.configFile.GetAuthConfig(registryEntry)
 	if err != nil {
 		return "", "", fmt.Errorf("get auth config: %w", err)
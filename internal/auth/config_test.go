@@ -0,0 +1,135 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func writeFile(t *testing.T, dir, name, content string, perm os.FileMode) string {
+	t.Helper()
+	filename := filepath.Join(dir, name)
+	if err := os.WriteFile(filename, []byte(content), perm); err != nil {
+		t.Fatal(err)
+	}
+	return filename
+}
+
+func TestLoadConfigStoreConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	// "alice:secret"
+	configFile := writeFile(t, dir, "agent.json", `{"auths": {"https://index.docker.io/v1/": {"auth": "YWxpY2U6c2VjcmV0"}}}`, 0o600)
+
+	store, err := LoadConfigStore(ConfigStoreOptions{ConfigFile: configFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+	username, password, err := store.GetCredentialStorePullTokens("https://index.docker.io/v1/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if username != "alice" || password != "secret" {
+		t.Errorf("got credentials %q/%q, want alice/secret", username, password)
+	}
+
+	if _, err := LoadConfigStore(ConfigStoreOptions{ConfigFile: filepath.Join(dir, "missing.json")}); err == nil {
+		t.Error("got no error for a missing config file")
+	}
+	malformed := writeFile(t, dir, "malformed.json", `{"auths":`, 0o600)
+	if _, err := LoadConfigStore(ConfigStoreOptions{ConfigFile: malformed}); err == nil {
+		t.Error("got no error for a malformed config file")
+	}
+}
+
+func TestLoadConfigStoreDockerConfig(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "config.json", `{"auths": {"registry.example.com": {"auth": "YWxpY2U6c2VjcmV0"}}}`, 0o600)
+	t.Setenv("DOCKER_CONFIG", dir)
+
+	store, err := LoadConfigStore(ConfigStoreOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if store.ConfigFile() != filepath.Join(dir, "config.json") {
+		t.Errorf("got config file %s, want the one in DOCKER_CONFIG", store.ConfigFile())
+	}
+	username, _, err := store.GetCredentialStorePullTokens("registry.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if username != "alice" {
+		t.Errorf("got username %q, want alice", username)
+	}
+}
+
+func TestLoadConfigStoreMalformedDockerConfig(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "config.json", `{"auths":`, 0o600)
+	t.Setenv("DOCKER_CONFIG", dir)
+
+	store, err := LoadConfigStore(ConfigStoreOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if store.LoadError() == nil {
+		t.Error("got no load error for a malformed default config file")
+	}
+	if _, _, err := store.GetCredentialStorePullTokens("registry.example.com"); err == nil {
+		t.Error("got no error reading credentials from a malformed default config file")
+	}
+	if _, _, err := store.GetCredentialStoreAccessTokens("registry.example.com"); err == nil {
+		t.Error("got no error reading access tokens from a malformed default config file")
+	}
+}
+
+func TestLoadConfigStoreCredentialHelper(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake credential helper is a shell script")
+	}
+	dir := t.TempDir()
+	writeFile(t, dir, "docker-credential-fake", `#!/bin/sh
+read server
+if [ "$1" = get ] && [ "$server" = "registry.example.com" ]; then
+	echo '{"ServerURL": "registry.example.com", "Username": "bob", "Secret": "from-helper"}'
+	exit 0
+fi
+echo "credentials not found in native keychain"
+exit 1
+`, 0o700)
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	// The helper of the config file is overridden.
+	configFile := writeFile(t, dir, "config.json", `{"credsStore": "missing", "credHelpers": {"registry.example.com": "missing"}}`, 0o600)
+
+	for _, helper := range []string{"fake", "docker-credential-fake"} {
+		t.Run(helper, func(t *testing.T) {
+			store, err := LoadConfigStore(ConfigStoreOptions{ConfigFile: configFile, CredentialHelper: helper})
+			if err != nil {
+				t.Fatal(err)
+			}
+			username, password, err := store.GetCredentialStorePullTokens("registry.example.com")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if username != "bob" || password != "from-helper" {
+				t.Errorf("got credentials %q/%q, want bob/from-helper", username, password)
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Parameters of an OAuth 2.0 token exchange (RFC 8693).
+const (
+	tokenExchangeGrantType = "urn:ietf:params:oauth:grant-type:token-exchange"
+	idTokenType            = "urn:ietf:params:oauth:token-type:id_token"
+	accessTokenType        = "urn:ietf:params:oauth:token-type:access_token"
+)
+
+// Environment variables GitHub Actions sets in jobs with the id-token: write
+// permission.
+const (
+	GitHubActionsRequestURLEnv   = "ACTIONS_ID_TOKEN_REQUEST_URL"
+	GitHubActionsRequestTokenEnv = "ACTIONS_ID_TOKEN_REQUEST_TOKEN"
+)
+
+// OIDCConfig configures where an OIDCTokenProvider reads the OIDC ID token
+// of the workload from, and where it exchanges it.
+type OIDCConfig struct {
+	// TokenURL is the token exchange endpoint.
+	TokenURL string
+	// Audience is the audience of the ID token requested from RequestURL,
+	// also sent to the token exchange endpoint.
+	Audience string
+
+	// The ID token is read from the first of TokenFile, TokenEnv and
+	// RequestURL that is set.
+
+	// TokenFile is a file containing the ID token, read before every
+	// exchange as CI systems may rotate it.
+	TokenFile string
+	// TokenEnv is the name of an environment variable containing the ID
+	// token.
+	TokenEnv string
+	// RequestURL is a URL returning the ID token in the value field of a
+	// JSON object, like the ID token endpoint of GitHub Actions.
+	RequestURL string
+	// RequestToken is sent as bearer token to RequestURL.
+	RequestToken string
+}
+
+// OIDCTokenProvider exchanges the OIDC ID token of a workload, such as a CI
+// job, for a short-lived Docker Hub token.
+type OIDCTokenProvider struct {
+	tokenCache
+	config     OIDCConfig
+	username   string
+	httpClient *http.Client
+	mu         sync.Mutex
+}
+
+// NewOIDCTokenProvider creates a token provider that exchanges ID tokens.
+// The username is only used for display purposes.
+func NewOIDCTokenProvider(config OIDCConfig, username string, transport http.RoundTripper) *OIDCTokenProvider {
+	return &OIDCTokenProvider{
+		tokenCache: newTokenCache(),
+		config:     config,
+		username:   username,
+		httpClient: &http.Client{
+			Timeout:   10 * time.Second,
+			Transport: transport,
+		},
+	}
+}
+
+// EnsureToken returns a cached token if valid, otherwise exchanges a fresh
+// ID token for a new one.
+func (p *OIDCTokenProvider) EnsureToken(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Return cached token if still valid
+	if token, ok := p.cached(); ok {
+		return token, nil
+	}
+
+	idToken, err := p.idToken(ctx)
+	if err != nil {
+		return "", fmt.Errorf("get OIDC ID token: %v", err)
+	}
+
+	form := url.Values{
+		"grant_type":           {tokenExchangeGrantType},
+		"subject_token":        {idToken},
+		"subject_token_type":   {idTokenType},
+		"requested_token_type": {accessTokenType},
+	}
+	if p.config.Audience != "" {
+		form.Set("audience", p.config.Audience)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.config.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	res, err := p.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("token exchange request: %v", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusBadRequest {
+		body, _ := io.ReadAll(res.Body)
+		if len(body) > 0 {
+			return "", fmt.Errorf("token exchange failed: %s - %s", res.Status, string(body))
+		}
+
+		return "", fmt.Errorf("token exchange failed: %s", res.Status)
+	}
+
+	var tokenResponse struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&tokenResponse); err != nil {
+		return "", fmt.Errorf("decode token exchange response: %v", err)
+	}
+	if tokenResponse.AccessToken == "" {
+		return "", fmt.Errorf("token exchange response does not contain an access token")
+	}
+
+	// Prefer the lifetime of the response, fall back to the expiry of the
+	// token.
+	expiry := time.Now().Add(time.Duration(tokenResponse.ExpiresIn) * time.Second)
+	if tokenResponse.ExpiresIn <= 0 {
+		claims, err := getClaims(tokenResponse.AccessToken)
+		if err != nil {
+			return "", fmt.Errorf("parse token claims: %v", err)
+		}
+		if claims.Expiry == nil {
+			return "", fmt.Errorf("token does not contain expiry")
+		}
+		expiry = claims.Expiry.Time()
+	}
+
+	// Cache the token
+	p.store(tokenResponse.AccessToken, expiry)
+
+	return tokenResponse.AccessToken, nil
+}
+
+func (p *OIDCTokenProvider) Username() string {
+	return p.username
+}
+
+// idToken reads the ID token of the workload from the configured source.
+func (p *OIDCTokenProvider) idToken(ctx context.Context) (string, error) {
+	switch {
+	case p.config.TokenFile != "":
+		content, err := os.ReadFile(p.config.TokenFile)
+		if err != nil {
+			return "", err
+		}
+		return nonEmptyToken(string(content), p.config.TokenFile)
+	case p.config.TokenEnv != "":
+		return nonEmptyToken(os.Getenv(p.config.TokenEnv), p.config.TokenEnv)
+	case p.config.RequestURL != "":
+		return p.requestIDToken(ctx)
+	default:
+		return "", fmt.Errorf("no ID token source configured")
+	}
+}
+
+func nonEmptyToken(token, source string) (string, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return "", fmt.Errorf("%s is empty", source)
+	}
+	return token, nil
+}
+
+// requestIDToken requests an ID token the way GitHub Actions jobs do.
+func (p *OIDCTokenProvider) requestIDToken(ctx context.Context) (string, error) {
+	u, err := url.Parse(p.config.RequestURL)
+	if err != nil {
+		return "", fmt.Errorf("parse request URL: %v", err)
+	}
+	if p.config.Audience != "" {
+		query := u.Query()
+		query.Set("audience", p.config.Audience)
+		u.RawQuery = query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/json")
+	if p.config.RequestToken != "" {
+		req.Header.Set("Authorization", "Bearer "+p.config.RequestToken)
+	}
+
+	res, err := p.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("ID token request: %v", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusBadRequest {
+		return "", fmt.Errorf("ID token request failed: %s", res.Status)
+	}
+
+	var tokenResponse struct {
+		Value string `json:"value"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&tokenResponse); err != nil {
+		return "", fmt.Errorf("decode ID token response: %v", err)
+	}
+	return nonEmptyToken(tokenResponse.Value, "ID token response")
+}
@@ -0,0 +1,197 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/go-jose/go-jose/v3/jwt"
+)
+
+// fakeIssuer is an OIDC issuer handing out ID tokens like GitHub Actions, and
+// a token exchange endpoint accepting them.
+type fakeIssuer struct {
+	*httptest.Server
+
+	key []byte
+	// ttl is the lifetime of the exchanged tokens.
+	ttl time.Duration
+	// sendExpiresIn reports the lifetime in the exchange response rather
+	// than only in the token.
+	sendExpiresIn bool
+
+	mu        sync.Mutex
+	exchanges int
+}
+
+func newFakeIssuer(t *testing.T) *fakeIssuer {
+	t.Helper()
+	issuer := &fakeIssuer{key: []byte("0123456789abcdef0123456789abcdef"), ttl: time.Hour}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /id-token", issuer.handleIDToken)
+	mux.HandleFunc("POST /exchange", issuer.handleExchange)
+	issuer.Server = httptest.NewServer(mux)
+	t.Cleanup(issuer.Close)
+	return issuer
+}
+
+func (f *fakeIssuer) sign(claims jwt.Claims) (string, error) {
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.HS256, Key: f.key}, (&jose.SignerOptions{}).WithType("JWT"))
+	if err != nil {
+		return "", err
+	}
+	return jwt.Signed(signer).Claims(claims).CompactSerialize()
+}
+
+func (f *fakeIssuer) handleIDToken(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer request-secret" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	token, err := f.sign(jwt.Claims{
+		Subject:  "repo:docker/app:ref:refs/heads/main",
+		Audience: jwt.Audience{r.URL.Query().Get("audience")},
+		Expiry:   jwt.NewNumericDate(time.Now().Add(5 * time.Minute)),
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(map[string]string{"value": token})
+}
+
+func (f *fakeIssuer) handleExchange(w http.ResponseWriter, r *http.Request) {
+	if r.FormValue("grant_type") != tokenExchangeGrantType || r.FormValue("subject_token_type") != idTokenType {
+		http.Error(w, `{"error": "unsupported_grant_type"}`, http.StatusBadRequest)
+		return
+	}
+	parsed, err := jwt.ParseSigned(r.FormValue("subject_token"))
+	var claims jwt.Claims
+	if err == nil {
+		err = parsed.Claims(f.key, &claims)
+	}
+	if err != nil || claims.ValidateWithLeeway(jwt.Expected{Audience: jwt.Audience{r.FormValue("audience")}, Time: time.Now()}, 0) != nil {
+		http.Error(w, `{"error": "invalid_grant"}`, http.StatusUnauthorized)
+		return
+	}
+
+	f.mu.Lock()
+	f.exchanges++
+	f.mu.Unlock()
+
+	token, err := f.sign(jwt.Claims{Subject: "acme", Expiry: jwt.NewNumericDate(time.Now().Add(f.ttl))})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	response := map[string]interface{}{"access_token": token, "token_type": "Bearer", "issued_token_type": accessTokenType}
+	if f.sendExpiresIn {
+		response["expires_in"] = int64(f.ttl.Seconds())
+	}
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func (f *fakeIssuer) exchangeCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.exchanges
+}
+
+func TestOIDCTokenProviderRequestURL(t *testing.T) {
+	ctx := context.Background()
+	issuer := newFakeIssuer(t)
+	issuer.sendExpiresIn = true
+	provider := NewOIDCTokenProvider(OIDCConfig{
+		TokenURL:     issuer.URL + "/exchange",
+		Audience:     "docker-hub",
+		RequestURL:   issuer.URL + "/id-token?api-version=2.0",
+		RequestToken: "request-secret",
+	}, "acme", nil)
+
+	token, err := provider.EnsureToken(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cached, err := provider.EnsureToken(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cached != token || issuer.exchangeCount() != 1 {
+		t.Errorf("got %d exchanges, want the token to be cached", issuer.exchangeCount())
+	}
+	if provider.Username() != "acme" {
+		t.Errorf("got username %q, want acme", provider.Username())
+	}
+}
+
+func TestOIDCTokenProviderTokenFile(t *testing.T) {
+	ctx := context.Background()
+	issuer := newFakeIssuer(t)
+	// Exchanged tokens are already expired, so every call exchanges again.
+	issuer.ttl = -time.Minute
+	tokenFile := filepath.Join(t.TempDir(), "id-token")
+	config := OIDCConfig{TokenURL: issuer.URL + "/exchange", TokenFile: tokenFile}
+	provider := NewOIDCTokenProvider(config, "", nil)
+
+	if _, err := provider.EnsureToken(ctx); err == nil || !strings.Contains(err.Error(), "get OIDC ID token") {
+		t.Fatalf("got error %v, want the missing token file to be reported", err)
+	}
+
+	for i := 1; i <= 2; i++ {
+		// The file is read again for every exchange.
+		idToken, err := issuer.sign(jwt.Claims{Audience: jwt.Audience{""}, Expiry: jwt.NewNumericDate(time.Now().Add(time.Minute))})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(tokenFile, []byte(idToken+"\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := provider.EnsureToken(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if issuer.exchangeCount() != i {
+			t.Errorf("got %d exchanges, want %d", issuer.exchangeCount(), i)
+		}
+	}
+}
+
+func TestOIDCTokenProviderRejected(t *testing.T) {
+	ctx := context.Background()
+	issuer := newFakeIssuer(t)
+	t.Setenv("TEST_OIDC_TOKEN", "not-a-jwt")
+	provider := NewOIDCTokenProvider(OIDCConfig{TokenURL: issuer.URL + "/exchange", TokenEnv: "TEST_OIDC_TOKEN"}, "", nil)
+
+	_, err := provider.EnsureToken(ctx)
+	if err == nil || !strings.Contains(err.Error(), "token exchange failed: 401") || !strings.Contains(err.Error(), "invalid_grant") {
+		t.Fatalf("got error %v, want the rejected exchange to be reported", err)
+	}
+
+	t.Setenv("TEST_OIDC_TOKEN", "")
+	if _, err := provider.EnsureToken(ctx); err == nil || !strings.Contains(err.Error(), "TEST_OIDC_TOKEN is empty") {
+		t.Fatalf("got error %v, want the empty variable to be reported", err)
+	}
+}
@@ -0,0 +1,114 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package auth
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/docker/terraform-provider-docker/internal/totp"
+)
+
+// DefaultPasswordCommandTimeout is how long a password command may run.
+const DefaultPasswordCommandTimeout = 30 * time.Second
+
+// PasswordSource returns the password to log in with. It is called for every
+// login, so that rotated secrets are picked up once the cached token
+// expires.
+type PasswordSource func(ctx context.Context) (string, error)
+
+// StaticPassword returns a PasswordSource that always returns password.
+func StaticPassword(password string) PasswordSource {
+	return func(ctx context.Context) (string, error) {
+		return password, nil
+	}
+}
+
+// PasswordFile returns a PasswordSource reading the password from a file.
+// Surrounding whitespace, such as a trailing newline, is trimmed.
+func PasswordFile(filename string) PasswordSource {
+	return func(ctx context.Context) (string, error) {
+		content, err := os.ReadFile(filename)
+		if err != nil {
+			return "", fmt.Errorf("read password file: %v", err)
+		}
+		password := strings.TrimSpace(string(content))
+		if password == "" {
+			return "", fmt.Errorf("password file %s is empty", filename)
+		}
+		return password, nil
+	}
+}
+
+// PasswordCommand returns a PasswordSource running a command and reading the
+// password from its standard output. Surrounding whitespace is trimmed. The
+// command is killed if it runs for longer than timeout.
+func PasswordCommand(args []string, timeout time.Duration) PasswordSource {
+	return func(ctx context.Context) (string, error) {
+		if len(args) == 0 {
+			return "", fmt.Errorf("password command is empty")
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		var stdout, stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			if ctx.Err() == context.DeadlineExceeded {
+				return "", fmt.Errorf("password command %s timed out after %s", args[0], timeout)
+			}
+			if msg := strings.TrimSpace(stderr.String()); msg != "" {
+				return "", fmt.Errorf("password command %s: %v: %s", args[0], err, msg)
+			}
+			return "", fmt.Errorf("password command %s: %v", args[0], err)
+		}
+
+		password := strings.TrimSpace(stdout.String())
+		if password == "" {
+			return "", fmt.Errorf("password command %s printed no password", args[0])
+		}
+		return password, nil
+	}
+}
+
+// OTPSource returns the one-time code to complete a login with, for accounts
+// with two-factor authentication.
+type OTPSource func(ctx context.Context) (string, error)
+
+// StaticOTP returns an OTPSource that always returns code. As codes are only
+// valid once and for a short time, logins after the first one fail.
+func StaticOTP(code string) OTPSource {
+	return func(ctx context.Context) (string, error) {
+		return code, nil
+	}
+}
+
+// TOTP returns an OTPSource computing the current code of a base32 TOTP
+// secret, like authenticator apps do.
+func TOTP(secret string) OTPSource {
+	return func(ctx context.Context) (string, error) {
+		return totp.Generate(secret, time.Now())
+	}
+}
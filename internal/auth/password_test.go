@@ -0,0 +1,176 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/terraform-provider-docker/internal/hubtest"
+	"github.com/docker/terraform-provider-docker/internal/totp"
+)
+
+func TestPasswordFile(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	password, err := PasswordFile(writeFile(t, dir, "token", "dckr_pat_secret\n", 0o600))(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if password != "dckr_pat_secret" {
+		t.Errorf("got password %q, want the trimmed content of the file", password)
+	}
+
+	if _, err := PasswordFile(writeFile(t, dir, "empty", "\n", 0o600))(ctx); err == nil || !strings.Contains(err.Error(), "is empty") {
+		t.Errorf("got error %v, want the empty file to be reported", err)
+	}
+	if _, err := PasswordFile(filepath.Join(dir, "missing"))(ctx); err == nil {
+		t.Error("got no error for a missing file")
+	}
+}
+
+func TestPasswordCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the password commands are shell commands")
+	}
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr string
+	}{
+		{name: "trimmed output", args: []string{"sh", "-c", "echo '  dckr_pat_secret  '"}, want: "dckr_pat_secret"},
+		{name: "failure", args: []string{"sh", "-c", "echo 'vault is sealed' >&2; exit 2"}, wantErr: "exit status 2: vault is sealed"},
+		{name: "no output", args: []string{"true"}, wantErr: "printed no password"},
+		{name: "timeout", args: []string{"sleep", "5"}, wantErr: "timed out after 100ms"},
+		{name: "empty", args: nil, wantErr: "password command is empty"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PasswordCommand(tt.args, 100*time.Millisecond)(ctx)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got password %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPasswordCommandRotatedPassword(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the password commands are shell commands")
+	}
+	ctx := context.Background()
+	secret := writeFile(t, t.TempDir(), "secret", "first", 0o600)
+	password := PasswordCommand([]string{"cat", secret}, time.Second)
+
+	if got, err := password(ctx); err != nil || got != "first" {
+		t.Fatalf("got %q, %v, want first", got, err)
+	}
+	writeFile(t, filepath.Dir(secret), "secret", "second", 0o600)
+	if got, err := password(ctx); err != nil || got != "second" {
+		t.Errorf("got %q, %v, want the rotated password", got, err)
+	}
+}
+
+func TestLoginTokenProviderRotatedPassword(t *testing.T) {
+	ctx := context.Background()
+	server := hubtest.NewServer()
+	defer server.Close()
+	// Tokens expire right away, so every call logs in again.
+	server.TokenTTL = -time.Minute
+	server.AddUser("alice", "first", "alice@example.com")
+
+	dir := t.TempDir()
+	passwordFile := writeFile(t, dir, "token", "first", 0o600)
+	provider := NewLoginTokenProvider("alice", PasswordFile(passwordFile), server.BaseURL(), http.DefaultTransport)
+	if _, err := provider.EnsureToken(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// The secret is rotated.
+	server.AddUser("alice", "second", "alice@example.com")
+	if _, err := provider.EnsureToken(ctx); err == nil {
+		t.Fatal("got no error logging in with the old password")
+	}
+	if err := os.WriteFile(passwordFile, []byte("second"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := provider.EnsureToken(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoginTokenProviderTwoFactor(t *testing.T) {
+	ctx := context.Background()
+	server := hubtest.NewServer()
+	defer server.Close()
+	const secret = "JBSWY3DPEHPK3PXP"
+	server.AddUser("admin", "secret", "admin@example.com")
+	server.EnableTwoFactor("admin", secret)
+
+	newProvider := func(otp OTPSource) *LoginTokenProvider {
+		provider := NewLoginTokenProvider("admin", StaticPassword("secret"), server.BaseURL(), http.DefaultTransport)
+		if otp != nil {
+			provider.SetOTPSource(otp)
+		}
+		return provider
+	}
+
+	if _, err := newProvider(nil).EnsureToken(ctx); err == nil || !strings.Contains(err.Error(), "two-factor authentication is enabled for admin") {
+		t.Fatalf("got error %v, want the missing second factor to be reported", err)
+	}
+
+	if _, err := newProvider(TOTP(secret)).EnsureToken(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	code, err := totp.Generate(secret, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newProvider(StaticOTP(code)).EnsureToken(ctx); err != nil {
+		t.Fatal(err)
+	}
+	wrong := "000000"
+	if totp.Validate(secret, wrong, time.Now()) {
+		wrong = "111111"
+	}
+	if _, err := newProvider(StaticOTP(wrong)).EnsureToken(ctx); err == nil || !strings.Contains(err.Error(), "Incorrect authentication code") {
+		t.Fatalf("got error %v, want the wrong code to be rejected", err)
+	}
+	if _, err := newProvider(TOTP("not base32!")).EnsureToken(ctx); err == nil || !strings.Contains(err.Error(), "get one-time code") {
+		t.Fatalf("got error %v, want the invalid secret to be reported", err)
+	}
+}
@@ -31,21 +31,23 @@ import (
 // The name of this struct was specifically chosen
 // to troll iam-team :)
 type LoginTokenProvider struct {
-	username    string
-	password    string
-	baseURL     string
-	httpClient  *http.Client
-	token       string
-	tokenExpiry time.Time
-	mu          sync.Mutex
+	tokenCache
+	username   string
+	password   PasswordSource
+	otp        OTPSource
+	baseURL    string
+	httpClient *http.Client
+	mu         sync.Mutex
 }
 
-// NewLoginTokenProvider creates a token provider that uses username/password
-func NewLoginTokenProvider(username, password, baseURL string, transport http.RoundTripper) *LoginTokenProvider {
+// NewLoginTokenProvider creates a token provider that uses username/password.
+// The password is read again for every login.
+func NewLoginTokenProvider(username string, password PasswordSource, baseURL string, transport http.RoundTripper) *LoginTokenProvider {
 	return &LoginTokenProvider{
-		username: username,
-		password: password,
-		baseURL:  baseURL,
+		tokenCache: newTokenCache(),
+		username:   username,
+		password:   password,
+		baseURL:    baseURL,
 		httpClient: &http.Client{
 			Timeout:   10 * time.Second,
 			Transport: transport,
@@ -60,51 +62,44 @@ func (p *LoginTokenProvider) EnsureToken(ctx context.Context) (string, error) {
 	defer p.mu.Unlock()
 
 	// Return cached token if still valid
-	if p.token != "" && time.Now().Before(p.tokenExpiry) {
-		return p.token, nil
+	if token, ok := p.cached(); ok {
+		return token, nil
 	}
 
-	// Request new token
-	auth := struct {
-		Username string `json:"username"`
-		Password string `json:"password"`
-	}{
-		Username: p.username,
-		Password: p.password,
-	}
-
-	authJSON, err := json.Marshal(auth)
+	password, err := p.password(ctx)
 	if err != nil {
-		return "", fmt.Errorf("marshal auth: %v", err)
+		return "", fmt.Errorf("get password: %v", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/users/login", p.baseURL), bytes.NewBuffer(authJSON))
+	// Request new token
+	tokenResponse, err := p.login(ctx, "/users/login", map[string]string{
+		"username": p.username,
+		"password": password,
+	})
 	if err != nil {
 		return "", err
 	}
-	req.Header.Set("Content-Type", "application/json")
-
-	res, err := p.httpClient.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("login request: %v", err)
-	}
-	defer res.Body.Close()
 
-	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusBadRequest {
-		// Read the response body to get more details on the error
-		body, _ := io.ReadAll(res.Body)
-		if len(body) > 0 {
-			return "", fmt.Errorf("login failed: %s - %s", res.Status, string(body))
+	// Accounts with two-factor authentication need to send a code along
+	// with the token of the first step.
+	if tokenResponse.Login2FAToken != "" {
+		if p.otp == nil {
+			return "", fmt.Errorf("login failed: two-factor authentication is enabled for %s, but no TOTP secret or one-time code is configured", p.username)
+		}
+		code, err := p.otp(ctx)
+		if err != nil {
+			return "", fmt.Errorf("get one-time code: %v", err)
+		}
+		tokenResponse, err = p.login(ctx, "/users/2fa-login", map[string]string{
+			"login_2fa_token": tokenResponse.Login2FAToken,
+			"code":            code,
+		})
+		if err != nil {
+			return "", err
 		}
-
-		return "", fmt.Errorf("login failed: %s", res.Status)
-	}
-
-	var tokenResponse struct {
-		Token string `json:"token"`
 	}
-	if err := json.NewDecoder(res.Body).Decode(&tokenResponse); err != nil {
-		return "", fmt.Errorf("decode token response: %v", err)
+	if tokenResponse.Token == "" {
+		return "", fmt.Errorf("login response does not contain a token")
 	}
 
 	// Parse token expiry
@@ -117,18 +112,79 @@ func (p *LoginTokenProvider) EnsureToken(ctx context.Context) (string, error) {
 	}
 
 	// Cache the token
-	p.token = tokenResponse.Token
-	p.tokenExpiry = claims.Expiry.Time()
+	p.store(tokenResponse.Token, claims.Expiry.Time())
 
-	return p.token, nil
+	return tokenResponse.Token, nil
 }
 
 func (p *LoginTokenProvider) Username() string {
 	return p.username
 }
 
-// AccessTokenProvider uses access tokens directly from the credential store
+// SetOTPSource sets where the one-time code is read from when Docker Hub
+// asks for a second factor.
+func (p *LoginTokenProvider) SetOTPSource(otp OTPSource) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.otp = otp
+}
+
+// loginResponse is the response of the login endpoints. Login2FAToken is set
+// instead of Token when a second factor is required.
+type loginResponse struct {
+	Token         string `json:"token"`
+	Login2FAToken string `json:"login_2fa_token"`
+}
+
+// login sends credentials to a login endpoint of the Docker Hub API.
+func (p *LoginTokenProvider) login(ctx context.Context, path string, credentials map[string]string) (loginResponse, error) {
+	authJSON, err := json.Marshal(credentials)
+	if err != nil {
+		return loginResponse{}, fmt.Errorf("marshal auth: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+path, bytes.NewBuffer(authJSON))
+	if err != nil {
+		return loginResponse{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := p.httpClient.Do(req)
+	if err != nil {
+		return loginResponse{}, fmt.Errorf("login request: %v", err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return loginResponse{}, fmt.Errorf("read login response: %v", err)
+	}
+
+	var tokenResponse loginResponse
+	decodeErr := json.Unmarshal(body, &tokenResponse)
+
+	// The second factor is asked for with a 401 response.
+	if res.StatusCode == http.StatusUnauthorized && decodeErr == nil && tokenResponse.Login2FAToken != "" {
+		return tokenResponse, nil
+	}
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusBadRequest {
+		if len(body) > 0 {
+			return loginResponse{}, fmt.Errorf("login failed: %s - %s", res.Status, string(body))
+		}
+
+		return loginResponse{}, fmt.Errorf("login failed: %s", res.Status)
+	}
+	if decodeErr != nil {
+		return loginResponse{}, fmt.Errorf("decode token response: %v", decodeErr)
+	}
+	return tokenResponse, nil
+}
+
+// AccessTokenProvider uses access tokens directly from the credential store.
+// Docker Desktop refreshes the token in the store, so it is read again when
+// it is due for a refresh.
 type AccessTokenProvider struct {
+	tokenCache
 	configKey      string
 	cachedUsername string
 	configStore    *ConfigStore
@@ -138,6 +194,7 @@ type AccessTokenProvider struct {
 // NewAccessTokenProvider creates a token provider that uses access tokens from the credential store
 func NewAccessTokenProvider(configStore *ConfigStore, configKey string) *AccessTokenProvider {
 	return &AccessTokenProvider{
+		tokenCache:  newTokenCache(),
 		configKey:   configKey,
 		configStore: configStore,
 	}
@@ -147,11 +204,23 @@ func (p *AccessTokenProvider) EnsureToken(ctx context.Context) (string, error) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
-	// Always get fresh access token from store (no caching for access tokens)
+	// Return cached token if still valid
+	if token, ok := p.cached(); ok {
+		return token, nil
+	}
+
+	// Read the access token from the store again, which Docker Desktop may
+	// have refreshed meanwhile. A token that is due for a refresh is still
+	// used as long as it is valid.
 	username, accessToken, err := p.configStore.GetCredentialStoreAccessTokens(p.configKey)
 	if err != nil {
 		return "", fmt.Errorf("get access token from store: %v", err)
 	}
+	claims, err := getClaims(accessToken)
+	if err != nil {
+		return "", fmt.Errorf("parse token claims: %v", err)
+	}
+	p.store(accessToken, claims.Expiry.Time())
 
 	// Cache username for display purposes
 	p.cachedUsername = username
@@ -194,5 +263,5 @@ func NewLoginTokenProviderFromStore(configStore *ConfigStore, configKey, baseURL
 		return nil, fmt.Errorf("empty password found in store")
 	}
 
-	return NewLoginTokenProvider(username, password, baseURL, transport), nil
+	return NewLoginTokenProvider(username, StaticPassword(password), baseURL, transport), nil
 }
@@ -23,11 +23,20 @@ import "os"
 // Acceptance test related environment variables
 const (
 	AccTestOrganization = "ACCTEST_DOCKER_ORG"
+
+	// AccTestFakeHub runs the acceptance tests against an in-memory fake of
+	// Docker Hub instead of a real account when set to a non-empty value.
+	AccTestFakeHub = "ACCTEST_FAKE_HUB"
+
+	// AccTestRegistry is the registry the acceptance tests pull images
+	// from, set to a fake registry along with AccTestFakeHub.
+	AccTestRegistry = "ACCTEST_REGISTRY"
 )
 
 // defaults is a map of pre-configured defaults for each envvar
 var defaults = map[string]string{
 	AccTestOrganization: "dockerterraform",
+	AccTestRegistry:     "docker.io",
 }
 
 // GetWithDefault returns the value of the environment variable or the
@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -29,12 +30,18 @@ import (
 	"github.com/hashicorp/go-retryablehttp"
 )
 
+// maxPageSize is the largest page size Docker Hub accepts on list endpoints.
+const maxPageSize = 100
+
 // TokenProvider provides a valid authentication token for API requests
 type TokenProvider interface {
 	// EnsureToken returns a valid token, refreshing if necessary
 	EnsureToken(ctx context.Context) (token string, err error)
 	// Username returns the username associated with this provider (for display purposes)
 	Username() string
+	// InvalidateToken drops token if it is still cached, after Docker Hub
+	// rejected it
+	InvalidateToken(token string)
 }
 
 type Client struct {
@@ -49,15 +56,40 @@ type Config struct {
 	TokenProvider  TokenProvider
 	Transport      http.RoundTripper
 	MaxPageResults int64
+	Retry          RetryConfig
+	// RequestsPerSecond limits the client-side request rate. Zero means
+	// unlimited.
+	RequestsPerSecond float64
 }
 
 func NewClient(config Config) *Client {
+	transport := config.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	if config.RequestsPerSecond > 0 {
+		transport = &rateLimitedTransport{
+			limiter:   newTokenBucket(config.RequestsPerSecond),
+			transport: transport,
+		}
+	}
+
 	baseClient := &http.Client{
 		Timeout:   time.Minute,
-		Transport: config.Transport,
+		Transport: transport,
 	}
+
+	retry := config.Retry.withDefaults()
 	retryClient := retryablehttp.NewClient()
 	retryClient.HTTPClient = baseClient
+	retryClient.RetryMax = retry.MaxAttempts - 1
+	retryClient.RetryWaitMin = retry.MinBackoff
+	retryClient.RetryWaitMax = retry.MaxBackoff
+	retryClient.CheckRetry = retryPolicy(retry.MaxBackoff)
+	retryClient.Backoff = backoff
+	// Hand the last response back to sendRequest once we give up, so callers
+	// get an APIError they can inspect instead of a generic retry error.
+	retryClient.ErrorHandler = retryablehttp.PassthroughErrorHandler
 
 	return &Client{
 		BaseURL:        config.BaseURL,
@@ -68,25 +100,19 @@ func NewClient(config Config) *Client {
 }
 
 func (c *Client) sendRequest(ctx context.Context, method string, url string, body []byte, result interface{}) error {
-	token, err := c.tokenProvider.EnsureToken(ctx)
-	if err != nil {
-		return err
-	}
-
 	path := fmt.Sprintf("%s%s", c.BaseURL, url)
-	req, err := http.NewRequest(method, path, bytes.NewBuffer(body))
+	res, token, err := c.send(ctx, method, path, body)
 	if err != nil {
 		return err
 	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
-
-	req = req.WithContext(ctx)
-
-	res, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return err
+	if res.StatusCode == http.StatusUnauthorized {
+		// The token may have been revoked or expired early, replay the
+		// request once with a new one.
+		res.Body.Close()
+		c.tokenProvider.InvalidateToken(token)
+		if res, _, err = c.send(ctx, method, path, body); err != nil {
+			return err
+		}
 	}
 
 	defer res.Body.Close()
@@ -97,12 +123,7 @@ func (c *Client) sendRequest(ctx context.Context, method string, url string, bod
 			return readErr
 		}
 
-		// Limit the size of the error message body to avoid excessive logs
-		if len(bodyBytes) > 500 {
-			bodyBytes = bodyBytes[:500]
-		}
-
-		return fmt.Errorf("server response %s: %s", path, string(bodyBytes))
+		return newAPIError(method, path, res.StatusCode, bodyBytes)
 	}
 
 	if result != nil {
@@ -114,33 +135,59 @@ func (c *Client) sendRequest(ctx context.Context, method string, url string, bod
 	return nil
 }
 
+// send sends a request authenticated with the current token, and returns the
+// response along with the token.
+func (c *Client) send(ctx context.Context, method, path string, body []byte) (*http.Response, string, error) {
+	token, err := c.tokenProvider.EnsureToken(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, path, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+
+	res, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	return res, token, nil
+}
+
 // convertToRelativeURL converts full URLs to relative paths for sendRequest
 func (c *Client) convertToRelativeURL(url string) string {
-	if strings.HasPrefix(url, "https://") {
+	if strings.HasPrefix(url, c.BaseURL) {
 		return strings.TrimPrefix(url, c.BaseURL)
 	}
 	return url
 }
 
-func (c *Client) paginate(ctx context.Context, initialURL string, processPage func(string) (interface{}, error)) error {
+// paginate calls processPage for every page of a listing, following the next
+// URL it returns. It stops after maxPageResults pages and reports whether
+// there were more pages left.
+func (c *Client) paginate(ctx context.Context, initialURL string, processPage func(string) (interface{}, error)) (truncated bool, err error) {
 	nextURL := initialURL
 	pagesFetched := 0
 
 	for nextURL != "" {
+		// If maxPageResults is set to 0, we will fetch all pages
+		if c.maxPageResults > 0 && int64(pagesFetched) >= c.maxPageResults {
+			return true, nil
+		}
+
 		relativeURL := c.convertToRelativeURL(nextURL)
 
 		next, err := processPage(relativeURL)
 		if err != nil {
-			return err
+			return false, err
 		}
 
 		pagesFetched++
 
-		// If maxPageResults is set to 0, we will fetch all pages
-		if c.maxPageResults > 0 && int64(pagesFetched) >= c.maxPageResults {
-			break
-		}
-
 		nextURL = ""
 		if next != nil {
 			if nextStr, ok := next.(string); ok {
@@ -149,7 +196,25 @@ func (c *Client) paginate(ctx context.Context, initialURL string, processPage fu
 		}
 	}
 
-	return nil
+	return false, nil
+}
+
+// TruncatedError is returned by listings that have to be complete to be
+// useful, when they have more pages than MaxPageResults allows.
+type TruncatedError struct {
+	// Path is the path of the first page of the listing.
+	Path           string
+	MaxPageResults int64
+}
+
+func (e *TruncatedError) Error() string {
+	return fmt.Sprintf("listing %s returned more than %d pages of results", e.Path, e.MaxPageResults)
+}
+
+// IsTruncated reports whether err is a TruncatedError.
+func IsTruncated(err error) bool {
+	var truncatedErr *TruncatedError
+	return errors.As(err, &truncatedErr)
 }
 
 func (c *Client) Username() string {
@@ -69,7 +69,7 @@ func (c *Client) GetAccessTokens(ctx context.Context) (AccessTokenPage, error) {
 	var allTokens []AccessToken
 	initialURL := "/access-tokens"
 
-	err := c.paginate(ctx, initialURL, func(url string) (interface{}, error) {
+	_, err := c.paginate(ctx, initialURL, func(url string) (interface{}, error) {
 		var page AccessTokenPage
 		if err := c.sendRequest(ctx, "GET", url, nil, &page); err != nil {
 			return nil, err
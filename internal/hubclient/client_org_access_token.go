@@ -58,6 +58,15 @@ type OrgAccessTokenUpdateParams struct {
 	Label       string                   `json:"label"`
 	Description string                   `json:"description"`
 	Resources   []OrgAccessTokenResource `json:"resources"`
+	// IsActive activates or deactivates the token, nil leaves it as is.
+	IsActive *bool `json:"is_active,omitempty"`
+}
+
+type OrgAccessTokenListResponse struct {
+	Total    int              `json:"total"`
+	Previous string           `json:"previous"`
+	Next     string           `json:"next"`
+	Results  []OrgAccessToken `json:"results"`
 }
 
 func (c *Client) CreateOrgAccessToken(ctx context.Context, orgName string, params OrgAccessTokenCreateParams) (OrgAccessToken, error) {
@@ -88,6 +97,35 @@ func (c *Client) GetOrgAccessToken(ctx context.Context, orgName, accessTokenID s
 	return accessToken, err
 }
 
+// ListOrgAccessTokens returns the access tokens of an organization. Tokens in
+// the list do not include their secret.
+func (c *Client) ListOrgAccessTokens(ctx context.Context, orgName string) ([]OrgAccessToken, error) {
+	if orgName == "" {
+		return nil, fmt.Errorf("orgName is required")
+	}
+
+	var accessTokens []OrgAccessToken
+	initialURL := fmt.Sprintf("/orgs/%s/access-tokens", orgName)
+	truncated, err := c.paginate(ctx, initialURL, func(url string) (interface{}, error) {
+		var page OrgAccessTokenListResponse
+		if err := c.sendRequest(ctx, "GET", url, nil, &page); err != nil {
+			return nil, err
+		}
+
+		accessTokens = append(accessTokens, page.Results...)
+		return page.Next, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	// The tokens are filtered and sorted by the caller, a partial list would
+	// silently miss the tokens on the missing pages.
+	if truncated {
+		return nil, &TruncatedError{Path: initialURL, MaxPageResults: c.maxPageResults}
+	}
+	return accessTokens, nil
+}
+
 func (c *Client) UpdateOrgAccessToken(ctx context.Context, orgName, accessTokenID string, params OrgAccessTokenUpdateParams) (OrgAccessToken, error) {
 	if orgName == "" {
 		return OrgAccessToken{}, fmt.Errorf("orgName is required")
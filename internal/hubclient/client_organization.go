@@ -186,7 +186,7 @@ func (c *Client) GetOrg(ctx context.Context, orgName string) (Org, error) {
 func (c *Client) ListOrgMembers(ctx context.Context, orgName string) ([]OrgMember, error) {
 	var members []OrgMember
 	initialURL := fmt.Sprintf("/orgs/%s/members", orgName)
-	err := c.paginate(ctx, initialURL, func(url string) (interface{}, error) {
+	_, err := c.paginate(ctx, initialURL, func(url string) (interface{}, error) {
 		var page OrgMemberListResponse
 		if err := c.sendRequest(ctx, "GET", url, nil, &page); err != nil {
 			return nil, err
@@ -257,8 +257,10 @@ func (c *Client) AddOrgTeamMember(ctx context.Context, orgName string, teamName
 
 func (c *Client) ListOrgTeamMembers(ctx context.Context, orgName string, teamName string) ([]OrgTeamMember, error) {
 	var members []OrgTeamMember
-	initialURL := fmt.Sprintf("/orgs/%s/groups/%s/members/", orgName, teamName)
-	err := c.paginate(ctx, initialURL, func(url string) (interface{}, error) {
+	// Teams can have thousands of members, ask for the largest page Docker
+	// Hub allows to keep the number of requests down.
+	initialURL := fmt.Sprintf("/orgs/%s/groups/%s/members/?page_size=%d", orgName, teamName, maxPageSize)
+	truncated, err := c.paginate(ctx, initialURL, func(url string) (interface{}, error) {
 		var page OrgTeamMembersResponse
 		if err := c.sendRequest(ctx, "GET", url, nil, &page); err != nil {
 			return nil, err
@@ -270,6 +272,11 @@ func (c *Client) ListOrgTeamMembers(ctx context.Context, orgName string, teamNam
 	if err != nil {
 		return nil, err
 	}
+	// Memberships are checked against this list, a partial one would make
+	// members on the missing pages look like they left the team.
+	if truncated {
+		return nil, &TruncatedError{Path: initialURL, MaxPageResults: c.maxPageResults}
+	}
 	return members, nil
 }
 
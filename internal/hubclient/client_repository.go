@@ -20,6 +20,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/url"
+	"strconv"
 )
 
 const ImmutableTagRulesSeparator = ","
@@ -81,6 +83,18 @@ type TeamRepoPermission struct {
 	Permission string `json:"permission"`
 }
 
+type TeamRepoPermissions struct {
+	Count    int                  `json:"count"`
+	Next     interface{}          `json:"next,omitempty"`
+	Previous interface{}          `json:"previous,omitempty"`
+	Results  []TeamRepoPermission `json:"results"`
+}
+
+type RepositoryCollaborator struct {
+	User       string `json:"user"`
+	Permission string `json:"permission"`
+}
+
 type Tag struct {
 	Name            string     `json:"name"`
 	FullSize        int64      `json:"full_size"`
@@ -120,6 +134,9 @@ type Tags struct {
 	Next     interface{} `json:"next,omitempty"`
 	Previous interface{} `json:"previous,omitempty"`
 	Results  []Tag       `json:"results"`
+	// Truncated is set by ListRepositoryTags when the repository has more
+	// pages of tags than MaxPageResults allows.
+	Truncated bool `json:"-"`
 }
 
 type CreateRepositoryRequest struct {
@@ -187,16 +204,61 @@ func (c *Client) DeleteRepository(ctx context.Context, id string) error {
 }
 
 func (c *Client) GetRepositoryTags(ctx context.Context, namespace, name string) (*Tags, error) {
+	return c.ListRepositoryTags(ctx, namespace, name, ListRepositoryTagsParams{})
+}
+
+// Orderings supported by the tag list endpoint.
+const (
+	TagOrderingLastUpdated = "-last_updated"
+	TagOrderingName        = "name"
+)
+
+type ListRepositoryTagsParams struct {
+	// Name only returns tags whose name contains this string.
+	Name string
+	// Ordering is one of the TagOrdering constants. Docker Hub lists the
+	// most recently updated tags first by default.
+	Ordering string
+	// Match, if set, drops the tags for which it returns false.
+	Match func(Tag) bool
+	// Limit stops paging once this many tags have been fetched. Zero fetches
+	// all pages, up to the client's max page results.
+	Limit int
+}
+
+// ListRepositoryTags lists the tags of a repository, letting Docker Hub do
+// the filtering and ordering it supports.
+func (c *Client) ListRepositoryTags(ctx context.Context, namespace, name string, params ListRepositoryTagsParams) (*Tags, error) {
 	var allTags []Tag
-	initialURL := fmt.Sprintf("/namespaces/%s/repositories/%s/tags", namespace, name)
+	query := url.Values{}
+	pageSize := maxPageSize
+	if params.Limit > 0 && params.Limit < pageSize && params.Match == nil {
+		pageSize = params.Limit
+	}
+	query.Set("page_size", strconv.Itoa(pageSize))
+	if params.Name != "" {
+		query.Set("name", params.Name)
+	}
+	if params.Ordering != "" {
+		query.Set("ordering", params.Ordering)
+	}
+	initialURL := fmt.Sprintf("/namespaces/%s/repositories/%s/tags?%s", namespace, name, query.Encode())
 
-	err := c.paginate(ctx, initialURL, func(url string) (interface{}, error) {
+	truncated, err := c.paginate(ctx, initialURL, func(url string) (interface{}, error) {
 		var page Tags
 		if err := c.sendRequest(ctx, "GET", url, nil, &page); err != nil {
 			return nil, err
 		}
 
-		allTags = append(allTags, page.Results...)
+		for _, tag := range page.Results {
+			if params.Match == nil || params.Match(tag) {
+				allTags = append(allTags, tag)
+			}
+		}
+		if params.Limit > 0 && len(allTags) >= params.Limit {
+			allTags = allTags[:params.Limit]
+			return nil, nil
+		}
 		return page.Next, nil
 	})
 	if err != nil {
@@ -204,8 +266,9 @@ func (c *Client) GetRepositoryTags(ctx context.Context, namespace, name string)
 	}
 
 	return &Tags{
-		Count:   len(allTags),
-		Results: allTags,
+		Count:     len(allTags),
+		Results:   allTags,
+		Truncated: truncated,
 	}, nil
 }
 
@@ -216,11 +279,16 @@ func (c *Client) GetRepositoryTag(ctx context.Context, namespace string, reposit
 	return tagInfo, err
 }
 
+func (c *Client) DeleteRepositoryTag(ctx context.Context, namespace string, repository string, tag string) error {
+	url := fmt.Sprintf("/namespaces/%s/repositories/%s/tags/%s", namespace, repository, tag)
+	return c.sendRequest(ctx, "DELETE", url, nil, nil)
+}
+
 func (c *Client) GetRepositories(ctx context.Context, namespace string) (Repositories, error) {
 	var allRepos []Repository
 	initialURL := fmt.Sprintf("/repositories/%s/", namespace)
 
-	err := c.paginate(ctx, initialURL, func(url string) (interface{}, error) {
+	_, err := c.paginate(ctx, initialURL, func(url string) (interface{}, error) {
 		var page Repositories
 		if err := c.sendRequest(ctx, "GET", url, nil, &page); err != nil {
 			return nil, err
@@ -255,6 +323,36 @@ func (c *Client) CreatePermissionForTeamAndRepo(ctx context.Context, repository
 	return created, err
 }
 
+// ListPermissionsForRepo lists the permissions of all the teams that have
+// access to a repository.
+func (c *Client) ListPermissionsForRepo(ctx context.Context, repository string) (TeamRepoPermissions, error) {
+	var allPermissions []TeamRepoPermission
+	initialURL := fmt.Sprintf("/repositories/%s/groups/", repository)
+
+	truncated, err := c.paginate(ctx, initialURL, func(url string) (interface{}, error) {
+		var page TeamRepoPermissions
+		if err := c.sendRequest(ctx, "GET", url, nil, &page); err != nil {
+			return nil, err
+		}
+
+		allPermissions = append(allPermissions, page.Results...)
+		return page.Next, nil
+	})
+	if err != nil {
+		return TeamRepoPermissions{}, err
+	}
+	// Grants are reconciled against this list, a partial one would hide the
+	// grants on the missing pages.
+	if truncated {
+		return TeamRepoPermissions{}, &TruncatedError{Path: initialURL, MaxPageResults: c.maxPageResults}
+	}
+
+	return TeamRepoPermissions{
+		Count:   len(allPermissions),
+		Results: allPermissions,
+	}, nil
+}
+
 func (c *Client) GetPermissionForTeamAndRepo(ctx context.Context, repository string, teamID int64) (TeamRepoPermission, error) {
 	perm := TeamRepoPermission{}
 	err := c.sendRequest(ctx, "GET", fmt.Sprintf("/repositories/%s/groups/%v/", repository, teamID), nil, &perm)
@@ -279,3 +377,38 @@ func (c *Client) UpdatePermissionForTeamAndRepo(ctx context.Context, repository
 func (c *Client) DeletePermissionForTeamAndRepo(ctx context.Context, repository string, teamID int64) error {
 	return c.sendRequest(ctx, "DELETE", fmt.Sprintf("/repositories/%s/groups/%v/", repository, teamID), nil, nil)
 }
+
+func (c *Client) CreateRepositoryCollaborator(ctx context.Context, repository string, userName string, permission string) (RepositoryCollaborator, error) {
+	created := RepositoryCollaborator{}
+	body, err := json.Marshal(&RepositoryCollaborator{
+		User:       userName,
+		Permission: permission,
+	})
+	if err != nil {
+		return created, err
+	}
+	err = c.sendRequest(ctx, "POST", fmt.Sprintf("/repositories/%s/collaborators/", repository), body, &created)
+	return created, err
+}
+
+func (c *Client) GetRepositoryCollaborator(ctx context.Context, repository string, userName string) (RepositoryCollaborator, error) {
+	collaborator := RepositoryCollaborator{}
+	err := c.sendRequest(ctx, "GET", fmt.Sprintf("/repositories/%s/collaborators/%s/", repository, userName), nil, &collaborator)
+	return collaborator, err
+}
+
+func (c *Client) UpdateRepositoryCollaborator(ctx context.Context, repository string, userName string, permission string) (RepositoryCollaborator, error) {
+	updated := RepositoryCollaborator{}
+	body, err := json.Marshal(&RepositoryCollaborator{
+		Permission: permission,
+	})
+	if err != nil {
+		return updated, err
+	}
+	err = c.sendRequest(ctx, "PATCH", fmt.Sprintf("/repositories/%s/collaborators/%s/", repository, userName), body, &updated)
+	return updated, err
+}
+
+func (c *Client) DeleteRepositoryCollaborator(ctx context.Context, repository string, userName string) error {
+	return c.sendRequest(ctx, "DELETE", fmt.Sprintf("/repositories/%s/collaborators/%s/", repository, userName), nil, nil)
+}
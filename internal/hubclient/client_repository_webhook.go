@@ -0,0 +1,137 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hubclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// DefaultWebhookRegistry is the registry Docker Hub assigns to webhooks
+// created without one.
+const DefaultWebhookRegistry = "registry-1.docker.io"
+
+// RepositoryWebhook is a webhook pipeline of a repository. Docker Hub models
+// a webhook as a named pipeline of one or more hooks; the provider manages
+// pipelines with a single hook.
+type RepositoryWebhook struct {
+	ID                  int64                   `json:"id,omitempty"`
+	Name                string                  `json:"name"`
+	Slug                string                  `json:"slug,omitempty"`
+	ExpectFinalCallback bool                    `json:"expect_final_callback"`
+	Registry            string                  `json:"registry,omitempty"`
+	Webhooks            []RepositoryWebhookHook `json:"webhooks"`
+	Creator             string                  `json:"creator,omitempty"`
+	Created             string                  `json:"created,omitempty"`
+	LastUpdated         string                  `json:"last_updated,omitempty"`
+}
+
+// URL returns the URL of the first hook of the pipeline.
+func (w RepositoryWebhook) URL() string {
+	if len(w.Webhooks) == 0 {
+		return ""
+	}
+	return w.Webhooks[0].HookURL
+}
+
+type RepositoryWebhookHook struct {
+	ID      int64  `json:"id,omitempty"`
+	Name    string `json:"name"`
+	HookURL string `json:"hook_url"`
+}
+
+type RepositoryWebhooks struct {
+	Count    int                 `json:"count"`
+	Next     interface{}         `json:"next,omitempty"`
+	Previous interface{}         `json:"previous,omitempty"`
+	Results  []RepositoryWebhook `json:"results"`
+}
+
+type RepositoryWebhookParams struct {
+	Name                string                  `json:"name,omitempty"`
+	ExpectFinalCallback bool                    `json:"expect_final_callback"`
+	Registry            string                  `json:"registry,omitempty"`
+	Webhooks            []RepositoryWebhookHook `json:"webhooks"`
+}
+
+// NewRepositoryWebhookParams builds the parameters of a pipeline with a
+// single hook named after the pipeline.
+func NewRepositoryWebhookParams(name, url string, expectFinalCallback bool, registry string) RepositoryWebhookParams {
+	return RepositoryWebhookParams{
+		Name:                name,
+		ExpectFinalCallback: expectFinalCallback,
+		Registry:            registry,
+		Webhooks: []RepositoryWebhookHook{{
+			Name:    name,
+			HookURL: url,
+		}},
+	}
+}
+
+func (c *Client) ListRepositoryWebhooks(ctx context.Context, repository string) (RepositoryWebhooks, error) {
+	var allWebhooks []RepositoryWebhook
+	initialURL := fmt.Sprintf("/repositories/%s/webhook_pipeline/", repository)
+
+	_, err := c.paginate(ctx, initialURL, func(url string) (interface{}, error) {
+		var page RepositoryWebhooks
+		if err := c.sendRequest(ctx, "GET", url, nil, &page); err != nil {
+			return nil, err
+		}
+
+		allWebhooks = append(allWebhooks, page.Results...)
+		return page.Next, nil
+	})
+	if err != nil {
+		return RepositoryWebhooks{}, err
+	}
+
+	return RepositoryWebhooks{
+		Count:   len(allWebhooks),
+		Results: allWebhooks,
+	}, nil
+}
+
+func (c *Client) GetRepositoryWebhook(ctx context.Context, repository string, slug string) (RepositoryWebhook, error) {
+	webhook := RepositoryWebhook{}
+	err := c.sendRequest(ctx, "GET", fmt.Sprintf("/repositories/%s/webhook_pipeline/%s/", repository, slug), nil, &webhook)
+	return webhook, err
+}
+
+func (c *Client) CreateRepositoryWebhook(ctx context.Context, repository string, params RepositoryWebhookParams) (RepositoryWebhook, error) {
+	webhook := RepositoryWebhook{}
+	body, err := json.Marshal(params)
+	if err != nil {
+		return webhook, err
+	}
+	err = c.sendRequest(ctx, "POST", fmt.Sprintf("/repositories/%s/webhook_pipeline/", repository), body, &webhook)
+	return webhook, err
+}
+
+func (c *Client) UpdateRepositoryWebhook(ctx context.Context, repository string, slug string, params RepositoryWebhookParams) (RepositoryWebhook, error) {
+	webhook := RepositoryWebhook{}
+	body, err := json.Marshal(params)
+	if err != nil {
+		return webhook, err
+	}
+	err = c.sendRequest(ctx, "PATCH", fmt.Sprintf("/repositories/%s/webhook_pipeline/%s/", repository, slug), body, &webhook)
+	return webhook, err
+}
+
+func (c *Client) DeleteRepositoryWebhook(ctx context.Context, repository string, slug string) error {
+	return c.sendRequest(ctx, "DELETE", fmt.Sprintf("/repositories/%s/webhook_pipeline/%s/", repository, slug), nil, nil)
+}
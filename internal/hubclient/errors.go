@@ -0,0 +1,153 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hubclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// maxErrorBodySize limits how much of an error response body we keep, to
+// avoid excessive logs.
+const maxErrorBodySize = 500
+
+// APIError is returned by the client for any non-2xx response from Docker Hub.
+type APIError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// Method is the HTTP method of the failed request.
+	Method string
+	// Path is the request URL, including the base URL.
+	Path string
+	// Code is the Hub error code, if the response contained one.
+	Code string
+	// Message is the human readable error message parsed from the response.
+	Message string
+	// Body is the raw (truncated) response body.
+	Body string
+}
+
+func (e *APIError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = e.Body
+	}
+	if e.Code != "" {
+		return fmt.Sprintf("server response %s %s: %d %s: %s", e.Method, e.Path, e.StatusCode, e.Code, msg)
+	}
+	return fmt.Sprintf("server response %s %s: %d: %s", e.Method, e.Path, e.StatusCode, msg)
+}
+
+// newAPIError builds an APIError from a response body. Docker Hub is not
+// consistent in its error format, so we try the shapes we know about:
+//
+//	{"message": "...", "errinfo": {...}}
+//	{"detail": "..."}
+//	{"code": "...", "message": "..."}
+//	{"errors": [{"code": "...", "message": "..."}]}
+func newAPIError(method, path string, statusCode int, body []byte) *APIError {
+	if len(body) > maxErrorBodySize {
+		body = body[:maxErrorBodySize]
+	}
+
+	apiErr := &APIError{
+		StatusCode: statusCode,
+		Method:     method,
+		Path:       path,
+		Body:       string(body),
+	}
+
+	var parsed struct {
+		Code    interface{} `json:"code"`
+		Message string      `json:"message"`
+		Detail  string      `json:"detail"`
+		Error   string      `json:"error"`
+		Errors  []struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		apiErr.Message = strings.TrimSpace(string(body))
+		return apiErr
+	}
+
+	switch {
+	case parsed.Message != "":
+		apiErr.Message = parsed.Message
+	case parsed.Detail != "":
+		apiErr.Message = parsed.Detail
+	case parsed.Error != "":
+		apiErr.Message = parsed.Error
+	case len(parsed.Errors) > 0:
+		apiErr.Message = parsed.Errors[0].Message
+	}
+
+	switch code := parsed.Code.(type) {
+	case string:
+		apiErr.Code = code
+	case float64:
+		apiErr.Code = fmt.Sprintf("%d", int64(code))
+	}
+	if apiErr.Code == "" && len(parsed.Errors) > 0 {
+		apiErr.Code = parsed.Errors[0].Code
+	}
+
+	return apiErr
+}
+
+// AsAPIError returns the APIError wrapped in err, if any.
+func AsAPIError(err error) (*APIError, bool) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr, true
+	}
+	return nil, false
+}
+
+func hasStatus(err error, statusCode int) bool {
+	apiErr, ok := AsAPIError(err)
+	return ok && apiErr.StatusCode == statusCode
+}
+
+// IsNotFound reports whether err is a 404 response from Docker Hub.
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsUnauthorized reports whether err is a 401 response from Docker Hub.
+func IsUnauthorized(err error) bool {
+	return hasStatus(err, http.StatusUnauthorized)
+}
+
+// IsForbidden reports whether err is a 403 response from Docker Hub.
+func IsForbidden(err error) bool {
+	return hasStatus(err, http.StatusForbidden)
+}
+
+// IsConflict reports whether err is a 409 response from Docker Hub.
+func IsConflict(err error) bool {
+	return hasStatus(err, http.StatusConflict)
+}
+
+// IsRateLimited reports whether err is a 429 response from Docker Hub.
+func IsRateLimited(err error) bool {
+	return hasStatus(err, http.StatusTooManyRequests)
+}
@@ -0,0 +1,111 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hubclient
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestNewAPIError(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		wantCode    string
+		wantMessage string
+	}{
+		{name: "message", body: `{"message": "repository not found", "errinfo": {"namespace": "docker"}}`, wantMessage: "repository not found"},
+		{name: "detail", body: `{"detail": "Not found."}`, wantMessage: "Not found."},
+		{name: "error", body: `{"error": "invalid token"}`, wantMessage: "invalid token"},
+		{name: "string code", body: `{"code": "token_expired", "message": "token is expired"}`, wantCode: "token_expired", wantMessage: "token is expired"},
+		{name: "numeric code", body: `{"code": 4001, "message": "bad request"}`, wantCode: "4001", wantMessage: "bad request"},
+		{name: "errors list", body: `{"errors": [{"code": "UNAUTHORIZED", "message": "authentication required"}, {"code": "OTHER", "message": "ignored"}]}`, wantCode: "UNAUTHORIZED", wantMessage: "authentication required"},
+		{name: "message wins over detail", body: `{"message": "first", "detail": "second"}`, wantMessage: "first"},
+		{name: "plain text", body: "  upstream connect error\n", wantMessage: "upstream connect error"},
+		{name: "empty", body: "", wantMessage: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiErr := newAPIError(http.MethodGet, "https://hub.docker.com/v2/repositories/docker/x", http.StatusBadRequest, []byte(tt.body))
+			if apiErr.Code != tt.wantCode {
+				t.Errorf("got code %q, want %q", apiErr.Code, tt.wantCode)
+			}
+			if apiErr.Message != tt.wantMessage {
+				t.Errorf("got message %q, want %q", apiErr.Message, tt.wantMessage)
+			}
+			if apiErr.Body != tt.body {
+				t.Errorf("got body %q, want %q", apiErr.Body, tt.body)
+			}
+		})
+	}
+}
+
+func TestNewAPIErrorTruncatesBody(t *testing.T) {
+	body := strings.Repeat("x", 2*maxErrorBodySize)
+	apiErr := newAPIError(http.MethodGet, "/", http.StatusInternalServerError, []byte(body))
+	if len(apiErr.Body) != maxErrorBodySize {
+		t.Errorf("got body of %d bytes, want %d", len(apiErr.Body), maxErrorBodySize)
+	}
+}
+
+func TestAPIErrorError(t *testing.T) {
+	apiErr := &APIError{StatusCode: http.StatusNotFound, Method: http.MethodGet, Path: "/v2/x", Code: "not_found", Message: "missing"}
+	if got, want := apiErr.Error(), "server response GET /v2/x: 404 not_found: missing"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	apiErr = &APIError{StatusCode: http.StatusBadGateway, Method: http.MethodPost, Path: "/v2/x", Body: "bad gateway"}
+	if got, want := apiErr.Error(), "server response POST /v2/x: 502: bad gateway"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestIsStatus(t *testing.T) {
+	is := map[string]func(error) bool{
+		"IsNotFound":     IsNotFound,
+		"IsUnauthorized": IsUnauthorized,
+		"IsForbidden":    IsForbidden,
+		"IsConflict":     IsConflict,
+		"IsRateLimited":  IsRateLimited,
+	}
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "not found", err: &APIError{StatusCode: http.StatusNotFound}, want: "IsNotFound"},
+		{name: "unauthorized", err: &APIError{StatusCode: http.StatusUnauthorized}, want: "IsUnauthorized"},
+		{name: "forbidden", err: &APIError{StatusCode: http.StatusForbidden}, want: "IsForbidden"},
+		{name: "conflict", err: &APIError{StatusCode: http.StatusConflict}, want: "IsConflict"},
+		{name: "rate limited", err: &APIError{StatusCode: http.StatusTooManyRequests}, want: "IsRateLimited"},
+		{name: "wrapped", err: fmt.Errorf("reading repository: %w", &APIError{StatusCode: http.StatusNotFound}), want: "IsNotFound"},
+		{name: "other status", err: &APIError{StatusCode: http.StatusInternalServerError}},
+		{name: "not an API error", err: errors.New("connection refused")},
+		{name: "nil", err: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, fn := range is {
+				if got := fn(tt.err); got != (name == tt.want) {
+					t.Errorf("%s: got %v, want %v", name, got, name == tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hubclient
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// CompileTagPattern compiles a tag pattern the way Docker Hub evaluates
+// immutable tag rules: as an RE2 regular expression that must match the
+// whole tag.
+func CompileTagPattern(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, fmt.Errorf("pattern must not be empty")
+	}
+	re, err := regexp.Compile(`^(?:` + pattern + `)$`)
+	if err != nil {
+		// Report the error against the pattern as written, not the anchored form.
+		if _, rawErr := regexp.Compile(pattern); rawErr != nil {
+			err = rawErr
+		}
+		return nil, fmt.Errorf("pattern %q is not a valid regular expression: %w", pattern, err)
+	}
+	return re, nil
+}
+
+// CompileImmutableTagRule compiles an immutable tag rule with
+// CompileTagPattern.
+func CompileImmutableTagRule(rule string) (*regexp.Regexp, error) {
+	// Rules are sent to Docker Hub as a single separated string, so a rule
+	// containing the separator would silently be split in two.
+	if strings.Contains(rule, ImmutableTagRulesSeparator) {
+		return nil, fmt.Errorf("rule %q must not contain %q", rule, ImmutableTagRulesSeparator)
+	}
+	return CompileTagPattern(rule)
+}
+
+// ImmutableTagMatches reports whether tag is protected by any of the rules.
+func ImmutableTagMatches(rules []string, tag string) (bool, error) {
+	for _, rule := range rules {
+		re, err := CompileImmutableTagRule(rule)
+		if err != nil {
+			return false, err
+		}
+		if re.MatchString(tag) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Protects reports whether tag is immutable under these settings. Enabling
+// immutable tags without any rules protects every tag.
+func (s ImmutableTagsSettings) Protects(tag string) (bool, error) {
+	if !s.Enabled {
+		return false, nil
+	}
+	if len(s.Rules) == 0 {
+		return true, nil
+	}
+	return ImmutableTagMatches(s.Rules, tag)
+}
@@ -0,0 +1,83 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hubclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTokenBucket(t *testing.T) {
+	tests := []struct {
+		name              string
+		requestsPerSecond float64
+		wantBurst         float64
+	}{
+		{name: "fractional rate", requestsPerSecond: 0.5, wantBurst: 1},
+		{name: "whole rate", requestsPerSecond: 4, wantBurst: 4},
+		{name: "rounded up", requestsPerSecond: 2.5, wantBurst: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTokenBucket(tt.requestsPerSecond)
+			if b.burst != tt.wantBurst || b.tokens != tt.wantBurst {
+				t.Fatalf("got burst %v with %v tokens, want %v", b.burst, b.tokens, tt.wantBurst)
+			}
+		})
+	}
+}
+
+func TestTokenBucketWait(t *testing.T) {
+	b := newTokenBucket(50)
+
+	// The burst is available right away.
+	start := time.Now()
+	for i := 0; i < 50; i++ {
+		if err := b.Wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 15*time.Millisecond {
+		t.Fatalf("burst took %s, want no wait", elapsed)
+	}
+
+	// Past the burst, requests are spaced by 1/rate.
+	start = time.Now()
+	for i := 0; i < 5; i++ {
+		if err := b.Wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Errorf("5 requests past the burst took %s, want at least 80ms", elapsed)
+	}
+}
+
+func TestTokenBucketWaitCanceled(t *testing.T) {
+	b := newTokenBucket(0.01)
+	if err := b.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := b.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got %v, want %v", err, context.DeadlineExceeded)
+	}
+}
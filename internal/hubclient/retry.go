@@ -0,0 +1,146 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hubclient
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+const (
+	DefaultRetryMaxAttempts = 5
+	DefaultRetryMinBackoff  = time.Second
+	DefaultRetryMaxBackoff  = 30 * time.Second
+)
+
+// RetryConfig controls how failed requests are retried. Zero values fall back
+// to the defaults above.
+type RetryConfig struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	MaxAttempts int
+	MinBackoff  time.Duration
+	MaxBackoff  time.Duration
+}
+
+func (r RetryConfig) withDefaults() RetryConfig {
+	if r.MaxAttempts <= 0 {
+		r.MaxAttempts = DefaultRetryMaxAttempts
+	}
+	if r.MinBackoff <= 0 {
+		r.MinBackoff = DefaultRetryMinBackoff
+	}
+	if r.MaxBackoff <= 0 {
+		r.MaxBackoff = DefaultRetryMaxBackoff
+	}
+	if r.MaxBackoff < r.MinBackoff {
+		r.MaxBackoff = r.MinBackoff
+	}
+	return r
+}
+
+// retryPolicy retries rate limited requests in addition to everything the
+// default policy retries (connection errors and 5xx responses). When Docker Hub
+// asks us to wait longer than maxBackoff we give up right away, so the caller
+// gets the rate limit error instead of a retry that is bound to fail again.
+func retryPolicy(maxBackoff time.Duration) retryablehttp.CheckRetry {
+	return func(ctx context.Context, resp *http.Response, err error) (bool, error) {
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		if err == nil {
+			if wait, ok := serverWait(resp); ok && wait > maxBackoff {
+				return false, nil
+			}
+			if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+				return true, nil
+			}
+		}
+		return retryablehttp.DefaultRetryPolicy(ctx, resp, err)
+	}
+}
+
+// backoff waits as long as Docker Hub asks us to when we are rate limited,
+// up to max, and otherwise falls back to exponential backoff.
+func backoff(min, max time.Duration, attemptNum int, resp *http.Response) time.Duration {
+	if wait, ok := serverWait(resp); ok {
+		if wait > max {
+			return max
+		}
+		return wait
+	}
+	return retryablehttp.DefaultBackoff(min, max, attemptNum, nil)
+}
+
+// serverWait returns how long Docker Hub asks us to wait before retrying a
+// rate limited or unavailable response.
+func serverWait(resp *http.Response) (time.Duration, bool) {
+	if resp == nil || (resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable) {
+		return 0, false
+	}
+	if wait, ok := retryAfter(resp.Header, time.Now()); ok {
+		return wait, true
+	}
+	return rateLimitReset(resp.Header, time.Now())
+}
+
+// retryAfter parses the Retry-After header, which is either a number of
+// seconds or an HTTP date.
+func retryAfter(header http.Header, now time.Time) (time.Duration, bool) {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if wait := at.Sub(now); wait > 0 {
+			return wait, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// rateLimitReset parses the X-RateLimit-Reset header. Docker Hub sends a unix
+// timestamp, but we also accept a number of seconds for robustness.
+func rateLimitReset(header http.Header, now time.Time) (time.Duration, bool) {
+	value := header.Get("X-RateLimit-Reset")
+	if value == "" {
+		return 0, false
+	}
+	reset, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || reset < 0 {
+		return 0, false
+	}
+
+	// Anything that looks like a timestamp (after 2001) is treated as one.
+	if reset > 1_000_000_000 {
+		if wait := time.Unix(reset, 0).Sub(now); wait > 0 {
+			return wait, true
+		}
+		return 0, true
+	}
+	return time.Duration(reset) * time.Second, true
+}
@@ -0,0 +1,157 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hubclient
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		value    string
+		wantWait time.Duration
+		wantOK   bool
+	}{
+		{name: "missing", value: ""},
+		{name: "seconds", value: "120", wantWait: 2 * time.Minute, wantOK: true},
+		{name: "zero seconds", value: "0", wantWait: 0, wantOK: true},
+		{name: "negative seconds", value: "-5"},
+		{name: "http date", value: now.Add(90 * time.Second).Format(http.TimeFormat), wantWait: 90 * time.Second, wantOK: true},
+		{name: "http date in the past", value: now.Add(-time.Hour).Format(http.TimeFormat), wantWait: 0, wantOK: true},
+		{name: "garbage", value: "soon"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.value != "" {
+				header.Set("Retry-After", tt.value)
+			}
+			wait, ok := retryAfter(header, now)
+			if ok != tt.wantOK || wait != tt.wantWait {
+				t.Errorf("got %s, %v, want %s, %v", wait, ok, tt.wantWait, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestRateLimitReset(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		value    string
+		wantWait time.Duration
+		wantOK   bool
+	}{
+		{name: "missing", value: ""},
+		{name: "epoch", value: strconv.FormatInt(now.Add(45*time.Second).Unix(), 10), wantWait: 45 * time.Second, wantOK: true},
+		{name: "epoch in the past", value: strconv.FormatInt(now.Add(-time.Minute).Unix(), 10), wantWait: 0, wantOK: true},
+		{name: "seconds", value: "30", wantWait: 30 * time.Second, wantOK: true},
+		{name: "negative", value: "-1"},
+		{name: "garbage", value: "tomorrow"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.value != "" {
+				header.Set("X-RateLimit-Reset", tt.value)
+			}
+			wait, ok := rateLimitReset(header, now)
+			if ok != tt.wantOK || wait != tt.wantWait {
+				t.Errorf("got %s, %v, want %s, %v", wait, ok, tt.wantWait, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	const (
+		min = time.Second
+		max = 30 * time.Second
+	)
+	tests := []struct {
+		name       string
+		statusCode int
+		header     http.Header
+		attempt    int
+		want       time.Duration
+	}{
+		{name: "no response", attempt: 2, want: 4 * time.Second},
+		{name: "server error", statusCode: http.StatusInternalServerError, header: http.Header{"Retry-After": {"10"}}, attempt: 1, want: 2 * time.Second},
+		{name: "exponential is capped", statusCode: http.StatusBadGateway, attempt: 10, want: max},
+		{name: "retry after", statusCode: http.StatusTooManyRequests, header: http.Header{"Retry-After": {"10"}}, want: 10 * time.Second},
+		{name: "unavailable retry after", statusCode: http.StatusServiceUnavailable, header: http.Header{"Retry-After": {"5"}}, want: 5 * time.Second},
+		{name: "retry after wins over reset", statusCode: http.StatusTooManyRequests, header: http.Header{"Retry-After": {"3"}, "X-Ratelimit-Reset": {"20"}}, want: 3 * time.Second},
+		{name: "reset", statusCode: http.StatusTooManyRequests, header: http.Header{"X-Ratelimit-Reset": {"20"}}, want: 20 * time.Second},
+		{name: "retry after is clamped", statusCode: http.StatusTooManyRequests, header: http.Header{"Retry-After": {"3600"}}, want: max},
+		{name: "reset is clamped", statusCode: http.StatusTooManyRequests, header: http.Header{"X-Ratelimit-Reset": {"3600"}}, want: max},
+		{name: "rate limited without headers", statusCode: http.StatusTooManyRequests, attempt: 0, want: min},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp *http.Response
+			if tt.statusCode != 0 {
+				resp = &http.Response{StatusCode: tt.statusCode, Header: tt.header}
+			}
+			if got := backoff(min, max, tt.attempt, resp); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		header     http.Header
+		want       bool
+	}{
+		{name: "ok", statusCode: http.StatusOK, want: false},
+		{name: "not found", statusCode: http.StatusNotFound, want: false},
+		{name: "server error", statusCode: http.StatusInternalServerError, want: true},
+		{name: "rate limited", statusCode: http.StatusTooManyRequests, want: true},
+		{name: "rate limited within max backoff", statusCode: http.StatusTooManyRequests, header: http.Header{"Retry-After": {"30"}}, want: true},
+		{name: "rate limited beyond max backoff", statusCode: http.StatusTooManyRequests, header: http.Header{"Retry-After": {"31"}}, want: false},
+		{name: "reset beyond max backoff", statusCode: http.StatusTooManyRequests, header: http.Header{"X-Ratelimit-Reset": {strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)}}, want: false},
+		{name: "unavailable beyond max backoff", statusCode: http.StatusServiceUnavailable, header: http.Header{"Retry-After": {"3600"}}, want: false},
+	}
+	checkRetry := retryPolicy(30 * time.Second)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: tt.statusCode, Header: tt.header}
+			got, err := checkRetry(context.Background(), resp, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got retry %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if retry, err := checkRetry(ctx, &http.Response{StatusCode: http.StatusTooManyRequests}, nil); retry || err == nil {
+		t.Errorf("got %v, %v, want no retry once the context is done", retry, err)
+	}
+}
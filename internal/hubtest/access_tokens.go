@@ -0,0 +1,286 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hubtest
+
+import (
+	"net"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/docker/terraform-provider-docker/internal/hubclient"
+)
+
+type accessToken struct {
+	seq    int64
+	owner  string
+	secret string
+	token  hubclient.AccessToken
+}
+
+type orgAccessToken struct {
+	seq    int64
+	org    string
+	secret string
+	token  hubclient.OrgAccessToken
+}
+
+// lookupAccessToken finds a personal access token owned by principal.
+func (s *Server) lookupAccessToken(w http.ResponseWriter, r *http.Request, principal string) (*accessToken, bool) {
+	pat, ok := s.accessTokens[r.PathValue("uuid")]
+	if !ok || pat.owner != principal {
+		writeNotFound(w)
+		return nil, false
+	}
+	return pat, true
+}
+
+func (s *Server) handleListAccessTokens(w http.ResponseWriter, r *http.Request, principal string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var pats []*accessToken
+	for _, pat := range s.accessTokens {
+		if pat.owner == principal {
+			pats = append(pats, pat)
+		}
+	}
+	// Newest first, like Docker Hub.
+	sort.Slice(pats, func(i, j int) bool {
+		return pats[i].seq > pats[j].seq
+	})
+
+	tokens := make([]hubclient.AccessToken, 0, len(pats))
+	for _, pat := range pats {
+		tokens = append(tokens, pat.token)
+	}
+	writeJSON(w, http.StatusOK, paginate(s, r, tokens))
+}
+
+func (s *Server) handleCreateAccessToken(w http.ResponseWriter, r *http.Request, principal string) {
+	var req hubclient.AccessTokenCreateParams
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if req.TokenLabel == "" {
+		writeError(w, http.StatusBadRequest, "token_label: This field may not be blank.")
+		return
+	}
+	if len(req.Scopes) == 0 {
+		writeError(w, http.StatusBadRequest, "scopes: This list may not be empty.")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	creatorIP, _, _ := net.SplitHostPort(r.RemoteAddr)
+	pat := &accessToken{
+		seq:    s.newID(),
+		owner:  principal,
+		secret: "dckr_pat_" + randomHex(16),
+		token: hubclient.AccessToken{
+			UUID:        newUUID(),
+			ClientID:    "HUB",
+			CreatorIP:   creatorIP,
+			CreatorUA:   r.UserAgent(),
+			CreatedAt:   timestamp(time.Now()),
+			GeneratedBy: "manual",
+			IsActive:    true,
+			TokenLabel:  req.TokenLabel,
+			Scopes:      req.Scopes,
+			ExpiresAt:   req.ExpiresAt,
+		},
+	}
+	s.accessTokens[pat.token.UUID] = pat
+
+	// The secret is only ever returned on creation.
+	created := pat.token
+	created.Token = pat.secret
+	writeJSON(w, http.StatusCreated, created)
+}
+
+func (s *Server) handleGetAccessToken(w http.ResponseWriter, r *http.Request, principal string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pat, ok := s.lookupAccessToken(w, r, principal)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, pat.token)
+}
+
+func (s *Server) handleUpdateAccessToken(w http.ResponseWriter, r *http.Request, principal string) {
+	var req hubclient.AccessTokenUpdateParams
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pat, ok := s.lookupAccessToken(w, r, principal)
+	if !ok {
+		return
+	}
+	if req.TokenLabel != "" {
+		pat.token.TokenLabel = req.TokenLabel
+	}
+	pat.token.IsActive = req.IsActive
+	writeJSON(w, http.StatusOK, pat.token)
+}
+
+func (s *Server) handleDeleteAccessToken(w http.ResponseWriter, r *http.Request, principal string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pat, ok := s.lookupAccessToken(w, r, principal)
+	if !ok {
+		return
+	}
+	delete(s.accessTokens, pat.token.UUID)
+	writeJSON(w, http.StatusNoContent, nil)
+}
+
+// lookupOrgAccessToken finds an organization access token of the
+// organization in the request path.
+func (s *Server) lookupOrgAccessToken(w http.ResponseWriter, r *http.Request) (*orgAccessToken, bool) {
+	oat, ok := s.orgAccessTokens[r.PathValue("id")]
+	if !ok || oat.org != r.PathValue("org") {
+		writeNotFound(w)
+		return nil, false
+	}
+	return oat, true
+}
+
+func (s *Server) handleCreateOrgAccessToken(w http.ResponseWriter, r *http.Request, principal string) {
+	var req hubclient.OrgAccessTokenCreateParams
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if req.Label == "" {
+		writeError(w, http.StatusBadRequest, "label: This field may not be blank.")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+
+	oat := &orgAccessToken{
+		seq:    s.newID(),
+		org:    o.org.OrgName,
+		secret: "dckr_oat_" + randomHex(16),
+		token: hubclient.OrgAccessToken{
+			ID:          newUUID(),
+			Label:       req.Label,
+			Description: req.Description,
+			CreatedBy:   principal,
+			IsActive:    true,
+			CreatedAt:   timestamp(time.Now()),
+			ExpiresAt:   req.ExpiresAt,
+			Resources:   req.Resources,
+		},
+	}
+	s.orgAccessTokens[oat.token.ID] = oat
+
+	// The secret is only ever returned on creation.
+	created := oat.token
+	created.Token = oat.secret
+	writeJSON(w, http.StatusCreated, created)
+}
+
+func (s *Server) handleListOrgAccessTokens(w http.ResponseWriter, r *http.Request, _ string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+
+	var oats []*orgAccessToken
+	for _, oat := range s.orgAccessTokens {
+		if oat.org == o.org.OrgName {
+			oats = append(oats, oat)
+		}
+	}
+	// Newest first, like Docker Hub.
+	sort.Slice(oats, func(i, j int) bool {
+		return oats[i].seq > oats[j].seq
+	})
+
+	tokens := make([]hubclient.OrgAccessToken, 0, len(oats))
+	for _, oat := range oats {
+		tokens = append(tokens, oat.token)
+	}
+	writeJSON(w, http.StatusOK, paginate(s, r, tokens))
+}
+
+func (s *Server) handleGetOrgAccessToken(w http.ResponseWriter, r *http.Request, _ string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	oat, ok := s.lookupOrgAccessToken(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, oat.token)
+}
+
+func (s *Server) handleUpdateOrgAccessToken(w http.ResponseWriter, r *http.Request, _ string) {
+	var req hubclient.OrgAccessTokenUpdateParams
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	oat, ok := s.lookupOrgAccessToken(w, r)
+	if !ok {
+		return
+	}
+	if req.Label != "" {
+		oat.token.Label = req.Label
+	}
+	oat.token.Description = req.Description
+	if req.Resources != nil {
+		oat.token.Resources = req.Resources
+	}
+	if req.IsActive != nil {
+		oat.token.IsActive = *req.IsActive
+	}
+	writeJSON(w, http.StatusOK, oat.token)
+}
+
+func (s *Server) handleDeleteOrgAccessToken(w http.ResponseWriter, r *http.Request, _ string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	oat, ok := s.lookupOrgAccessToken(w, r)
+	if !ok {
+		return
+	}
+	delete(s.orgAccessTokens, oat.token.ID)
+	writeJSON(w, http.StatusNoContent, nil)
+}
@@ -0,0 +1,572 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hubtest
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/terraform-provider-docker/internal/hubclient"
+)
+
+// ownersTeam is the team every Docker Hub organization is created with.
+const ownersTeam = "owners"
+
+type org struct {
+	org hubclient.Org
+	// members maps user names to their role, as returned by Docker Hub
+	// ("Owner", "Editor" or "Member").
+	members map[string]string
+	teams   map[string]*team
+	iam     hubclient.OrgSettingImageAccessManagement
+	ram     hubclient.OrgSettingRegistryAccessManagement
+}
+
+type team struct {
+	team    hubclient.OrgTeam
+	members map[string]bool
+}
+
+// AddOrg creates an organization owned by the given users. Like on Docker
+// Hub, the owners are members of the "owners" team.
+func (s *Server) AddOrg(name string, owners ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o := &org{
+		org: hubclient.Org{
+			ID:         newUUID(),
+			OrgName:    name,
+			FullName:   name,
+			DateJoined: timestamp(time.Now()),
+		},
+		members: map[string]string{},
+		teams:   map[string]*team{},
+		ram: hubclient.OrgSettingRegistryAccessManagement{
+			StandardRegistries: []hubclient.RegistryAccessManagementStandardRegistry{
+				{ID: hubclient.StandardRegistryDocker, Allowed: true},
+			},
+			CustomRegistries: []hubclient.RegistryAccessManagementCustomRegistry{},
+		},
+	}
+	t := s.addTeam(o, ownersTeam, "")
+	for _, owner := range owners {
+		o.members[owner] = roleName(hubclient.OrgRoleParamOwner)
+		t.members[owner] = true
+	}
+	s.orgs[name] = o
+}
+
+// AddOrgMember adds an existing user to an organization with the given role.
+func (s *Server) AddOrgMember(orgName, username string, role hubclient.OrgRoleParam) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, ok := s.orgs[orgName]
+	if !ok {
+		panic(fmt.Sprintf("hubtest: organization %s does not exist", orgName))
+	}
+	o.members[username] = roleName(role)
+}
+
+// roleName converts a role parameter to the capitalized form Docker Hub
+// returns.
+func roleName(role hubclient.OrgRoleParam) string {
+	switch role {
+	case hubclient.OrgRoleParamOwner:
+		return "Owner"
+	case hubclient.OrgRoleParamEditor:
+		return "Editor"
+	case hubclient.OrgRoleParamMember:
+		return "Member"
+	}
+	return ""
+}
+
+func (s *Server) addTeam(o *org, name, description string) *team {
+	t := &team{
+		team: hubclient.OrgTeam{
+			ID:          s.newID(),
+			UUID:        newUUID(),
+			Name:        name,
+			Description: description,
+		},
+		members: map[string]bool{},
+	}
+	o.teams[name] = t
+	return t
+}
+
+func (t *team) response() hubclient.OrgTeam {
+	resp := t.team
+	resp.MemberCount = len(t.members)
+	return resp
+}
+
+func (s *Server) lookupOrg(w http.ResponseWriter, r *http.Request) (*org, bool) {
+	o, ok := s.orgs[r.PathValue("org")]
+	if !ok {
+		writeNotFound(w)
+	}
+	return o, ok
+}
+
+func (s *Server) lookupTeam(w http.ResponseWriter, r *http.Request) (*org, *team, bool) {
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return nil, nil, false
+	}
+	t, ok := o.teams[r.PathValue("team")]
+	if !ok {
+		writeNotFound(w)
+		return nil, nil, false
+	}
+	return o, t, true
+}
+
+// lookupUser finds a user by name or email.
+func (s *Server) lookupUser(nameOrEmail string) (*user, bool) {
+	if u, ok := s.users[nameOrEmail]; ok {
+		return u, true
+	}
+	for _, u := range s.users {
+		if u.email != "" && u.email == nameOrEmail {
+			return u, true
+		}
+	}
+	return nil, false
+}
+
+func (s *Server) orgMember(o *org, username string) hubclient.OrgMember {
+	member := hubclient.OrgMember{
+		Username:   username,
+		Role:       o.members[username],
+		Groups:     []string{},
+		Type:       "User",
+		DateJoined: o.org.DateJoined,
+	}
+	if u, ok := s.users[username]; ok {
+		member.ID = u.id
+		member.Email = u.email
+	}
+	for _, name := range sortedKeys(o.teams) {
+		if o.teams[name].members[username] {
+			member.Groups = append(member.Groups, name)
+		}
+	}
+	return member
+}
+
+func (s *Server) handleGetOrg(w http.ResponseWriter, r *http.Request, _ string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, o.org)
+}
+
+func (s *Server) handleListOrgMembers(w http.ResponseWriter, r *http.Request, _ string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+	var members []hubclient.OrgMember
+	for _, username := range sortedKeys(o.members) {
+		members = append(members, s.orgMember(o, username))
+	}
+	writeJSON(w, http.StatusOK, paginate(s, r, members))
+}
+
+func (s *Server) handleUpdateOrgMember(w http.ResponseWriter, r *http.Request, _ string) {
+	var req struct {
+		Role hubclient.OrgRoleParam `json:"role"`
+	}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+	username := r.PathValue("username")
+	if _, ok := o.members[username]; !ok {
+		writeNotFound(w)
+		return
+	}
+	role := roleName(req.Role)
+	if role == "" {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid role %q", req.Role))
+		return
+	}
+	o.members[username] = role
+	writeJSON(w, http.StatusOK, s.orgMember(o, username))
+}
+
+func (s *Server) handleDeleteOrgMember(w http.ResponseWriter, r *http.Request, _ string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+	username := r.PathValue("username")
+	if _, ok := o.members[username]; !ok {
+		writeNotFound(w)
+		return
+	}
+	delete(o.members, username)
+	for _, t := range o.teams {
+		delete(t.members, username)
+	}
+	writeJSON(w, http.StatusNoContent, nil)
+}
+
+func (s *Server) handleListOrgInvites(w http.ResponseWriter, r *http.Request, _ string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+	invites := []hubclient.OrgInvite{}
+	for _, invite := range s.invites {
+		if invite.Org == o.org.OrgName {
+			invites = append(invites, *invite)
+		}
+	}
+	sort.Slice(invites, func(i, j int) bool {
+		if invites[i].CreatedAt != invites[j].CreatedAt {
+			return invites[i].CreatedAt < invites[j].CreatedAt
+		}
+		return invites[i].ID < invites[j].ID
+	})
+	writeJSON(w, http.StatusOK, hubclient.OrgInvitesListResponse{Data: invites})
+}
+
+func (s *Server) handleBulkInvite(w http.ResponseWriter, r *http.Request, principal string) {
+	var req hubclient.OrgMemberRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, ok := s.orgs[req.Org]
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+	if roleName(hubclient.OrgRoleParam(req.Role)) == "" {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid role %q", req.Role))
+		return
+	}
+	if req.Team != "" {
+		if _, ok := o.teams[req.Team]; !ok {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("team %q does not exist", req.Team))
+			return
+		}
+	}
+
+	resp := hubclient.OrgInviteResponse{OrgInvitees: []hubclient.OrgInvitee{}}
+	for _, invitee := range req.Invitees {
+		if u, ok := s.lookupUser(invitee); ok {
+			if _, member := o.members[u.username]; member {
+				resp.OrgInvitees = append(resp.OrgInvitees, hubclient.OrgInvitee{
+					Invitee: invitee,
+					Status:  "already_member",
+				})
+				continue
+			}
+		}
+
+		if existing := s.pendingInvite(o.org.OrgName, invitee); existing != nil {
+			resp.OrgInvitees = append(resp.OrgInvitees, hubclient.OrgInvitee{
+				Invitee: invitee,
+				Status:  "already_invited",
+				Invite:  *existing,
+			})
+			continue
+		}
+
+		invite := hubclient.OrgInvite{
+			ID:              newUUID(),
+			InviterUsername: principal,
+			Invitee:         invitee,
+			Team:            req.Team,
+			Org:             o.org.OrgName,
+			Role:            strings.ToLower(req.Role),
+			CreatedAt:       timestamp(time.Now()),
+		}
+		if !req.DryRun {
+			s.invites[invite.ID] = &invite
+		}
+		resp.OrgInvitees = append(resp.OrgInvitees, hubclient.OrgInvitee{
+			Invitee: invitee,
+			Status:  "invited",
+			Invite:  invite,
+		})
+	}
+	writeJSON(w, http.StatusAccepted, resp)
+}
+
+func (s *Server) pendingInvite(orgName, invitee string) *hubclient.OrgInvite {
+	for _, invite := range s.invites {
+		if invite.Org == orgName && invite.Invitee == invitee {
+			return invite
+		}
+	}
+	return nil
+}
+
+func (s *Server) handleDeleteInvite(w http.ResponseWriter, r *http.Request, _ string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	if _, ok := s.invites[id]; !ok {
+		writeNotFound(w)
+		return
+	}
+	delete(s.invites, id)
+	writeJSON(w, http.StatusNoContent, nil)
+}
+
+func (s *Server) handleCreateTeam(w http.ResponseWriter, r *http.Request, _ string) {
+	var req hubclient.OrgTeam
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+	if req.Name == "" {
+		writeError(w, http.StatusBadRequest, "name: This field may not be blank.")
+		return
+	}
+	if _, ok := o.teams[req.Name]; ok {
+		writeError(w, http.StatusConflict, fmt.Sprintf("team %q already exists", req.Name))
+		return
+	}
+
+	t := s.addTeam(o, req.Name, req.Description)
+	writeJSON(w, http.StatusCreated, t.response())
+}
+
+func (s *Server) handleGetTeam(w http.ResponseWriter, r *http.Request, _ string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, t, ok := s.lookupTeam(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, t.response())
+}
+
+func (s *Server) handleUpdateTeam(w http.ResponseWriter, r *http.Request, _ string) {
+	var req hubclient.OrgTeam
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, t, ok := s.lookupTeam(w, r)
+	if !ok {
+		return
+	}
+	if req.Name != "" && req.Name != t.team.Name {
+		if _, ok := o.teams[req.Name]; ok {
+			writeError(w, http.StatusConflict, fmt.Sprintf("team %q already exists", req.Name))
+			return
+		}
+		delete(o.teams, t.team.Name)
+		t.team.Name = req.Name
+		o.teams[req.Name] = t
+	}
+	t.team.Description = req.Description
+	writeJSON(w, http.StatusOK, t.response())
+}
+
+func (s *Server) handleDeleteTeam(w http.ResponseWriter, r *http.Request, _ string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, t, ok := s.lookupTeam(w, r)
+	if !ok {
+		return
+	}
+	delete(o.teams, t.team.Name)
+	for _, repo := range s.repos {
+		if repo.repo.Namespace == o.org.OrgName {
+			delete(repo.teamPermissions, t.team.ID)
+		}
+	}
+	writeJSON(w, http.StatusNoContent, nil)
+}
+
+func (s *Server) handleListTeamMembers(w http.ResponseWriter, r *http.Request, _ string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, t, ok := s.lookupTeam(w, r)
+	if !ok {
+		return
+	}
+	var members []hubclient.OrgTeamMember
+	for _, username := range sortedKeys(t.members) {
+		member := s.orgMember(o, username)
+		members = append(members, hubclient.OrgTeamMember{
+			UUID:       member.ID,
+			Username:   member.Username,
+			Email:      member.Email,
+			Role:       member.Role,
+			Groups:     member.Groups,
+			Type:       member.Type,
+			DateJoined: member.DateJoined,
+		})
+	}
+	writeJSON(w, http.StatusOK, paginate(s, r, members))
+}
+
+func (s *Server) handleAddTeamMember(w http.ResponseWriter, r *http.Request, _ string) {
+	var req hubclient.OrgTeamMemberRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, t, ok := s.lookupTeam(w, r)
+	if !ok {
+		return
+	}
+	u, ok := s.lookupUser(req.Member)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("user %q does not exist", req.Member))
+		return
+	}
+
+	// Adding a user to a team also adds them to the organization.
+	if _, ok := o.members[u.username]; !ok {
+		o.members[u.username] = roleName(hubclient.OrgRoleParamMember)
+	}
+	t.members[u.username] = true
+	writeJSON(w, http.StatusOK, map[string]string{"member": u.username})
+}
+
+func (s *Server) handleDeleteTeamMember(w http.ResponseWriter, r *http.Request, _ string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, t, ok := s.lookupTeam(w, r)
+	if !ok {
+		return
+	}
+	username := r.PathValue("username")
+	if !t.members[username] {
+		writeNotFound(w)
+		return
+	}
+	delete(t.members, username)
+	writeJSON(w, http.StatusNoContent, nil)
+}
+
+func (s *Server) handleGetImageAccessManagement(w http.ResponseWriter, r *http.Request, _ string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, o.iam)
+}
+
+func (s *Server) handleSetImageAccessManagement(w http.ResponseWriter, r *http.Request, _ string) {
+	var req hubclient.OrgSettingImageAccessManagement
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+	o.iam = req
+	writeJSON(w, http.StatusOK, o.iam)
+}
+
+func (s *Server) handleGetRegistryAccessManagement(w http.ResponseWriter, r *http.Request, _ string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, o.ram)
+}
+
+func (s *Server) handleSetRegistryAccessManagement(w http.ResponseWriter, r *http.Request, _ string) {
+	var req hubclient.OrgSettingRegistryAccessManagement
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+	if req.StandardRegistries == nil {
+		req.StandardRegistries = []hubclient.RegistryAccessManagementStandardRegistry{}
+	}
+	if req.CustomRegistries == nil {
+		req.CustomRegistries = []hubclient.RegistryAccessManagementCustomRegistry{}
+	}
+	o.ram = req
+	writeJSON(w, http.StatusOK, o.ram)
+}
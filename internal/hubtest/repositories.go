@@ -0,0 +1,619 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hubtest
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/terraform-provider-docker/internal/hubclient"
+)
+
+type repository struct {
+	repo            hubclient.Repository
+	tags            []hubclient.Tag
+	teamPermissions map[int64]string
+	collaborators   map[string]string
+	webhooks        []hubclient.RepositoryWebhook
+}
+
+func repoKey(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+// AddRepository adds a repository. Fields that are not set are filled in
+// with the values Docker Hub would return for a new repository.
+func (s *Server) AddRepository(repo hubclient.Repository) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := timestamp(time.Now())
+	if repo.RepositoryType == "" {
+		repo.RepositoryType = "image"
+	}
+	if repo.StatusDescription == "" {
+		repo.Status = 1
+		repo.StatusDescription = "active"
+	}
+	if repo.DateRegistered == "" {
+		repo.DateRegistered = now
+	}
+	if repo.LastUpdated == "" {
+		repo.LastUpdated = now
+	}
+	if repo.ImmutableTagsSettings.Rules == nil {
+		repo.ImmutableTagsSettings.Rules = []string{}
+	}
+	repo.Permissions = hubclient.Permissions{Read: true, Write: true, Admin: true}
+
+	s.repos[repoKey(repo.Namespace, repo.Name)] = &repository{
+		repo:            repo,
+		teamPermissions: map[int64]string{},
+		collaborators:   map[string]string{},
+	}
+}
+
+// AddTag adds a tag to a repository previously added with AddRepository.
+// Fields that are not set are filled in with plausible values, including a
+// single linux/amd64 image.
+func (s *Server) AddTag(namespace, name string, tag hubclient.Tag) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repo, ok := s.repos[repoKey(namespace, name)]
+	if !ok {
+		panic(fmt.Sprintf("hubtest: repository %s/%s does not exist", namespace, name))
+	}
+
+	now := timestamp(time.Now())
+	tag.ID = s.newID()
+	if tag.Digest == "" {
+		tag.Digest = fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(repoKey(namespace, name)+":"+tag.Name)))
+	}
+	if tag.FullSize == 0 {
+		tag.FullSize = 2048
+	}
+	if tag.LastUpdated == "" {
+		tag.LastUpdated = now
+	}
+	if tag.TagLastPushed == "" {
+		tag.TagLastPushed = tag.LastUpdated
+	}
+	if tag.TagStatus == "" {
+		tag.TagStatus = "active"
+	}
+	if tag.MediaType == "" {
+		tag.MediaType = "application/vnd.oci.image.index.v1+json"
+	}
+	if tag.ContentType == "" {
+		tag.ContentType = "image"
+	}
+	tag.V2 = true
+	if len(tag.Images) == 0 {
+		tag.Images = []hubclient.TagImage{{
+			Architecture: "amd64",
+			OS:           "linux",
+			Digest:       tag.Digest,
+			Size:         tag.FullSize,
+			Status:       tag.TagStatus,
+			LastPushed:   tag.TagLastPushed,
+			LastPulled:   tag.TagLastPulled,
+		}}
+	}
+
+	repo.tags = append(repo.tags, tag)
+}
+
+func (s *Server) lookupRepository(w http.ResponseWriter, r *http.Request) (*repository, bool) {
+	repo, ok := s.repos[repoKey(r.PathValue("namespace"), r.PathValue("name"))]
+	if !ok {
+		writeNotFound(w)
+	}
+	return repo, ok
+}
+
+func (s *Server) namespaceExists(namespace string) bool {
+	if _, ok := s.users[namespace]; ok {
+		return true
+	}
+	_, ok := s.orgs[namespace]
+	return ok
+}
+
+func (s *Server) handleCreateRepository(w http.ResponseWriter, r *http.Request, principal string) {
+	var req hubclient.CreateRepositoryRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	namespace := r.PathValue("namespace")
+	if !s.namespaceExists(namespace) {
+		writeNotFound(w)
+		return
+	}
+	if req.Name == "" {
+		writeError(w, http.StatusBadRequest, "name: This field may not be blank.")
+		return
+	}
+	if _, ok := s.repos[repoKey(namespace, req.Name)]; ok {
+		writeError(w, http.StatusConflict, "Repository with this Name and Namespace already exists.")
+		return
+	}
+
+	now := timestamp(time.Now())
+	repo := &repository{
+		repo: hubclient.Repository{
+			Name:                  req.Name,
+			Namespace:             namespace,
+			RepositoryType:        "image",
+			IsPrivate:             req.IsPrivate,
+			Status:                1,
+			StatusDescription:     "active",
+			Description:           req.Description,
+			FullDescription:       req.FullDescription,
+			LastUpdated:           now,
+			DateRegistered:        now,
+			HubUser:               principal,
+			Permissions:           hubclient.Permissions{Read: true, Write: true, Admin: true},
+			ImmutableTagsSettings: hubclient.ImmutableTagsSettings{Rules: []string{}},
+		},
+		teamPermissions: map[int64]string{},
+		collaborators:   map[string]string{},
+	}
+	s.repos[repoKey(namespace, req.Name)] = repo
+	writeJSON(w, http.StatusCreated, repo.repo)
+}
+
+func (s *Server) handleListRepositories(w http.ResponseWriter, r *http.Request, _ string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	namespace := r.PathValue("namespace")
+	var repos []hubclient.Repository
+	for _, key := range sortedKeys(s.repos) {
+		if repo := s.repos[key]; repo.repo.Namespace == namespace {
+			repos = append(repos, repo.repo)
+		}
+	}
+	writeJSON(w, http.StatusOK, paginate(s, r, repos))
+}
+
+func (s *Server) handleGetRepository(w http.ResponseWriter, r *http.Request, _ string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repo, ok := s.lookupRepository(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, repo.repo)
+}
+
+func (s *Server) handleUpdateRepository(w http.ResponseWriter, r *http.Request, _ string) {
+	var req hubclient.UpdateRepositoryRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repo, ok := s.lookupRepository(w, r)
+	if !ok {
+		return
+	}
+
+	repo.repo.Description = req.Description
+	repo.repo.FullDescription = req.FullDescription
+	repo.repo.ImmutableTagsSettings.Enabled = req.ImmutableTags
+	switch {
+	case req.ImmutableTagsRules != "":
+		repo.repo.ImmutableTagsSettings.Rules = strings.Split(req.ImmutableTagsRules, hubclient.ImmutableTagRulesSeparator)
+	case !req.ImmutableTags:
+		repo.repo.ImmutableTagsSettings.Rules = []string{}
+	}
+	repo.repo.LastUpdated = timestamp(time.Now())
+	writeJSON(w, http.StatusOK, repo.repo)
+}
+
+func (s *Server) handleDeleteRepository(w http.ResponseWriter, r *http.Request, _ string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repo, ok := s.lookupRepository(w, r)
+	if !ok {
+		return
+	}
+	delete(s.repos, repoKey(repo.repo.Namespace, repo.repo.Name))
+	writeJSON(w, http.StatusAccepted, nil)
+}
+
+func (s *Server) handleSetRepositoryPrivacy(w http.ResponseWriter, r *http.Request, _ string) {
+	var req hubclient.SetRepositoryPrivacyRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repo, ok := s.lookupRepository(w, r)
+	if !ok {
+		return
+	}
+	repo.repo.IsPrivate = req.IsPrivate
+	writeJSON(w, http.StatusOK, map[string]bool{"is_private": req.IsPrivate})
+}
+
+func (s *Server) handleListTags(w http.ResponseWriter, r *http.Request, _ string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repo, ok := s.lookupRepository(w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	var tags []hubclient.Tag
+	for _, tag := range repo.tags {
+		if strings.Contains(tag.Name, query.Get("name")) {
+			tags = append(tags, tag)
+		}
+	}
+	switch query.Get("ordering") {
+	case "", "-last_updated":
+		// Docker Hub lists the most recently updated tags first.
+		sort.SliceStable(tags, func(i, j int) bool {
+			return tags[i].LastUpdated > tags[j].LastUpdated
+		})
+	case "last_updated":
+		sort.SliceStable(tags, func(i, j int) bool {
+			return tags[i].LastUpdated < tags[j].LastUpdated
+		})
+	case "name":
+		sort.SliceStable(tags, func(i, j int) bool {
+			return tags[i].Name < tags[j].Name
+		})
+	case "-name":
+		sort.SliceStable(tags, func(i, j int) bool {
+			return tags[i].Name > tags[j].Name
+		})
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid ordering %q", query.Get("ordering")))
+		return
+	}
+	writeJSON(w, http.StatusOK, paginate(s, r, tags))
+}
+
+func (s *Server) handleGetTag(w http.ResponseWriter, r *http.Request, _ string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repo, ok := s.lookupRepository(w, r)
+	if !ok {
+		return
+	}
+	for _, tag := range repo.tags {
+		if tag.Name == r.PathValue("tag") {
+			writeJSON(w, http.StatusOK, tag)
+			return
+		}
+	}
+	writeNotFound(w)
+}
+
+func (s *Server) handleDeleteTag(w http.ResponseWriter, r *http.Request, _ string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repo, ok := s.lookupRepository(w, r)
+	if !ok {
+		return
+	}
+	name := r.PathValue("tag")
+	for i, tag := range repo.tags {
+		if tag.Name != name {
+			continue
+		}
+		immutable, err := repo.repo.ImmutableTagsSettings.Protects(name)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if immutable {
+			writeError(w, http.StatusForbidden, fmt.Sprintf("tag %q is immutable", name))
+			return
+		}
+		repo.tags = append(repo.tags[:i], repo.tags[i+1:]...)
+		writeJSON(w, http.StatusNoContent, nil)
+		return
+	}
+	writeNotFound(w)
+}
+
+// lookupTeamByID finds a team of the organization that owns the repository.
+func (s *Server) lookupTeamByID(namespace string, teamID int64) (*team, bool) {
+	o, ok := s.orgs[namespace]
+	if !ok {
+		return nil, false
+	}
+	for _, t := range o.teams {
+		if t.team.ID == teamID {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+func validTeamRepoPermission(permission string) bool {
+	switch permission {
+	case hubclient.TeamRepoPermissionLevelRead, hubclient.TeamRepoPermissionLevelWrite, hubclient.TeamRepoPermissionLevelAdmin:
+		return true
+	}
+	return false
+}
+
+func (s *Server) handleListTeamPermissions(w http.ResponseWriter, r *http.Request, _ string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repo, ok := s.lookupRepository(w, r)
+	if !ok {
+		return
+	}
+
+	var perms []hubclient.TeamRepoPermission
+	for teamID, permission := range repo.teamPermissions {
+		t, ok := s.lookupTeamByID(repo.repo.Namespace, teamID)
+		if !ok {
+			// The team was deleted.
+			continue
+		}
+		perms = append(perms, hubclient.TeamRepoPermission{
+			TeamID:     teamID,
+			TeamName:   t.team.Name,
+			Permission: permission,
+		})
+	}
+	sort.Slice(perms, func(i, j int) bool {
+		return perms[i].TeamID < perms[j].TeamID
+	})
+	writeJSON(w, http.StatusOK, paginate(s, r, perms))
+}
+
+func (s *Server) handleCreateTeamPermission(w http.ResponseWriter, r *http.Request, _ string) {
+	var req hubclient.TeamRepoPermission
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repo, ok := s.lookupRepository(w, r)
+	if !ok {
+		return
+	}
+	t, ok := s.lookupTeamByID(repo.repo.Namespace, req.TeamID)
+	if !ok {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("group %d does not exist", req.TeamID))
+		return
+	}
+	if !validTeamRepoPermission(req.Permission) {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid permission %q", req.Permission))
+		return
+	}
+	if _, ok := repo.teamPermissions[req.TeamID]; ok {
+		writeError(w, http.StatusConflict, "group already has access to this repository")
+		return
+	}
+
+	repo.teamPermissions[req.TeamID] = req.Permission
+	writeJSON(w, http.StatusOK, hubclient.TeamRepoPermission{
+		TeamID:     t.team.ID,
+		TeamName:   t.team.Name,
+		Permission: req.Permission,
+	})
+}
+
+// lookupTeamPermission resolves the repository and team of a
+// /repositories/{namespace}/{name}/groups/{team}/ request.
+func (s *Server) lookupTeamPermission(w http.ResponseWriter, r *http.Request) (*repository, *team, bool) {
+	repo, ok := s.lookupRepository(w, r)
+	if !ok {
+		return nil, nil, false
+	}
+	teamID, err := strconv.ParseInt(r.PathValue("team"), 10, 64)
+	if err != nil {
+		writeNotFound(w)
+		return nil, nil, false
+	}
+	t, ok := s.lookupTeamByID(repo.repo.Namespace, teamID)
+	if !ok {
+		writeNotFound(w)
+		return nil, nil, false
+	}
+	if _, ok := repo.teamPermissions[teamID]; !ok {
+		writeNotFound(w)
+		return nil, nil, false
+	}
+	return repo, t, true
+}
+
+func (s *Server) handleGetTeamPermission(w http.ResponseWriter, r *http.Request, _ string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repo, t, ok := s.lookupTeamPermission(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, hubclient.TeamRepoPermission{
+		TeamID:     t.team.ID,
+		TeamName:   t.team.Name,
+		Permission: repo.teamPermissions[t.team.ID],
+	})
+}
+
+func (s *Server) handleUpdateTeamPermission(w http.ResponseWriter, r *http.Request, _ string) {
+	var req hubclient.TeamRepoPermission
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repo, t, ok := s.lookupTeamPermission(w, r)
+	if !ok {
+		return
+	}
+	if !validTeamRepoPermission(req.Permission) {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid permission %q", req.Permission))
+		return
+	}
+
+	repo.teamPermissions[t.team.ID] = req.Permission
+	writeJSON(w, http.StatusOK, hubclient.TeamRepoPermission{
+		TeamID:     t.team.ID,
+		TeamName:   t.team.Name,
+		Permission: req.Permission,
+	})
+}
+
+func (s *Server) handleDeleteTeamPermission(w http.ResponseWriter, r *http.Request, _ string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repo, t, ok := s.lookupTeamPermission(w, r)
+	if !ok {
+		return
+	}
+	delete(repo.teamPermissions, t.team.ID)
+	writeJSON(w, http.StatusNoContent, nil)
+}
+
+// lookupCollaborator resolves the repository and user name of a
+// /repositories/{namespace}/{name}/collaborators/{username}/ request.
+func (s *Server) lookupCollaborator(w http.ResponseWriter, r *http.Request) (*repository, string, bool) {
+	repo, ok := s.lookupRepository(w, r)
+	if !ok {
+		return nil, "", false
+	}
+	username := r.PathValue("username")
+	if _, ok := repo.collaborators[username]; !ok {
+		writeNotFound(w)
+		return nil, "", false
+	}
+	return repo, username, true
+}
+
+func (s *Server) handleCreateCollaborator(w http.ResponseWriter, r *http.Request, _ string) {
+	var req hubclient.RepositoryCollaborator
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repo, ok := s.lookupRepository(w, r)
+	if !ok {
+		return
+	}
+	u, ok := s.lookupUser(req.User)
+	if !ok {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("user %q does not exist", req.User))
+		return
+	}
+	if !validTeamRepoPermission(req.Permission) {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid permission %q", req.Permission))
+		return
+	}
+	if _, ok := repo.collaborators[u.username]; ok {
+		writeError(w, http.StatusConflict, "user is already a collaborator on this repository")
+		return
+	}
+
+	repo.collaborators[u.username] = req.Permission
+	repo.repo.CollaboratorCount = int64(len(repo.collaborators))
+	writeJSON(w, http.StatusCreated, hubclient.RepositoryCollaborator{
+		User:       u.username,
+		Permission: req.Permission,
+	})
+}
+
+func (s *Server) handleGetCollaborator(w http.ResponseWriter, r *http.Request, _ string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repo, username, ok := s.lookupCollaborator(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, hubclient.RepositoryCollaborator{
+		User:       username,
+		Permission: repo.collaborators[username],
+	})
+}
+
+func (s *Server) handleUpdateCollaborator(w http.ResponseWriter, r *http.Request, _ string) {
+	var req hubclient.RepositoryCollaborator
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repo, username, ok := s.lookupCollaborator(w, r)
+	if !ok {
+		return
+	}
+	if !validTeamRepoPermission(req.Permission) {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid permission %q", req.Permission))
+		return
+	}
+
+	repo.collaborators[username] = req.Permission
+	writeJSON(w, http.StatusOK, hubclient.RepositoryCollaborator{
+		User:       username,
+		Permission: req.Permission,
+	})
+}
+
+func (s *Server) handleDeleteCollaborator(w http.ResponseWriter, r *http.Request, _ string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repo, username, ok := s.lookupCollaborator(w, r)
+	if !ok {
+		return
+	}
+	delete(repo.collaborators, username)
+	repo.repo.CollaboratorCount = int64(len(repo.collaborators))
+	writeJSON(w, http.StatusNoContent, nil)
+}
@@ -0,0 +1,459 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package hubtest provides an in-memory fake of the Docker Hub API, so that
+// the client and the provider can be tested without network access or a real
+// Docker Hub account.
+//
+// The fake implements the endpoints used by the hubclient package. It keeps
+// the behavior that matters to the provider (authentication, pagination,
+// not found and conflict errors), but it does not try to model Docker Hub
+// permissions: any authenticated user may manage any object.
+package hubtest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/terraform-provider-docker/internal/hubclient"
+	"github.com/docker/terraform-provider-docker/internal/totp"
+	"github.com/go-jose/go-jose/v3"
+	"github.com/go-jose/go-jose/v3/jwt"
+)
+
+const (
+	// DefaultPageSize is the page size used by list endpoints when the
+	// request does not ask for one.
+	DefaultPageSize = 10
+
+	// DefaultTokenTTL is how long tokens returned by the login endpoint are
+	// valid for.
+	DefaultTokenTTL = time.Hour
+)
+
+// Server is a fake Docker Hub API server.
+type Server struct {
+	*httptest.Server
+
+	// PageSize is the default page size of list endpoints.
+	PageSize int
+	// TokenTTL is the lifetime of tokens returned by the login endpoint.
+	TokenTTL time.Duration
+
+	mu              sync.Mutex
+	signingKey      []byte
+	nextID          int64
+	users           map[string]*user
+	sessions        map[string]string
+	login2FATokens  map[string]string
+	orgs            map[string]*org
+	repos           map[string]*repository
+	invites         map[string]*hubclient.OrgInvite
+	accessTokens    map[string]*accessToken
+	orgAccessTokens map[string]*orgAccessToken
+}
+
+type user struct {
+	id       string
+	username string
+	password string
+	email    string
+	// totpSecret is set for users with two-factor authentication.
+	totpSecret string
+}
+
+// NewServer starts a new fake Docker Hub server. The caller should call
+// Close when finished, to shut it down.
+func NewServer() *Server {
+	s := &Server{
+		PageSize:        DefaultPageSize,
+		TokenTTL:        DefaultTokenTTL,
+		signingKey:      []byte(randomHex(32)),
+		users:           map[string]*user{},
+		sessions:        map[string]string{},
+		login2FATokens:  map[string]string{},
+		orgs:            map[string]*org{},
+		repos:           map[string]*repository{},
+		invites:         map[string]*hubclient.OrgInvite{},
+		accessTokens:    map[string]*accessToken{},
+		orgAccessTokens: map[string]*orgAccessToken{},
+	}
+	s.Server = httptest.NewServer(s.routes())
+	return s
+}
+
+// BaseURL returns the URL of the v2 API, as expected by hubclient.Config.
+func (s *Server) BaseURL() string {
+	return s.URL + "/v2"
+}
+
+// AddUser registers a user that can log in with the given password.
+func (s *Server) AddUser(username, password, email string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.users[username] = &user{
+		id:       newUUID(),
+		username: username,
+		password: password,
+		email:    email,
+	}
+}
+
+// EnableTwoFactor turns on two-factor authentication for a user, with a
+// base32 TOTP secret. Logging in with the password then requires a code,
+// logging in with a personal access token does not.
+func (s *Server) EnableTwoFactor(username, secret string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users[username].totpSecret = secret
+}
+
+// RevokeTokens invalidates all tokens returned by the login endpoint, as
+// if they were revoked before their expiry.
+func (s *Server) RevokeTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions = map[string]string{}
+}
+
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("POST /v2/users/login", s.handleLogin)
+	mux.HandleFunc("POST /v2/users/2fa-login", s.handle2FALogin)
+
+	handle := func(pattern string, handler func(w http.ResponseWriter, r *http.Request, principal string)) {
+		mux.HandleFunc(pattern, s.authenticated(handler))
+	}
+
+	handle("POST /v2/namespaces/{namespace}/repositories", s.handleCreateRepository)
+	handle("GET /v2/namespaces/{namespace}/repositories/{name}/tags", s.handleListTags)
+	handle("DELETE /v2/namespaces/{namespace}/repositories/{name}/tags/{tag}", s.handleDeleteTag)
+	handle("GET /v2/repositories/{namespace}/{$}", s.handleListRepositories)
+	handle("GET /v2/repositories/{namespace}/{name}/{$}", s.handleGetRepository)
+	handle("PATCH /v2/repositories/{namespace}/{name}/{$}", s.handleUpdateRepository)
+	handle("DELETE /v2/repositories/{namespace}/{name}/{$}", s.handleDeleteRepository)
+	handle("POST /v2/repositories/{namespace}/{name}/privacy", s.handleSetRepositoryPrivacy)
+	handle("GET /v2/repositories/{namespace}/{name}/tags/{tag}", s.handleGetTag)
+	handle("GET /v2/repositories/{namespace}/{name}/groups/{$}", s.handleListTeamPermissions)
+	handle("POST /v2/repositories/{namespace}/{name}/groups/{$}", s.handleCreateTeamPermission)
+	handle("GET /v2/repositories/{namespace}/{name}/groups/{team}/{$}", s.handleGetTeamPermission)
+	handle("PATCH /v2/repositories/{namespace}/{name}/groups/{team}/{$}", s.handleUpdateTeamPermission)
+	handle("DELETE /v2/repositories/{namespace}/{name}/groups/{team}/{$}", s.handleDeleteTeamPermission)
+	handle("POST /v2/repositories/{namespace}/{name}/collaborators/{$}", s.handleCreateCollaborator)
+	handle("GET /v2/repositories/{namespace}/{name}/collaborators/{username}/{$}", s.handleGetCollaborator)
+	handle("PATCH /v2/repositories/{namespace}/{name}/collaborators/{username}/{$}", s.handleUpdateCollaborator)
+	handle("DELETE /v2/repositories/{namespace}/{name}/collaborators/{username}/{$}", s.handleDeleteCollaborator)
+	handle("GET /v2/repositories/{namespace}/{name}/webhook_pipeline/{$}", s.handleListWebhooks)
+	handle("POST /v2/repositories/{namespace}/{name}/webhook_pipeline/{$}", s.handleCreateWebhook)
+	handle("GET /v2/repositories/{namespace}/{name}/webhook_pipeline/{slug}/{$}", s.handleGetWebhook)
+	handle("PATCH /v2/repositories/{namespace}/{name}/webhook_pipeline/{slug}/{$}", s.handleUpdateWebhook)
+	handle("DELETE /v2/repositories/{namespace}/{name}/webhook_pipeline/{slug}/{$}", s.handleDeleteWebhook)
+
+	handle("GET /v2/orgs/{org}/{$}", s.handleGetOrg)
+	handle("GET /v2/orgs/{org}/members", s.handleListOrgMembers)
+	handle("PUT /v2/orgs/{org}/members/{username}/{$}", s.handleUpdateOrgMember)
+	handle("DELETE /v2/orgs/{org}/members/{username}/{$}", s.handleDeleteOrgMember)
+	handle("GET /v2/orgs/{org}/invites", s.handleListOrgInvites)
+	handle("POST /v2/invites/bulk", s.handleBulkInvite)
+	handle("DELETE /v2/invites/{id}", s.handleDeleteInvite)
+	handle("POST /v2/orgs/{org}/groups/{$}", s.handleCreateTeam)
+	handle("GET /v2/orgs/{org}/groups/{team}/{$}", s.handleGetTeam)
+	handle("PATCH /v2/orgs/{org}/groups/{team}/{$}", s.handleUpdateTeam)
+	handle("DELETE /v2/orgs/{org}/groups/{team}/{$}", s.handleDeleteTeam)
+	handle("GET /v2/orgs/{org}/groups/{team}/members/{$}", s.handleListTeamMembers)
+	handle("POST /v2/orgs/{org}/groups/{team}/members/{$}", s.handleAddTeamMember)
+	handle("DELETE /v2/orgs/{org}/groups/{team}/members/{username}", s.handleDeleteTeamMember)
+	handle("GET /v2/orgs/{org}/settings/{$}", s.handleGetImageAccessManagement)
+	handle("PUT /v2/orgs/{org}/settings", s.handleSetImageAccessManagement)
+	handle("GET /v2/orgs/{org}/settings/registry-access-management", s.handleGetRegistryAccessManagement)
+	handle("PUT /v2/orgs/{org}/settings/registry-access-management", s.handleSetRegistryAccessManagement)
+
+	handle("GET /v2/access-tokens", s.handleListAccessTokens)
+	handle("POST /v2/access-tokens", s.handleCreateAccessToken)
+	handle("GET /v2/access-tokens/{uuid}", s.handleGetAccessToken)
+	handle("PATCH /v2/access-tokens/{uuid}", s.handleUpdateAccessToken)
+	handle("DELETE /v2/access-tokens/{uuid}", s.handleDeleteAccessToken)
+	handle("GET /v2/orgs/{org}/access-tokens", s.handleListOrgAccessTokens)
+	handle("POST /v2/orgs/{org}/access-tokens", s.handleCreateOrgAccessToken)
+	handle("GET /v2/orgs/{org}/access-tokens/{id}", s.handleGetOrgAccessToken)
+	handle("PATCH /v2/orgs/{org}/access-tokens/{id}", s.handleUpdateOrgAccessToken)
+	handle("DELETE /v2/orgs/{org}/access-tokens/{id}", s.handleDeleteOrgAccessToken)
+
+	return mux
+}
+
+// handleLogin exchanges a password, a personal access token, or an
+// organization access token for a JWT.
+func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+	}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.checkCredentials(req.Username, req.Password) {
+		writeError(w, http.StatusUnauthorized, "Incorrect authentication credentials")
+		return
+	}
+	if u, ok := s.users[req.Username]; ok && u.totpSecret != "" && u.password == req.Password {
+		// Like Docker Hub, answer with a token to send along with the code.
+		login2FAToken := randomHex(16)
+		s.login2FATokens[login2FAToken] = req.Username
+		writeJSON(w, http.StatusUnauthorized, map[string]string{
+			"detail":          "Two-factor authentication is required",
+			"login_2fa_token": login2FAToken,
+		})
+		return
+	}
+
+	token, err := s.issueToken(req.Username)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"token": token})
+}
+
+// handle2FALogin completes a password login with a TOTP code.
+func (s *Server) handle2FALogin(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Login2FAToken string `json:"login_2fa_token"`
+		Code          string `json:"code"`
+	}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	username, ok := s.login2FATokens[req.Login2FAToken]
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "Invalid or expired login_2fa_token")
+		return
+	}
+	if !totp.Validate(s.users[username].totpSecret, req.Code, time.Now()) {
+		writeError(w, http.StatusUnauthorized, "Incorrect authentication code")
+		return
+	}
+	delete(s.login2FATokens, req.Login2FAToken)
+
+	token, err := s.issueToken(username)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"token": token})
+}
+
+func (s *Server) checkCredentials(username, password string) bool {
+	if u, ok := s.users[username]; ok {
+		if u.password == password {
+			return true
+		}
+		for _, pat := range s.accessTokens {
+			if pat.owner == username && pat.token.IsActive && pat.secret == password {
+				return true
+			}
+		}
+	}
+	if _, ok := s.orgs[username]; ok {
+		for _, oat := range s.orgAccessTokens {
+			if oat.org == username && oat.token.IsActive && oat.secret == password {
+				oat.token.LastUsedAt = timestamp(time.Now())
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (s *Server) issueToken(username string) (string, error) {
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: jose.HS256, Key: s.signingKey},
+		(&jose.SignerOptions{}).WithType("JWT"))
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	token, err := jwt.Signed(signer).Claims(jwt.Claims{
+		Subject:  username,
+		IssuedAt: jwt.NewNumericDate(now),
+		Expiry:   jwt.NewNumericDate(now.Add(s.TokenTTL)),
+	}).CompactSerialize()
+	if err != nil {
+		return "", err
+	}
+
+	s.sessions[token] = username
+	return token, nil
+}
+
+// authenticated rejects requests without a token issued by the login
+// endpoint, and passes the authenticated user name to the handler.
+func (s *Server) authenticated(handler func(w http.ResponseWriter, r *http.Request, principal string)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok {
+			writeError(w, http.StatusUnauthorized, "Authentication credentials were not provided")
+			return
+		}
+
+		s.mu.Lock()
+		principal, ok := s.sessions[token]
+		s.mu.Unlock()
+		if !ok || !s.tokenValid(token) {
+			writeError(w, http.StatusUnauthorized, "Invalid or expired token")
+			return
+		}
+
+		handler(w, r, principal)
+	}
+}
+
+func (s *Server) tokenValid(token string) bool {
+	parsed, err := jwt.ParseSigned(token)
+	if err != nil {
+		return false
+	}
+	var claims jwt.Claims
+	if err := parsed.Claims(s.signingKey, &claims); err != nil {
+		return false
+	}
+	return claims.ValidateWithLeeway(jwt.Expected{Time: time.Now()}, 0) == nil
+}
+
+// pageResponse is the envelope Docker Hub uses for paginated lists.
+type pageResponse[T any] struct {
+	Count    int     `json:"count"`
+	Next     *string `json:"next"`
+	Previous *string `json:"previous"`
+	Results  []T     `json:"results"`
+}
+
+// paginate returns the page of items requested by the page and page_size
+// query parameters. Like Docker Hub, the next and previous links are absolute
+// URLs.
+func paginate[T any](s *Server, r *http.Request, items []T) pageResponse[T] {
+	query := r.URL.Query()
+	pageSize, err := strconv.Atoi(query.Get("page_size"))
+	if err != nil || pageSize <= 0 {
+		pageSize = s.PageSize
+	}
+	page, err := strconv.Atoi(query.Get("page"))
+	if err != nil || page <= 0 {
+		page = 1
+	}
+
+	link := func(page int) *string {
+		q := r.URL.Query()
+		q.Set("page", strconv.Itoa(page))
+		q.Set("page_size", strconv.Itoa(pageSize))
+		u := fmt.Sprintf("%s%s?%s", s.URL, r.URL.Path, q.Encode())
+		return &u
+	}
+
+	resp := pageResponse[T]{
+		Count:   len(items),
+		Results: []T{},
+	}
+	start := (page - 1) * pageSize
+	if start < len(items) {
+		end := min(start+pageSize, len(items))
+		resp.Results = items[start:end]
+		if end < len(items) {
+			resp.Next = link(page + 1)
+		}
+	}
+	if page > 1 {
+		resp.Previous = link(page - 1)
+	}
+	return resp
+}
+
+// sortedKeys returns the keys of m in order, so that lists are stable across
+// pages.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func decodeBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if v != nil {
+		_ = json.NewEncoder(w).Encode(v)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"message": message})
+}
+
+func writeNotFound(w http.ResponseWriter) {
+	writeJSON(w, http.StatusNotFound, map[string]string{"detail": "Not found."})
+}
+
+func (s *Server) newID() int64 {
+	s.nextID++
+	return s.nextID
+}
+
+func newUUID() string {
+	b := randomHex(16)
+	return fmt.Sprintf("%s-%s-%s-%s-%s", b[0:8], b[8:12], b[12:16], b[16:20], b[20:32])
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+func timestamp(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
@@ -0,0 +1,679 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hubtest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/docker/terraform-provider-docker/internal/auth"
+	"github.com/docker/terraform-provider-docker/internal/hubclient"
+)
+
+func newTestClient(t *testing.T, server *Server, username, password string) *hubclient.Client {
+	t.Helper()
+	return hubclient.NewClient(hubclient.Config{
+		BaseURL:       server.BaseURL(),
+		TokenProvider: auth.NewLoginTokenProvider(username, auth.StaticPassword(password), server.BaseURL(), http.DefaultTransport),
+		Retry:         hubclient.RetryConfig{MaxAttempts: 1},
+	})
+}
+
+func newSeededServer(t *testing.T) *Server {
+	t.Helper()
+	server := NewServer()
+	t.Cleanup(server.Close)
+
+	server.AddUser("alice", "secret", "alice@example.com")
+	server.AddOrg("acme", "alice")
+	return server
+}
+
+func TestLogin(t *testing.T) {
+	ctx := context.Background()
+	server := newSeededServer(t)
+
+	_, err := newTestClient(t, server, "alice", "wrong").GetOrg(ctx, "acme")
+	if err == nil {
+		t.Fatal("expected login with a wrong password to fail")
+	}
+
+	org, err := newTestClient(t, server, "alice", "secret").GetOrg(ctx, "acme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if org.OrgName != "acme" {
+		t.Errorf("got org %q, want acme", org.OrgName)
+	}
+}
+
+func TestRevokedTokenIsReplaced(t *testing.T) {
+	ctx := context.Background()
+	server := newSeededServer(t)
+	client := newTestClient(t, server, "alice", "secret")
+
+	if _, err := client.GetOrg(ctx, "acme"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The cached token is rejected, the request is replayed after logging
+	// in again.
+	server.RevokeTokens()
+	if _, err := client.GetOrg(ctx, "acme"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUnauthenticated(t *testing.T) {
+	server := newSeededServer(t)
+
+	res, err := http.Get(server.BaseURL() + "/orgs/acme/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusUnauthorized {
+		t.Errorf("got status %d, want %d", res.StatusCode, http.StatusUnauthorized)
+	}
+}
+
+func TestPersonalAccessTokenLogin(t *testing.T) {
+	ctx := context.Background()
+	server := newSeededServer(t)
+	client := newTestClient(t, server, "alice", "secret")
+
+	pat, err := client.CreateAccessToken(ctx, hubclient.AccessTokenCreateParams{
+		TokenLabel: "ci",
+		Scopes:     []string{"repo:read"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	patClient := newTestClient(t, server, "alice", pat.Token)
+	tokens, err := patClient.GetAccessTokens(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tokens.Count != 1 || tokens.Results[0].UUID != pat.UUID {
+		t.Errorf("got tokens %+v, want only %s", tokens.Results, pat.UUID)
+	}
+	if tokens.Results[0].Token != "" {
+		t.Error("the token secret must only be returned on creation")
+	}
+
+	if _, err := client.UpdateAccessToken(ctx, pat.UUID, hubclient.AccessTokenUpdateParams{TokenLabel: "ci", IsActive: false}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newTestClient(t, server, "alice", pat.Token).GetAccessTokens(ctx); err == nil {
+		t.Error("expected login with an inactive token to fail")
+	}
+}
+
+func TestRepositoryPagination(t *testing.T) {
+	ctx := context.Background()
+	server := newSeededServer(t)
+	server.PageSize = 3
+	client := newTestClient(t, server, "alice", "secret")
+
+	for i := 0; i < 8; i++ {
+		_, err := client.CreateRepository(ctx, "acme", hubclient.CreateRepositoryRequest{
+			Name: fmt.Sprintf("repo%d", i),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	repos, err := client.GetRepositories(ctx, "acme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if repos.Count != 8 {
+		t.Errorf("got %d repositories, want 8", repos.Count)
+	}
+	for i, repo := range repos.Results {
+		if want := fmt.Sprintf("repo%d", i); repo.Name != want {
+			t.Errorf("repository %d: got %q, want %q", i, repo.Name, want)
+		}
+	}
+
+	limited := hubclient.NewClient(hubclient.Config{
+		BaseURL:        server.BaseURL(),
+		TokenProvider:  auth.NewLoginTokenProvider("alice", auth.StaticPassword("secret"), server.BaseURL(), http.DefaultTransport),
+		MaxPageResults: 2,
+	})
+	repos, err = limited.GetRepositories(ctx, "acme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if repos.Count != 6 {
+		t.Errorf("got %d repositories with 2 pages, want 6", repos.Count)
+	}
+}
+
+func TestRepositoryLifecycle(t *testing.T) {
+	ctx := context.Background()
+	server := newSeededServer(t)
+	client := newTestClient(t, server, "alice", "secret")
+
+	_, err := client.CreateRepository(ctx, "acme", hubclient.CreateRepositoryRequest{Name: "app"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.CreateRepository(ctx, "acme", hubclient.CreateRepositoryRequest{Name: "app"})
+	if !hubclient.IsConflict(err) {
+		t.Errorf("got %v, want a conflict error", err)
+	}
+
+	repo, err := client.UpdateRepository(ctx, "acme/app", hubclient.UpdateRepositoryRequest{
+		Description:        "An app",
+		ImmutableTags:      true,
+		ImmutableTagsRules: "v.*,latest",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !repo.ImmutableTagsSettings.Enabled || len(repo.ImmutableTagsSettings.Rules) != 2 {
+		t.Errorf("got immutable tags settings %+v", repo.ImmutableTagsSettings)
+	}
+
+	if err := client.SetRepositoryPrivacy(ctx, "acme/app", true); err != nil {
+		t.Fatal(err)
+	}
+	repo, err = client.GetRepository(ctx, "acme/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !repo.IsPrivate || repo.Description != "An app" {
+		t.Errorf("got repository %+v", repo)
+	}
+
+	if err := client.DeleteRepository(ctx, "acme/app"); err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.GetRepository(ctx, "acme/app")
+	if !hubclient.IsNotFound(err) {
+		t.Errorf("got %v, want a not found error", err)
+	}
+}
+
+func TestRepositoryTags(t *testing.T) {
+	ctx := context.Background()
+	server := newSeededServer(t)
+	server.AddRepository(hubclient.Repository{Namespace: "library", Name: "hello-world"})
+	server.AddTag("library", "hello-world", hubclient.Tag{Name: "linux", LastUpdated: "2024-01-01T00:00:00Z"})
+	server.AddTag("library", "hello-world", hubclient.Tag{Name: "latest", LastUpdated: "2024-06-01T00:00:00Z"})
+	client := newTestClient(t, server, "alice", "secret")
+
+	tags, err := client.GetRepositoryTags(ctx, "library", "hello-world")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tags.Count != 2 || tags.Results[0].Name != "latest" {
+		t.Errorf("got tags %+v, want the most recent first", tags.Results)
+	}
+
+	tags, err = client.ListRepositoryTags(ctx, "library", "hello-world", hubclient.ListRepositoryTagsParams{
+		Ordering: hubclient.TagOrderingName,
+		Limit:    1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tags.Count != 1 || tags.Results[0].Name != "latest" {
+		t.Errorf("got tags %+v, want the first tag by name", tags.Results)
+	}
+	tags, err = client.ListRepositoryTags(ctx, "library", "hello-world", hubclient.ListRepositoryTagsParams{Name: "nu"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tags.Count != 1 || tags.Results[0].Name != "linux" {
+		t.Errorf("got tags %+v, want the tags containing the name", tags.Results)
+	}
+
+	tag, err := client.GetRepositoryTag(ctx, "library", "hello-world", "linux")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tag.Digest == "" || len(tag.Images) != 1 {
+		t.Errorf("got tag %+v, want a digest and an image", tag)
+	}
+
+	if _, err := client.UpdateRepository(ctx, "library/hello-world", hubclient.UpdateRepositoryRequest{
+		ImmutableTags:      true,
+		ImmutableTagsRules: "latest",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.DeleteRepositoryTag(ctx, "library", "hello-world", "latest"); !hubclient.IsForbidden(err) {
+		t.Errorf("got %v, want a forbidden error for an immutable tag", err)
+	}
+	if err := client.DeleteRepositoryTag(ctx, "library", "hello-world", "linux"); err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.GetRepositoryTag(ctx, "library", "hello-world", "linux")
+	if !hubclient.IsNotFound(err) {
+		t.Errorf("got %v, want a not found error", err)
+	}
+}
+
+func TestRepositoryTagsTruncated(t *testing.T) {
+	ctx := context.Background()
+	server := newSeededServer(t)
+	server.AddRepository(hubclient.Repository{Namespace: "acme", Name: "app"})
+	// One more tag than fits in the largest page.
+	for i := 0; i < 101; i++ {
+		server.AddTag("acme", "app", hubclient.Tag{Name: fmt.Sprintf("v%d", i)})
+	}
+	client := hubclient.NewClient(hubclient.Config{
+		BaseURL:        server.BaseURL(),
+		TokenProvider:  auth.NewLoginTokenProvider("alice", auth.StaticPassword("secret"), server.BaseURL(), http.DefaultTransport),
+		MaxPageResults: 1,
+	})
+
+	tags, err := client.GetRepositoryTags(ctx, "acme", "app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tags.Count != 100 || !tags.Truncated {
+		t.Errorf("got %d tags, truncated %v, want 100 truncated", tags.Count, tags.Truncated)
+	}
+
+	// Stopping at the limit is not a truncation.
+	tags, err = client.ListRepositoryTags(ctx, "acme", "app", hubclient.ListRepositoryTagsParams{Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tags.Count != 10 || tags.Truncated {
+		t.Errorf("got %d tags, truncated %v, want 10 not truncated", tags.Count, tags.Truncated)
+	}
+}
+
+func TestRepositoryWebhooks(t *testing.T) {
+	ctx := context.Background()
+	server := newSeededServer(t)
+	server.PageSize = 1
+	server.AddRepository(hubclient.Repository{Namespace: "acme", Name: "app"})
+	client := newTestClient(t, server, "alice", "secret")
+
+	created, err := client.CreateRepositoryWebhook(ctx, "acme/app", hubclient.NewRepositoryWebhookParams("CI Build", "https://ci.example.com/hook", false, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Slug != "ci-build" || created.Registry != hubclient.DefaultWebhookRegistry {
+		t.Errorf("got webhook %+v, want a slug and the default registry", created)
+	}
+	_, err = client.CreateRepositoryWebhook(ctx, "acme/app", hubclient.NewRepositoryWebhookParams("CI Build", "https://ci.example.com/hook", false, ""))
+	if !hubclient.IsConflict(err) {
+		t.Errorf("got %v, want a conflict error", err)
+	}
+	if _, err := client.CreateRepositoryWebhook(ctx, "acme/app", hubclient.NewRepositoryWebhookParams("deploy", "https://cd.example.com/hook", true, "")); err != nil {
+		t.Fatal(err)
+	}
+
+	webhooks, err := client.ListRepositoryWebhooks(ctx, "acme/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if webhooks.Count != 2 {
+		t.Errorf("got %d webhooks across pages, want 2", webhooks.Count)
+	}
+
+	updated, err := client.UpdateRepositoryWebhook(ctx, "acme/app", "ci-build", hubclient.NewRepositoryWebhookParams("CI Build", "https://ci.example.com/v2", true, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.URL() != "https://ci.example.com/v2" || !updated.ExpectFinalCallback {
+		t.Errorf("got webhook %+v", updated)
+	}
+
+	if err := client.DeleteRepositoryWebhook(ctx, "acme/app", "ci-build"); err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.GetRepositoryWebhook(ctx, "acme/app", "ci-build")
+	if !hubclient.IsNotFound(err) {
+		t.Errorf("got %v, want a not found error", err)
+	}
+}
+
+func TestRepositoryCollaborators(t *testing.T) {
+	ctx := context.Background()
+	server := newSeededServer(t)
+	server.AddUser("bob", "secret", "bob@example.com")
+	server.AddRepository(hubclient.Repository{Namespace: "alice", Name: "app"})
+	client := newTestClient(t, server, "alice", "secret")
+
+	if _, err := client.CreateRepositoryCollaborator(ctx, "alice/app", "nobody", hubclient.TeamRepoPermissionLevelRead); err == nil {
+		t.Error("expected adding an unknown user to fail")
+	}
+	if _, err := client.CreateRepositoryCollaborator(ctx, "alice/app", "bob", hubclient.TeamRepoPermissionLevelRead); err != nil {
+		t.Fatal(err)
+	}
+	collaborator, err := client.UpdateRepositoryCollaborator(ctx, "alice/app", "bob", hubclient.TeamRepoPermissionLevelWrite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if collaborator.User != "bob" || collaborator.Permission != hubclient.TeamRepoPermissionLevelWrite {
+		t.Errorf("got collaborator %+v", collaborator)
+	}
+
+	if err := client.DeleteRepositoryCollaborator(ctx, "alice/app", "bob"); err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.GetRepositoryCollaborator(ctx, "alice/app", "bob")
+	if !hubclient.IsNotFound(err) {
+		t.Errorf("got %v, want a not found error", err)
+	}
+}
+
+func TestTeamsAndPermissions(t *testing.T) {
+	ctx := context.Background()
+	server := newSeededServer(t)
+	server.AddUser("bob", "secret", "bob@example.com")
+	client := newTestClient(t, server, "alice", "secret")
+
+	team, err := client.CreateOrgTeam(ctx, "acme", hubclient.OrgTeam{Name: "devs"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.AddOrgTeamMember(ctx, "acme", "devs", "bob"); err != nil {
+		t.Fatal(err)
+	}
+	members, err := client.ListOrgTeamMembers(ctx, "acme", "devs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(members) != 1 || members[0].Username != "bob" {
+		t.Errorf("got team members %+v, want bob", members)
+	}
+
+	if _, err := client.CreateRepository(ctx, "acme", hubclient.CreateRepositoryRequest{Name: "app"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.CreatePermissionForTeamAndRepo(ctx, "acme/app", team.ID, hubclient.TeamRepoPermissionLevelRead); err != nil {
+		t.Fatal(err)
+	}
+	perm, err := client.UpdatePermissionForTeamAndRepo(ctx, "acme/app", team.ID, hubclient.TeamRepoPermissionLevelAdmin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm.TeamName != "devs" || perm.Permission != hubclient.TeamRepoPermissionLevelAdmin {
+		t.Errorf("got permission %+v", perm)
+	}
+	perms, err := client.ListPermissionsForRepo(ctx, "acme/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perms.Count != 1 || perms.Results[0].TeamID != team.ID {
+		t.Errorf("got permissions %+v, want only devs", perms.Results)
+	}
+
+	if err := client.DeleteOrgTeam(ctx, "acme", "devs"); err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.GetPermissionForTeamAndRepo(ctx, "acme/app", team.ID)
+	if !hubclient.IsNotFound(err) {
+		t.Errorf("got %v, want a not found error once the team is deleted", err)
+	}
+}
+
+func TestTeamMembersTruncated(t *testing.T) {
+	ctx := context.Background()
+	server := newSeededServer(t)
+	client := newTestClient(t, server, "alice", "secret")
+	if _, err := client.CreateOrgTeam(ctx, "acme", hubclient.OrgTeam{Name: "devs"}); err != nil {
+		t.Fatal(err)
+	}
+	// One more member than fits in the largest page.
+	for i := 0; i < 101; i++ {
+		name := fmt.Sprintf("user%d", i)
+		server.AddUser(name, "secret", name+"@example.com")
+		server.AddOrgMember("acme", name, hubclient.OrgRoleParamMember)
+		if err := client.AddOrgTeamMember(ctx, "acme", "devs", name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	limited := func(maxPageResults int64) *hubclient.Client {
+		return hubclient.NewClient(hubclient.Config{
+			BaseURL:        server.BaseURL(),
+			TokenProvider:  auth.NewLoginTokenProvider("alice", auth.StaticPassword("secret"), server.BaseURL(), http.DefaultTransport),
+			MaxPageResults: maxPageResults,
+		})
+	}
+	_, err := limited(1).ListOrgTeamMembers(ctx, "acme", "devs")
+	if !hubclient.IsTruncated(err) {
+		t.Errorf("got %v, want a truncated error with one page", err)
+	}
+	members, err := limited(2).ListOrgTeamMembers(ctx, "acme", "devs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(members) != 101 {
+		t.Errorf("got %d team members with two pages, want 101", len(members))
+	}
+}
+
+func TestTeamPermissionsTruncated(t *testing.T) {
+	ctx := context.Background()
+	server := newSeededServer(t)
+	server.PageSize = 2
+	server.AddRepository(hubclient.Repository{Namespace: "acme", Name: "app"})
+	client := newTestClient(t, server, "alice", "secret")
+	for i := 0; i < 3; i++ {
+		team, err := client.CreateOrgTeam(ctx, "acme", hubclient.OrgTeam{Name: fmt.Sprintf("team%d", i)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := client.CreatePermissionForTeamAndRepo(ctx, "acme/app", team.ID, hubclient.TeamRepoPermissionLevelRead); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	limited := func(maxPageResults int64) *hubclient.Client {
+		return hubclient.NewClient(hubclient.Config{
+			BaseURL:        server.BaseURL(),
+			TokenProvider:  auth.NewLoginTokenProvider("alice", auth.StaticPassword("secret"), server.BaseURL(), http.DefaultTransport),
+			MaxPageResults: maxPageResults,
+		})
+	}
+	_, err := limited(1).ListPermissionsForRepo(ctx, "acme/app")
+	if !hubclient.IsTruncated(err) {
+		t.Errorf("got %v, want a truncated error with one page", err)
+	}
+	permissions, err := limited(2).ListPermissionsForRepo(ctx, "acme/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if permissions.Count != 3 {
+		t.Errorf("got %d team permissions with two pages, want 3", permissions.Count)
+	}
+}
+
+func TestOrgMembersAndInvites(t *testing.T) {
+	ctx := context.Background()
+	server := newSeededServer(t)
+	server.PageSize = 2
+	for i := 0; i < 4; i++ {
+		name := fmt.Sprintf("user%d", i)
+		server.AddUser(name, "secret", name+"@example.com")
+		server.AddOrgMember("acme", name, hubclient.OrgRoleParamMember)
+	}
+	client := newTestClient(t, server, "alice", "secret")
+
+	members, err := client.ListOrgMembers(ctx, "acme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(members) != 5 {
+		t.Fatalf("got %d members, want 5", len(members))
+	}
+	if members[0].Username != "alice" || members[0].Role != "Owner" {
+		t.Errorf("got first member %+v, want alice as owner", members[0])
+	}
+
+	if err := client.UpdateOrgMember(ctx, "acme", "user0", hubclient.OrgRoleParamEditor); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.DeleteOrgMember(ctx, "acme", "user1"); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := client.InviteOrgMember(ctx, "acme", string(hubclient.OrgRoleParamMember), []string{"new@example.com"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.OrgInvitees) != 1 || resp.OrgInvitees[0].Invite.ID == "" {
+		t.Fatalf("got invite response %+v", resp)
+	}
+	invites, err := client.ListOrgInvites(ctx, "acme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(invites) != 1 || invites[0].Invitee != "new@example.com" {
+		t.Errorf("got invites %+v", invites)
+	}
+	if err := client.DeleteOrgInvite(ctx, invites[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	err = client.DeleteOrgInvite(ctx, invites[0].ID)
+	if !hubclient.IsNotFound(err) {
+		t.Errorf("got %v, want a not found error", err)
+	}
+}
+
+func TestOrgAccessTokens(t *testing.T) {
+	ctx := context.Background()
+	server := newSeededServer(t)
+	client := newTestClient(t, server, "alice", "secret")
+
+	oat, err := client.CreateOrgAccessToken(ctx, "acme", hubclient.OrgAccessTokenCreateParams{
+		Label: "ci",
+		Resources: []hubclient.OrgAccessTokenResource{{
+			Type:   hubclient.OrgAccessTokenTypeRepo,
+			Path:   "acme/app",
+			Scopes: []string{"repo-pull"},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if oat.Token == "" {
+		t.Fatal("expected the token secret on creation")
+	}
+
+	// Organization access tokens log in with the organization name.
+	if _, err := newTestClient(t, server, "acme", oat.Token).GetOrg(ctx, "acme"); err != nil {
+		t.Fatal(err)
+	}
+
+	unused, err := client.CreateOrgAccessToken(ctx, "acme", hubclient.OrgAccessTokenCreateParams{
+		Label: "unused",
+		Resources: []hubclient.OrgAccessTokenResource{{
+			Type:   hubclient.OrgAccessTokenTypeOrg,
+			Path:   "*",
+			Scopes: []string{"member-read"},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.PageSize = 1
+	tokens, err := client.ListOrgAccessTokens(ctx, "acme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tokens) != 2 || tokens[0].ID != unused.ID || tokens[1].ID != oat.ID {
+		t.Fatalf("got tokens %+v, want the unused token first", tokens)
+	}
+	if tokens[0].Token != "" || tokens[1].Token != "" {
+		t.Error("expected listed tokens without their secret")
+	}
+	if tokens[0].LastUsedAt != "" || tokens[1].LastUsedAt == "" {
+		t.Errorf("got last used %q and %q, want only the second set", tokens[0].LastUsedAt, tokens[1].LastUsedAt)
+	}
+	limited := hubclient.NewClient(hubclient.Config{
+		BaseURL:        server.BaseURL(),
+		TokenProvider:  auth.NewLoginTokenProvider("alice", auth.StaticPassword("secret"), server.BaseURL(), http.DefaultTransport),
+		MaxPageResults: 1,
+	})
+	if _, err := limited.ListOrgAccessTokens(ctx, "acme"); !hubclient.IsTruncated(err) {
+		t.Errorf("got %v, want a truncated error with one page", err)
+	}
+
+	inactive := false
+	deactivated, err := client.UpdateOrgAccessToken(ctx, "acme", oat.ID, hubclient.OrgAccessTokenUpdateParams{
+		Label:     oat.Label,
+		Resources: oat.Resources,
+		IsActive:  &inactive,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deactivated.IsActive {
+		t.Error("expected the token to be deactivated")
+	}
+	// Updates without is_active leave it as is.
+	relabeled, err := client.UpdateOrgAccessToken(ctx, "acme", oat.ID, hubclient.OrgAccessTokenUpdateParams{
+		Label:     "relabeled",
+		Resources: oat.Resources,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if relabeled.IsActive {
+		t.Error("expected an update without is_active to keep the token deactivated")
+	}
+	if _, err := newTestClient(t, server, "acme", oat.Token).GetOrg(ctx, "acme"); err == nil {
+		t.Error("expected login with an inactive token to fail")
+	}
+
+	if err := client.DeleteOrgAccessToken(ctx, "acme", oat.ID); err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.GetOrgAccessToken(ctx, "acme", oat.ID)
+	if !hubclient.IsNotFound(err) {
+		t.Errorf("got %v, want a not found error", err)
+	}
+}
+
+func TestOrgSettings(t *testing.T) {
+	ctx := context.Background()
+	server := newSeededServer(t)
+	client := newTestClient(t, server, "alice", "secret")
+
+	iam, err := client.SetOrgSettingImageAccessManagement(ctx, "acme", hubclient.OrgSettingImageAccessManagement{
+		RestrictedImages: hubclient.ImageAccessManagementRestrictedImages{Enabled: true, AllowOfficialImages: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !iam.RestrictedImages.Enabled || !iam.RestrictedImages.AllowOfficialImages {
+		t.Errorf("got image access management settings %+v", iam)
+	}
+
+	ram, err := client.GetOrgSettingRegistryAccessManagement(ctx, "acme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ram.Enabled || len(ram.StandardRegistries) != 1 {
+		t.Errorf("got default registry access management settings %+v", ram)
+	}
+}
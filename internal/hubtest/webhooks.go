@@ -0,0 +1,187 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hubtest
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/docker/terraform-provider-docker/internal/hubclient"
+)
+
+var slugInvalidChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// webhookSlug derives the slug of a webhook pipeline from its name, the way
+// Docker Hub does.
+func webhookSlug(name string) string {
+	return strings.Trim(slugInvalidChars.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
+
+// validateWebhookParams checks the parameters of a create or update request.
+func validateWebhookParams(w http.ResponseWriter, req hubclient.RepositoryWebhookParams) bool {
+	if len(req.Webhooks) == 0 {
+		writeError(w, http.StatusBadRequest, "webhooks: This list may not be empty.")
+		return false
+	}
+	for _, hook := range req.Webhooks {
+		u, err := url.Parse(hook.HookURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			writeError(w, http.StatusBadRequest, "hook_url: Enter a valid URL.")
+			return false
+		}
+	}
+	return true
+}
+
+// lookupWebhook finds the webhook of the repository in the request path.
+func (s *Server) lookupWebhook(w http.ResponseWriter, r *http.Request) (*repository, int, bool) {
+	repo, ok := s.lookupRepository(w, r)
+	if !ok {
+		return nil, 0, false
+	}
+	for i, webhook := range repo.webhooks {
+		if webhook.Slug == r.PathValue("slug") {
+			return repo, i, true
+		}
+	}
+	writeNotFound(w)
+	return nil, 0, false
+}
+
+func (s *Server) handleListWebhooks(w http.ResponseWriter, r *http.Request, _ string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repo, ok := s.lookupRepository(w, r)
+	if !ok {
+		return
+	}
+	webhooks := append([]hubclient.RepositoryWebhook{}, repo.webhooks...)
+	writeJSON(w, http.StatusOK, paginate(s, r, webhooks))
+}
+
+func (s *Server) handleCreateWebhook(w http.ResponseWriter, r *http.Request, principal string) {
+	var req hubclient.RepositoryWebhookParams
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if req.Name == "" {
+		writeError(w, http.StatusBadRequest, "name: This field may not be blank.")
+		return
+	}
+	if !validateWebhookParams(w, req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repo, ok := s.lookupRepository(w, r)
+	if !ok {
+		return
+	}
+	slug := webhookSlug(req.Name)
+	for _, webhook := range repo.webhooks {
+		if webhook.Name == req.Name || webhook.Slug == slug {
+			writeError(w, http.StatusConflict, "Webhook with this name already exists.")
+			return
+		}
+	}
+	if req.Registry == "" {
+		req.Registry = hubclient.DefaultWebhookRegistry
+	}
+
+	now := timestamp(time.Now())
+	webhook := hubclient.RepositoryWebhook{
+		ID:                  s.newID(),
+		Name:                req.Name,
+		Slug:                slug,
+		ExpectFinalCallback: req.ExpectFinalCallback,
+		Registry:            req.Registry,
+		Creator:             principal,
+		Created:             now,
+		LastUpdated:         now,
+	}
+	for _, hook := range req.Webhooks {
+		webhook.Webhooks = append(webhook.Webhooks, hubclient.RepositoryWebhookHook{
+			ID:      s.newID(),
+			Name:    hook.Name,
+			HookURL: hook.HookURL,
+		})
+	}
+	repo.webhooks = append(repo.webhooks, webhook)
+	writeJSON(w, http.StatusCreated, webhook)
+}
+
+func (s *Server) handleGetWebhook(w http.ResponseWriter, r *http.Request, _ string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repo, i, ok := s.lookupWebhook(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, repo.webhooks[i])
+}
+
+func (s *Server) handleUpdateWebhook(w http.ResponseWriter, r *http.Request, _ string) {
+	var req hubclient.RepositoryWebhookParams
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if !validateWebhookParams(w, req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repo, i, ok := s.lookupWebhook(w, r)
+	if !ok {
+		return
+	}
+	webhook := &repo.webhooks[i]
+	webhook.ExpectFinalCallback = req.ExpectFinalCallback
+	if req.Registry != "" {
+		webhook.Registry = req.Registry
+	}
+	webhook.Webhooks = nil
+	for _, hook := range req.Webhooks {
+		webhook.Webhooks = append(webhook.Webhooks, hubclient.RepositoryWebhookHook{
+			ID:      s.newID(),
+			Name:    hook.Name,
+			HookURL: hook.HookURL,
+		})
+	}
+	webhook.LastUpdated = timestamp(time.Now())
+	writeJSON(w, http.StatusOK, webhook)
+}
+
+func (s *Server) handleDeleteWebhook(w http.ResponseWriter, r *http.Request, _ string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repo, i, ok := s.lookupWebhook(w, r)
+	if !ok {
+		return
+	}
+	repo.webhooks = append(repo.webhooks[:i], repo.webhooks[i+1:]...)
+	writeJSON(w, http.StatusNoContent, nil)
+}
@@ -0,0 +1,167 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package imageref parses image references such as "nginx",
+// "docker.io/library/nginx:1.27" or "ghcr.io/org/app@sha256:...", following
+// the normalization rules of the docker CLI.
+package imageref
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	DefaultRegistry  = "docker.io"
+	DefaultNamespace = "library"
+	DefaultTag       = "latest"
+
+	maxNameLength = 255
+)
+
+var (
+	// dockerHubAliases are the other host names Docker Hub is known by.
+	dockerHubAliases = map[string]bool{
+		"index.docker.io":         true,
+		"registry-1.docker.io":    true,
+		"registry.hub.docker.com": true,
+	}
+
+	domainRegexp    = regexp.MustCompile(`^(?:[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?)(?:\.[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?)*(?::[0-9]+)?$`)
+	componentRegexp = regexp.MustCompile(`^[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*$`)
+	tagRegexp       = regexp.MustCompile(`^[\w][\w.-]{0,127}$`)
+	digestRegexp    = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*(?:[-_+.][A-Za-z][A-Za-z0-9]*)*:[0-9a-fA-F]{32,}$`)
+)
+
+// Reference is a parsed and normalized image reference.
+type Reference struct {
+	// Registry is the registry host, such as "docker.io" or "ghcr.io:443".
+	Registry string
+	// Namespace is everything between the registry and the repository
+	// name, such as "library". It may be empty for other registries.
+	Namespace string
+	// Repository is the last path component of the image name.
+	Repository string
+	// Tag is the tag, "latest" if the reference has neither a tag nor a
+	// digest.
+	Tag string
+	// Digest is the content digest, such as "sha256:...".
+	Digest string
+}
+
+// Path returns the repository path within the registry, such as
+// "library/nginx".
+func (r Reference) Path() string {
+	if r.Namespace == "" {
+		return r.Repository
+	}
+	return r.Namespace + "/" + r.Repository
+}
+
+// Name returns the fully qualified image name, without tag or digest.
+func (r Reference) Name() string {
+	return r.Registry + "/" + r.Path()
+}
+
+// String returns the fully qualified reference.
+func (r Reference) String() string {
+	s := r.Name()
+	if r.Tag != "" {
+		s += ":" + r.Tag
+	}
+	if r.Digest != "" {
+		s += "@" + r.Digest
+	}
+	return s
+}
+
+// Parse parses an image reference. References without a registry default to
+// Docker Hub, and single component Docker Hub names to the library
+// namespace.
+func Parse(ref string) (Reference, error) {
+	if ref == "" {
+		return Reference{}, fmt.Errorf("image reference is empty")
+	}
+
+	var result Reference
+	remainder := ref
+
+	if i := strings.Index(remainder, "@"); i >= 0 {
+		result.Digest = remainder[i+1:]
+		remainder = remainder[:i]
+		if !digestRegexp.MatchString(result.Digest) {
+			return Reference{}, fmt.Errorf("invalid digest %q in image reference %q", result.Digest, ref)
+		}
+	}
+
+	if i := strings.LastIndex(remainder, ":"); i > strings.LastIndex(remainder, "/") {
+		result.Tag = remainder[i+1:]
+		remainder = remainder[:i]
+		if !tagRegexp.MatchString(result.Tag) {
+			return Reference{}, fmt.Errorf("invalid tag %q in image reference %q", result.Tag, ref)
+		}
+	}
+
+	result.Registry = DefaultRegistry
+	path := remainder
+	if i := strings.Index(remainder, "/"); i >= 0 && isDomain(remainder[:i]) {
+		result.Registry = remainder[:i]
+		path = remainder[i+1:]
+		if !domainRegexp.MatchString(result.Registry) {
+			return Reference{}, fmt.Errorf("invalid registry %q in image reference %q", result.Registry, ref)
+		}
+		if dockerHubAliases[result.Registry] {
+			result.Registry = DefaultRegistry
+		}
+	}
+
+	if path == "" {
+		return Reference{}, fmt.Errorf("image reference %q has no repository name", ref)
+	}
+	for _, component := range strings.Split(path, "/") {
+		if !componentRegexp.MatchString(component) {
+			return Reference{}, fmt.Errorf("invalid repository name %q in image reference %q: must be lowercase letters, digits and separators", path, ref)
+		}
+	}
+	if len(path) > maxNameLength {
+		return Reference{}, fmt.Errorf("repository name in image reference %q is longer than %d characters", ref, maxNameLength)
+	}
+
+	if result.Registry == DefaultRegistry && !strings.Contains(path, "/") {
+		path = DefaultNamespace + "/" + path
+	}
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		result.Namespace = path[:i]
+		result.Repository = path[i+1:]
+	} else {
+		result.Repository = path
+	}
+
+	if result.Tag == "" && result.Digest == "" {
+		result.Tag = DefaultTag
+	}
+
+	return result, nil
+}
+
+// isDomain reports whether the first component of a reference is a registry
+// host rather than a namespace, using the same rules as the docker CLI.
+func isDomain(component string) bool {
+	return strings.ContainsAny(component, ".:") ||
+		component == "localhost" ||
+		strings.ToLower(component) != component
+}
@@ -0,0 +1,86 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package imageref
+
+import (
+	"testing"
+)
+
+const testDigest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want Reference
+	}{
+		{"nginx", Reference{Registry: "docker.io", Namespace: "library", Repository: "nginx", Tag: "latest"}},
+		{"nginx:1.27", Reference{Registry: "docker.io", Namespace: "library", Repository: "nginx", Tag: "1.27"}},
+		{"docker/app", Reference{Registry: "docker.io", Namespace: "docker", Repository: "app", Tag: "latest"}},
+		{"docker.io/docker/app:v1", Reference{Registry: "docker.io", Namespace: "docker", Repository: "app", Tag: "v1"}},
+		{"index.docker.io/nginx", Reference{Registry: "docker.io", Namespace: "library", Repository: "nginx", Tag: "latest"}},
+		{"registry-1.docker.io/library/nginx", Reference{Registry: "docker.io", Namespace: "library", Repository: "nginx", Tag: "latest"}},
+		{"nginx@" + testDigest, Reference{Registry: "docker.io", Namespace: "library", Repository: "nginx", Digest: testDigest}},
+		{"nginx:1.27@" + testDigest, Reference{Registry: "docker.io", Namespace: "library", Repository: "nginx", Tag: "1.27", Digest: testDigest}},
+		{"ghcr.io/org/team/app:main", Reference{Registry: "ghcr.io", Namespace: "org/team", Repository: "app", Tag: "main"}},
+		{"localhost:5000/app", Reference{Registry: "localhost:5000", Repository: "app", Tag: "latest"}},
+		{"localhost/app:1", Reference{Registry: "localhost", Repository: "app", Tag: "1"}},
+		{"Registry.example.com/app", Reference{Registry: "Registry.example.com", Repository: "app", Tag: "latest"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			got, err := Parse(tt.ref)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseString(t *testing.T) {
+	got, err := Parse("nginx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "docker.io/library/nginx:latest"; got.String() != want {
+		t.Errorf("got %q, want %q", got.String(), want)
+	}
+	if want := "library/nginx"; got.Path() != want {
+		t.Errorf("got path %q, want %q", got.Path(), want)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, ref := range []string{
+		"",
+		"Nginx",
+		"nginx:",
+		"nginx:-bad",
+		"nginx@sha256:short",
+		"docker.io/",
+		"org//app",
+		"ghcr.io/org/App",
+	} {
+		t.Run(ref, func(t *testing.T) {
+			if got, err := Parse(ref); err == nil {
+				t.Errorf("expected an error, got %+v", got)
+			}
+		})
+	}
+}
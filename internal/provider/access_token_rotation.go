@@ -0,0 +1,172 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package provider
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// AccessTokenRotationModel is the rotation block shared by the personal and
+// organization access token resources.
+type AccessTokenRotationModel struct {
+	RotateAfter types.String `tfsdk:"rotate_after"`
+	Overlap     types.String `tfsdk:"overlap"`
+}
+
+// accessTokenRotation is the parsed form of AccessTokenRotationModel.
+type accessTokenRotation struct {
+	rotateAfter time.Duration
+	overlap     time.Duration
+}
+
+func accessTokenRotationBlock() schema.SingleNestedBlock {
+	return schema.SingleNestedBlock{
+		MarkdownDescription: "Rotate the token on a schedule tracked from its creation time. Once `rotate_after` has passed, the next apply creates a replacement token. The replaced token stays valid as `previous_token` until `overlap` has passed since the replacement was created, and is revoked by the first apply after that.",
+		Attributes: map[string]schema.Attribute{
+			"rotate_after": schema.StringAttribute{
+				MarkdownDescription: "Age after which the token is replaced, e.g. `720h`. Required when the block is set.",
+				Optional:            true,
+				Validators: []validator.String{
+					durationValidator,
+				},
+			},
+			"overlap": schema.StringAttribute{
+				MarkdownDescription: "How long the replaced token stays valid after rotation, e.g. `24h`. Must be shorter than `rotate_after`. If not set, the replaced token is revoked as soon as the replacement is created.",
+				Optional:            true,
+				Validators: []validator.String{
+					durationValidator,
+				},
+			},
+		},
+	}
+}
+
+// expandAccessTokenRotation parses a configured rotation block. It returns
+// false when rotation is not configured or its values are not yet known.
+func expandAccessTokenRotation(m *AccessTokenRotationModel) (accessTokenRotation, bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if m == nil || m.RotateAfter.IsUnknown() || m.Overlap.IsUnknown() {
+		return accessTokenRotation{}, false, diags
+	}
+
+	if m.RotateAfter.IsNull() {
+		diags.AddAttributeError(
+			path.Root("rotation").AtName("rotate_after"),
+			"Missing Rotation Schedule",
+			"The rotation block requires rotate_after to be set.",
+		)
+		return accessTokenRotation{}, false, diags
+	}
+
+	var rotation accessTokenRotation
+	var err error
+	if rotation.rotateAfter, err = time.ParseDuration(m.RotateAfter.ValueString()); err != nil {
+		// Reported by the attribute validator.
+		return accessTokenRotation{}, false, diags
+	}
+	if !m.Overlap.IsNull() {
+		if rotation.overlap, err = time.ParseDuration(m.Overlap.ValueString()); err != nil {
+			return accessTokenRotation{}, false, diags
+		}
+	}
+
+	if rotation.overlap >= rotation.rotateAfter {
+		diags.AddAttributeError(
+			path.Root("rotation").AtName("overlap"),
+			"Invalid Rotation Overlap",
+			fmt.Sprintf("The overlap (%s) must be shorter than rotate_after (%s).", rotation.overlap, rotation.rotateAfter),
+		)
+		return accessTokenRotation{}, false, diags
+	}
+
+	return rotation, true, diags
+}
+
+// due reports whether a token created at createdAt should be replaced at now.
+// Tokens with an unknown creation time are never rotated.
+func (r accessTokenRotation) due(createdAt types.String, now time.Time) bool {
+	created, ok := parseCreatedAt(createdAt)
+	return ok && !now.Before(created.Add(r.rotateAfter))
+}
+
+// overlapEnded reports whether the token replaced by one created at createdAt
+// should be revoked at now.
+func (r accessTokenRotation) overlapEnded(createdAt types.String, now time.Time) bool {
+	created, ok := parseCreatedAt(createdAt)
+	return !ok || !now.Before(created.Add(r.overlap))
+}
+
+func parseCreatedAt(createdAt types.String) (time.Time, bool) {
+	if createdAt.IsNull() || createdAt.IsUnknown() {
+		return time.Time{}, false
+	}
+	return parseTimestamp(createdAt.ValueString())
+}
+
+// parseTimestamp parses an RFC 3339 timestamp as returned by Docker Hub.
+func parseTimestamp(value string) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339Nano, value)
+	return t, err == nil
+}
+
+// accessTokenRotationPlan is the outcome of planAccessTokenRotation.
+type accessTokenRotationPlan int
+
+const (
+	accessTokenRotationNone accessTokenRotationPlan = iota
+	// accessTokenRotationRotate creates a replacement token.
+	accessTokenRotationRotate
+	// accessTokenRotationRevokePrevious revokes the token that was replaced.
+	accessTokenRotationRevokePrevious
+)
+
+// planAccessTokenRotation decides what an update of a token created at
+// createdAt has to do for rotation at now. A previous token is revoked once
+// the overlap has ended, or straight away when rotation is no longer
+// configured.
+func planAccessTokenRotation(m *AccessTokenRotationModel, createdAt types.String, hasPrevious bool, now time.Time) (accessTokenRotation, accessTokenRotationPlan, diag.Diagnostics) {
+	rotation, ok, diags := expandAccessTokenRotation(m)
+	if diags.HasError() {
+		return rotation, accessTokenRotationNone, diags
+	}
+
+	switch {
+	case ok && rotation.due(createdAt, now):
+		return rotation, accessTokenRotationRotate, diags
+	case hasPrevious && m == nil:
+		return rotation, accessTokenRotationRevokePrevious, diags
+	case hasPrevious && ok && rotation.overlapEnded(createdAt, now):
+		return rotation, accessTokenRotationRevokePrevious, diags
+	}
+	return rotation, accessTokenRotationNone, diags
+}
+
+// accessTokenRotationComputedAttributes are the attributes that change when a
+// token is rotated or its previous token is revoked. ModifyPlan marks all of
+// them unknown so that the decision can be made again at apply time without
+// contradicting the plan.
+var accessTokenRotationComputedAttributes = []string{"token", "created_at", "previous_token"}
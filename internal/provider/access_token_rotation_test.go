@@ -0,0 +1,144 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package provider
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestPlanAccessTokenRotation(t *testing.T) {
+	created := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	createdAt := types.StringValue(created.Format(time.RFC3339))
+	rotation := &AccessTokenRotationModel{
+		RotateAfter: types.StringValue("720h"),
+		Overlap:     types.StringValue("24h"),
+	}
+
+	tests := []struct {
+		name        string
+		rotation    *AccessTokenRotationModel
+		createdAt   types.String
+		hasPrevious bool
+		now         time.Time
+		want        accessTokenRotationPlan
+	}{
+		{
+			name:      "not configured",
+			createdAt: createdAt,
+			now:       created.Add(1000 * time.Hour),
+			want:      accessTokenRotationNone,
+		},
+		{
+			name:      "not due",
+			rotation:  rotation,
+			createdAt: createdAt,
+			now:       created.Add(719 * time.Hour),
+			want:      accessTokenRotationNone,
+		},
+		{
+			name:      "due",
+			rotation:  rotation,
+			createdAt: createdAt,
+			now:       created.Add(720 * time.Hour),
+			want:      accessTokenRotationRotate,
+		},
+		{
+			name:        "due during overlap",
+			rotation:    &AccessTokenRotationModel{RotateAfter: types.StringValue("1h"), Overlap: types.StringValue("30m")},
+			createdAt:   createdAt,
+			hasPrevious: true,
+			now:         created.Add(2 * time.Hour),
+			want:        accessTokenRotationRotate,
+		},
+		{
+			name:        "overlap",
+			rotation:    rotation,
+			createdAt:   createdAt,
+			hasPrevious: true,
+			now:         created.Add(23 * time.Hour),
+			want:        accessTokenRotationNone,
+		},
+		{
+			name:        "overlap ended",
+			rotation:    rotation,
+			createdAt:   createdAt,
+			hasPrevious: true,
+			now:         created.Add(24 * time.Hour),
+			want:        accessTokenRotationRevokePrevious,
+		},
+		{
+			name:        "rotation removed",
+			createdAt:   createdAt,
+			hasPrevious: true,
+			now:         created.Add(time.Hour),
+			want:        accessTokenRotationRevokePrevious,
+		},
+		{
+			name:      "unknown creation time",
+			rotation:  rotation,
+			createdAt: types.StringNull(),
+			now:       created.Add(1000 * time.Hour),
+			want:      accessTokenRotationNone,
+		},
+		{
+			name:      "unknown schedule",
+			rotation:  &AccessTokenRotationModel{RotateAfter: types.StringUnknown(), Overlap: types.StringNull()},
+			createdAt: createdAt,
+			now:       created.Add(1000 * time.Hour),
+			want:      accessTokenRotationNone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, got, diags := planAccessTokenRotation(tt.rotation, tt.createdAt, tt.hasPrevious, tt.now)
+			if diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", diags)
+			}
+			if got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandAccessTokenRotationErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		rotation *AccessTokenRotationModel
+	}{
+		{
+			name:     "missing rotate_after",
+			rotation: &AccessTokenRotationModel{RotateAfter: types.StringNull(), Overlap: types.StringValue("1h")},
+		},
+		{
+			name:     "overlap not shorter",
+			rotation: &AccessTokenRotationModel{RotateAfter: types.StringValue("24h"), Overlap: types.StringValue("24h")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, diags := expandAccessTokenRotation(tt.rotation); !diags.HasError() {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -17,7 +17,10 @@
 package provider
 
 import (
+	"context"
+	"fmt"
 	"regexp"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
@@ -27,3 +30,31 @@ var accessTokenExpiresAtValidator validator.String = stringvalidator.RegexMatche
 	regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(?:\.\d{1,6})?Z$`),
 	"must be in ISO 8601 format, e.g., 2021-10-28T18:30:19.520861Z",
 )
+
+var timestampValidator validator.String = rfc3339TimestampValidator{}
+
+// rfc3339TimestampValidator checks that a string is an RFC 3339 timestamp,
+// such as "2024-01-01T00:00:00Z" or "2024-01-01T00:00:00+02:00".
+type rfc3339TimestampValidator struct{}
+
+func (v rfc3339TimestampValidator) Description(_ context.Context) string {
+	return "must be an RFC 3339 timestamp, e.g. 2024-01-01T00:00:00Z"
+}
+
+func (v rfc3339TimestampValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v rfc3339TimestampValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if _, err := time.Parse(time.RFC3339, req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Timestamp",
+			fmt.Sprintf("Attribute %s %s, got: %q", req.Path, v.Description(ctx), req.ConfigValue.ValueString()),
+		)
+	}
+}
@@ -0,0 +1,179 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/docker/terraform-provider-docker/internal/imageref"
+	"github.com/docker/terraform-provider-docker/internal/registryclient"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ datasource.DataSource                   = &PullRateLimitDataSource{}
+	_ datasource.DataSourceWithConfigure      = &PullRateLimitDataSource{}
+	_ datasource.DataSourceWithValidateConfig = &PullRateLimitDataSource{}
+)
+
+// defaultRateLimitImage is the image Docker Hub provides to check the pull
+// rate limit with.
+var defaultRateLimitImage = fmt.Sprintf("docker.io/%s:%s", registryclient.RateLimitRepository, registryclient.RateLimitTag)
+
+func NewPullRateLimitDataSource() datasource.DataSource {
+	return &PullRateLimitDataSource{}
+}
+
+type PullRateLimitDataSource struct {
+	client *registryclient.Client
+}
+
+type PullRateLimitDataSourceModel struct {
+	ID            types.String `tfsdk:"id"`
+	Image         types.String `tfsdk:"image"`
+	Limit         types.Int64  `tfsdk:"limit"`
+	Remaining     types.Int64  `tfsdk:"remaining"`
+	WindowSeconds types.Int64  `tfsdk:"window_seconds"`
+	Source        types.String `tfsdk:"source"`
+}
+
+func (d *PullRateLimitDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_hub_pull_rate_limit"
+}
+
+func (d *PullRateLimitDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: `Reads the Docker Hub pull rate limit status of the credentials the provider pulls images with.
+
+The status is read from the ` + "`ratelimit-*`" + ` headers of a HEAD request for the manifest of ` + "`ratelimitpreview/test`" + `, which does not count as a pull. Credentials are looked up like for ` + "`docker_registry_image_manifest`" + `: the Docker Hub credentials of the provider, whichever auth source they come from, when the provider manages ` + "`hub.docker.com`" + `, the Docker config file otherwise, and anonymous access if none are found.
+
+## Example Usage
+
+` + "```hcl" + `
+data "docker_hub_pull_rate_limit" "current" {}
+
+output "pulls_remaining" {
+  value = "${data.docker_hub_pull_rate_limit.current.remaining}/${data.docker_hub_pull_rate_limit.current.limit}"
+}
+` + "```" + `
+`,
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The fully qualified name of the image the status was read with",
+				Computed:            true,
+			},
+			"image": schema.StringAttribute{
+				MarkdownDescription: "Image to read the status with. Defaults to `" + defaultRateLimitImage + "`.",
+				Optional:            true,
+			},
+			"limit": schema.Int64Attribute{
+				MarkdownDescription: "Number of pulls allowed per window. Not set when pulls are not limited.",
+				Computed:            true,
+			},
+			"remaining": schema.Int64Attribute{
+				MarkdownDescription: "Number of pulls left in the current window. Not set when pulls are not limited.",
+				Computed:            true,
+			},
+			"window_seconds": schema.Int64Attribute{
+				MarkdownDescription: "Length of the window in seconds. Not set when pulls are not limited.",
+				Computed:            true,
+			},
+			"source": schema.StringAttribute{
+				MarkdownDescription: "What the limit applies to: the IP address for anonymous pulls, or the ID of the account for authenticated pulls",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *PullRateLimitDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *provider.ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = providerData.Registry
+}
+
+func (d *PullRateLimitDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var image types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("image"), &image)...)
+	if resp.Diagnostics.HasError() || image.IsNull() || image.IsUnknown() {
+		return
+	}
+
+	if _, err := imageref.Parse(image.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("image"), "Invalid Image Reference", err.Error())
+	}
+}
+
+func (d *PullRateLimitDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data PullRateLimitDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	image := defaultRateLimitImage
+	if !data.Image.IsNull() {
+		image = data.Image.ValueString()
+	}
+	ref, err := imageref.Parse(image)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("image"), "Invalid Image Reference", err.Error())
+		return
+	}
+	reference := ref.Tag
+	if ref.Digest != "" {
+		reference = ref.Digest
+	}
+
+	rateLimit, err := d.client.GetPullRateLimit(ctx, registryclient.RegistryHost(ref.Registry), ref.Path(), reference)
+	if err != nil {
+		resp.Diagnostics.AddError("Registry API error reading pull rate limit", fmt.Sprintf("Could not read the pull rate limit with %s, unexpected error: %s", ref, err))
+		return
+	}
+
+	data.ID = types.StringValue(ref.String())
+	data.Limit = types.Int64Null()
+	data.Remaining = types.Int64Null()
+	data.WindowSeconds = types.Int64Null()
+	if rateLimit.Limited {
+		data.Limit = types.Int64Value(rateLimit.Limit)
+		data.Remaining = types.Int64Value(rateLimit.Remaining)
+		data.WindowSeconds = types.Int64Value(rateLimit.WindowSeconds)
+	}
+	data.Source = types.StringValue(rateLimit.Source)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
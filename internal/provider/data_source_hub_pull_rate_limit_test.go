@@ -0,0 +1,67 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package provider
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/docker/terraform-provider-docker/internal/envvar"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccPullRateLimitDataSource(t *testing.T) {
+	image := envvar.GetWithDefault(envvar.AccTestRegistry) + "/ratelimitpreview/test:latest"
+	checks := []resource.TestCheckFunc{
+		resource.TestCheckResourceAttr("data.docker_hub_pull_rate_limit.test", "id", image),
+	}
+	if os.Getenv(envvar.AccTestFakeHub) != "" {
+		// Docker Hub does not report a limit for accounts without pull
+		// limits, the fake registry always does.
+		checks = append(checks,
+			resource.TestCheckResourceAttr("data.docker_hub_pull_rate_limit.test", "limit", "200"),
+			resource.TestCheckResourceAttr("data.docker_hub_pull_rate_limit.test", "remaining", "200"),
+			resource.TestCheckResourceAttr("data.docker_hub_pull_rate_limit.test", "window_seconds", "21600"),
+			resource.TestCheckResourceAttr("data.docker_hub_pull_rate_limit.test", "source", os.Getenv("DOCKER_USERNAME")),
+		)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPullRateLimitDataSourceConfig(image),
+				Check:  resource.ComposeAggregateTestCheckFunc(checks...),
+			},
+			{
+				Config:      testAccPullRateLimitDataSourceConfig("RateLimitPreview/Test"),
+				ExpectError: regexp.MustCompile("Invalid Image Reference"),
+			},
+		},
+	})
+}
+
+func testAccPullRateLimitDataSourceConfig(image string) string {
+	return fmt.Sprintf(`
+data "docker_hub_pull_rate_limit" "test" {
+  image = %q
+}
+`, image)
+}
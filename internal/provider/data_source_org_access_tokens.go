@@ -0,0 +1,312 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/docker/terraform-provider-docker/internal/hubclient"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ datasource.DataSource              = &OrgAccessTokensDataSource{}
+	_ datasource.DataSourceWithConfigure = &OrgAccessTokensDataSource{}
+)
+
+func NewOrgAccessTokensDataSource() datasource.DataSource {
+	return &OrgAccessTokensDataSource{}
+}
+
+type OrgAccessTokensDataSource struct {
+	client *hubclient.Client
+}
+
+type OrgAccessTokensDataSourceModel struct {
+	OrgName      types.String          `tfsdk:"org_name"`
+	ActiveOnly   types.Bool            `tfsdk:"active_only"`
+	Expired      types.Bool            `tfsdk:"expired"`
+	UnusedSince  types.String          `tfsdk:"unused_since"`
+	AccessTokens []OrgAccessTokenModel `tfsdk:"access_tokens"`
+}
+
+type OrgAccessTokenModel struct {
+	ID          types.String                       `tfsdk:"id"`
+	Label       types.String                       `tfsdk:"label"`
+	Description types.String                       `tfsdk:"description"`
+	CreatedBy   types.String                       `tfsdk:"created_by"`
+	IsActive    types.Bool                         `tfsdk:"is_active"`
+	CreatedAt   types.String                       `tfsdk:"created_at"`
+	ExpiresAt   types.String                       `tfsdk:"expires_at"`
+	LastUsedAt  types.String                       `tfsdk:"last_used_at"`
+	Resources   []OrgAccessTokenResourceEntryModel `tfsdk:"resources"`
+}
+
+// orgAccessTokenFilter selects organization access tokens. A nil field does
+// not filter.
+type orgAccessTokenFilter struct {
+	ActiveOnly  bool
+	Expired     *bool
+	UnusedSince *time.Time
+}
+
+func (f orgAccessTokenFilter) matches(at hubclient.OrgAccessToken, now time.Time) bool {
+	if f.ActiveOnly && !at.IsActive {
+		return false
+	}
+	if f.Expired != nil {
+		expiresAt, ok := parseTimestamp(at.ExpiresAt)
+		expired := ok && !now.Before(expiresAt)
+		if expired != *f.Expired {
+			return false
+		}
+	}
+	if f.UnusedSince != nil {
+		if lastUsedAt, ok := parseTimestamp(at.LastUsedAt); ok && !lastUsedAt.Before(*f.UnusedSince) {
+			return false
+		}
+	}
+	return true
+}
+
+// sortOrgAccessTokensNewestFirst sorts tokens by creation time, newest
+// first. Tokens without a creation time go last.
+func sortOrgAccessTokensNewestFirst(accessTokens []hubclient.OrgAccessToken) {
+	sort.SliceStable(accessTokens, func(i, j int) bool {
+		createdI, okI := parseTimestamp(accessTokens[i].CreatedAt)
+		createdJ, okJ := parseTimestamp(accessTokens[j].CreatedAt)
+		if okI != okJ {
+			return okI
+		}
+		return createdI.After(createdJ)
+	})
+}
+
+func (d *OrgAccessTokensDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_org_access_tokens"
+}
+
+func (d *OrgAccessTokensDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: `Lists the access tokens of an organization, newest first.
+
+-> **Note**: This data source is only available when authenticated with a username and password as an owner of the org.
+
+-> **Note**: Access tokens are read using the provider's ` + "`max_page_results`" + ` limit. Reading an organization with more tokens than that fails rather than filtering a partial list, set ` + "`max_page_results`" + ` to 0 to read every page.
+
+## Example Usage
+
+` + "```hcl" + `
+data "docker_org_access_tokens" "stale" {
+  org_name     = "my-organization"
+  active_only  = true
+  unused_since = timeadd(plantimestamp(), "-2160h")
+}
+
+output "stale_token_labels" {
+  value = [for token in data.docker_org_access_tokens.stale.access_tokens : token.label]
+}
+` + "```" + `
+`,
+		Attributes: map[string]schema.Attribute{
+			"org_name": schema.StringAttribute{
+				MarkdownDescription: "The organization namespace",
+				Required:            true,
+			},
+			"active_only": schema.BoolAttribute{
+				MarkdownDescription: "Only list active tokens",
+				Optional:            true,
+			},
+			"expired": schema.BoolAttribute{
+				MarkdownDescription: "If true, only list tokens that have expired. If false, only list tokens that have not expired, including tokens without an expiration date.",
+				Optional:            true,
+			},
+			"unused_since": schema.StringAttribute{
+				MarkdownDescription: "Only list tokens that have not been used since this time, including tokens that were never used, e.g. `2024-01-01T00:00:00Z`",
+				Optional:            true,
+				Validators: []validator.String{
+					timestampValidator,
+				},
+			},
+			"access_tokens": schema.ListNestedAttribute{
+				MarkdownDescription: "The access tokens matching the filters",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "The ID of the access token",
+							Computed:            true,
+						},
+						"label": schema.StringAttribute{
+							MarkdownDescription: "Label of the access token",
+							Computed:            true,
+						},
+						"description": schema.StringAttribute{
+							MarkdownDescription: "Description of the access token",
+							Computed:            true,
+						},
+						"created_by": schema.StringAttribute{
+							MarkdownDescription: "Username of the user who created the token",
+							Computed:            true,
+						},
+						"is_active": schema.BoolAttribute{
+							MarkdownDescription: "Whether the token is active",
+							Computed:            true,
+						},
+						"created_at": schema.StringAttribute{
+							MarkdownDescription: "Time the token was created",
+							Computed:            true,
+						},
+						"expires_at": schema.StringAttribute{
+							MarkdownDescription: "Time the token expires, if it expires",
+							Computed:            true,
+						},
+						"last_used_at": schema.StringAttribute{
+							MarkdownDescription: "Time the token was last used, if it was used",
+							Computed:            true,
+						},
+						"resources": schema.ListNestedAttribute{
+							MarkdownDescription: "Resources the token has access to",
+							Computed:            true,
+							NestedObject: schema.NestedAttributeObject{
+								Attributes: map[string]schema.Attribute{
+									"type": schema.StringAttribute{
+										MarkdownDescription: "The type of resource",
+										Computed:            true,
+									},
+									"path": schema.StringAttribute{
+										MarkdownDescription: "The path of the resource",
+										Computed:            true,
+									},
+									"scopes": schema.ListAttribute{
+										MarkdownDescription: "The scopes the token has for the resource",
+										Computed:            true,
+										ElementType:         types.StringType,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *OrgAccessTokensDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *provider.ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = providerData.Hub
+}
+
+func (d *OrgAccessTokensDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data OrgAccessTokensDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	filter := orgAccessTokenFilter{ActiveOnly: data.ActiveOnly.ValueBool()}
+	if !data.Expired.IsNull() {
+		expired := data.Expired.ValueBool()
+		filter.Expired = &expired
+	}
+	if !data.UnusedSince.IsNull() {
+		unusedSince, err := time.Parse(time.RFC3339, data.UnusedSince.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("unused_since"),
+				"Invalid Timestamp",
+				fmt.Sprintf("Unable to parse unused_since: %v", err),
+			)
+			return
+		}
+		filter.UnusedSince = &unusedSince
+	}
+
+	orgName := data.OrgName.ValueString()
+	accessTokens, err := d.client.ListOrgAccessTokens(ctx, orgName)
+	if err != nil {
+		resp.Diagnostics.AddError("Docker Hub API error reading org access tokens", listingError(err))
+		return
+	}
+	sortOrgAccessTokensNewestFirst(accessTokens)
+
+	now := time.Now()
+	data.AccessTokens = []OrgAccessTokenModel{}
+	for _, at := range accessTokens {
+		if !filter.matches(at, now) {
+			continue
+		}
+
+		// The list endpoint may leave out the resources of each token.
+		if at.Resources == nil {
+			at, err = d.client.GetOrgAccessToken(ctx, orgName, at.ID)
+			if err != nil {
+				resp.Diagnostics.AddError("Docker Hub API error reading org access token", fmt.Sprintf("%v", err))
+				return
+			}
+		}
+
+		resources, diags := flattenOrgAccessTokenResources(ctx, at.Resources)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		data.AccessTokens = append(data.AccessTokens, OrgAccessTokenModel{
+			ID:          types.StringValue(at.ID),
+			Label:       types.StringValue(at.Label),
+			Description: types.StringValue(at.Description),
+			CreatedBy:   types.StringValue(at.CreatedBy),
+			IsActive:    types.BoolValue(at.IsActive),
+			CreatedAt:   types.StringValue(at.CreatedAt),
+			ExpiresAt:   stringNullIfEmpty(at.ExpiresAt),
+			LastUsedAt:  stringNullIfEmpty(at.LastUsedAt),
+			Resources:   resources,
+		})
+	}
+
+	diags := resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
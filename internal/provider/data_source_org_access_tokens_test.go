@@ -0,0 +1,153 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package provider
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/docker/terraform-provider-docker/internal/envvar"
+	"github.com/docker/terraform-provider-docker/internal/hubclient"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccOrgAccessTokensDataSource(t *testing.T) {
+	orgName := envvar.GetWithDefault(envvar.AccTestOrganization)
+	label := "test-" + randString(10)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccOrgAccessTokensDataSourceConfig(orgName, label),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckOutput("label", label),
+					resource.TestCheckOutput("is_active", "true"),
+					resource.TestCheckOutput("path", orgName+"/*"),
+					resource.TestCheckOutput("expired_count", "0"),
+				),
+			},
+		},
+	})
+}
+
+func testAccOrgAccessTokensDataSourceConfig(orgName, label string) string {
+	return fmt.Sprintf(`
+resource "docker_org_access_token" "test" {
+  org_name = "%[1]s"
+  label    = "%[2]s"
+  resources = [
+    {
+      type   = "%[3]s"
+      path   = "%[1]s/*"
+      scopes = ["scope-image-pull"]
+    }
+  ]
+}
+
+data "docker_org_access_tokens" "unused" {
+  org_name     = "%[1]s"
+  active_only  = true
+  unused_since = "2099-01-01T00:00:00Z"
+
+  depends_on = [docker_org_access_token.test]
+}
+
+data "docker_org_access_tokens" "expired" {
+  org_name = "%[1]s"
+  expired  = true
+
+  depends_on = [docker_org_access_token.test]
+}
+
+locals {
+  token = [for token in data.docker_org_access_tokens.unused.access_tokens : token if token.id == docker_org_access_token.test.id][0]
+}
+
+output "label" {
+  value = local.token.label
+}
+
+output "is_active" {
+  value = local.token.is_active
+}
+
+output "path" {
+  value = local.token.resources[0].path
+}
+
+output "expired_count" {
+  value = length([for token in data.docker_org_access_tokens.expired.access_tokens : token if token.id == docker_org_access_token.test.id])
+}
+`, orgName, label, hubclient.OrgAccessTokenTypeRepo)
+}
+
+func TestOrgAccessTokenFilter(t *testing.T) {
+	now := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+	yes, no := true, false
+	since := now.Add(-30 * 24 * time.Hour)
+	token := func(isActive bool, expiresAt, lastUsedAt string) hubclient.OrgAccessToken {
+		return hubclient.OrgAccessToken{IsActive: isActive, ExpiresAt: expiresAt, LastUsedAt: lastUsedAt}
+	}
+
+	tests := []struct {
+		name   string
+		filter orgAccessTokenFilter
+		token  hubclient.OrgAccessToken
+		want   bool
+	}{
+		{"no filter", orgAccessTokenFilter{}, token(false, "2024-01-01T00:00:00Z", ""), true},
+		{"active", orgAccessTokenFilter{ActiveOnly: true}, token(true, "", ""), true},
+		{"inactive", orgAccessTokenFilter{ActiveOnly: true}, token(false, "", ""), false},
+		{"expired", orgAccessTokenFilter{Expired: &yes}, token(true, "2024-06-29T00:00:00Z", ""), true},
+		{"not yet expired", orgAccessTokenFilter{Expired: &yes}, token(true, "2024-07-01T00:00:00Z", ""), false},
+		{"never expires", orgAccessTokenFilter{Expired: &yes}, token(true, "", ""), false},
+		{"unexpired", orgAccessTokenFilter{Expired: &no}, token(true, "", ""), true},
+		{"unexpired but expired", orgAccessTokenFilter{Expired: &no}, token(true, "2024-06-29T00:00:00Z", ""), false},
+		{"never used", orgAccessTokenFilter{UnusedSince: &since}, token(true, "", ""), true},
+		{"used before", orgAccessTokenFilter{UnusedSince: &since}, token(true, "", "2024-05-01T00:00:00.123Z"), true},
+		{"used since", orgAccessTokenFilter{UnusedSince: &since}, token(true, "", "2024-06-15T00:00:00Z"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.matches(tt.token, now); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortOrgAccessTokensNewestFirst(t *testing.T) {
+	accessTokens := []hubclient.OrgAccessToken{
+		{ID: "unknown", CreatedAt: ""},
+		{ID: "old", CreatedAt: "2024-01-01T00:00:00Z"},
+		{ID: "new", CreatedAt: "2024-06-01T00:00:00.5Z"},
+		{ID: "middle", CreatedAt: "2024-03-01T00:00:00Z"},
+	}
+	sortOrgAccessTokensNewestFirst(accessTokens)
+
+	var got []string
+	for _, at := range accessTokens {
+		got = append(got, at.ID)
+	}
+	if want := []string{"new", "middle", "old", "unknown"}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
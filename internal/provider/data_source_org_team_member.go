@@ -174,18 +174,18 @@ func (d *OrgTeamMemberDataSource) Configure(ctx context.Context, req datasource.
 		return
 	}
 
-	client, ok := req.ProviderData.(*hubclient.Client)
+	providerData, ok := req.ProviderData.(*ProviderData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *hubclient.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *provider.ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	d.client = client
+	d.client = providerData.Hub
 }
 
 func (d *OrgTeamMemberDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
@@ -199,7 +199,7 @@ func (d *OrgTeamMemberDataSource) Read(ctx context.Context, req datasource.ReadR
 
 	members, err := d.client.ListOrgTeamMembers(ctx, data.OrgName.ValueString(), data.TeamName.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError("Docker Hub API error reading org team members", fmt.Sprintf("%v", err))
+		resp.Diagnostics.AddError("Docker Hub API error reading org team members", listingError(err))
 		return
 	}
 
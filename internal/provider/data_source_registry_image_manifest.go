@@ -0,0 +1,336 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/docker/terraform-provider-docker/internal/imageref"
+	"github.com/docker/terraform-provider-docker/internal/registryclient"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ datasource.DataSource                   = &RegistryImageManifestDataSource{}
+	_ datasource.DataSourceWithConfigure      = &RegistryImageManifestDataSource{}
+	_ datasource.DataSourceWithValidateConfig = &RegistryImageManifestDataSource{}
+)
+
+// platformRegexp matches a platform in the os/architecture[/variant] form.
+var platformRegexp = regexp.MustCompile(`^[a-z0-9]+/[a-z0-9_]+(/[a-z0-9]+)?$`)
+
+func NewRegistryImageManifestDataSource() datasource.DataSource {
+	return &RegistryImageManifestDataSource{}
+}
+
+type RegistryImageManifestDataSource struct {
+	client *registryclient.Client
+}
+
+type RegistryImageManifestDataSourceModel struct {
+	ID             types.String                 `tfsdk:"id"`
+	Image          types.String                 `tfsdk:"image"`
+	Platform       types.String                 `tfsdk:"platform"`
+	MediaType      types.String                 `tfsdk:"media_type"`
+	Digest         types.String                 `tfsdk:"digest"`
+	Manifests      []RegistryManifestEntryModel `tfsdk:"manifests"`
+	PlatformDigest types.String                 `tfsdk:"platform_digest"`
+	ConfigDigest   types.String                 `tfsdk:"config_digest"`
+	Labels         map[string]types.String      `tfsdk:"labels"`
+}
+
+type RegistryManifestEntryModel struct {
+	Digest       types.String `tfsdk:"digest"`
+	MediaType    types.String `tfsdk:"media_type"`
+	Size         types.Int64  `tfsdk:"size"`
+	Platform     types.String `tfsdk:"platform"`
+	Architecture types.String `tfsdk:"architecture"`
+	OS           types.String `tfsdk:"os"`
+	OSVersion    types.String `tfsdk:"os_version"`
+	Variant      types.String `tfsdk:"variant"`
+}
+
+func (d *RegistryImageManifestDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_registry_image_manifest"
+}
+
+func (d *RegistryImageManifestDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: `Reads the manifest of an image from any registry speaking the OCI distribution API, such as Docker Hub or GHCR.
+
+Credentials are read from the Docker config file (` + "`docker login`" + `). For Docker Hub, the provider authenticates as the same user as for the Docker Hub API when it manages ` + "`hub.docker.com`" + `, whichever auth source that user came from. Images are pulled anonymously if no credentials are found.
+
+-> **Note**: Every read fetches the manifest, which Docker Hub counts as a pull towards its pull rate limit. Image layers are never downloaded.
+
+## Example Usage
+
+` + "```hcl" + `
+data "docker_registry_image_manifest" "alpine" {
+  image    = "alpine:3.20"
+  platform = "linux/arm64/v8"
+}
+
+output "alpine_arm64" {
+  value = "alpine@${data.docker_registry_image_manifest.alpine.platform_digest}"
+}
+
+output "platforms" {
+  value = data.docker_registry_image_manifest.alpine.manifests[*].platform
+}
+` + "```" + `
+`,
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The fully qualified image name pinned to the digest of the manifest",
+				Computed:            true,
+			},
+			"image": schema.StringAttribute{
+				MarkdownDescription: "Image reference, such as `alpine`, `alpine:3.20`, `ghcr.io/org/app:v1` or `alpine@sha256:...`. The tag defaults to `latest`.",
+				Required:            true,
+			},
+			"platform": schema.StringAttribute{
+				MarkdownDescription: "Platform to resolve when the image is an index, in the `os/architecture[/variant]` form such as `linux/amd64` or `linux/arm64/v8`. Without it, `config_digest` and `labels` are only set for single-platform images.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(platformRegexp, "must be of the form os/architecture or os/architecture/variant"),
+				},
+			},
+			"media_type": schema.StringAttribute{
+				MarkdownDescription: "Media type of the manifest, an image index or manifest list for multi-platform images",
+				Computed:            true,
+			},
+			"digest": schema.StringAttribute{
+				MarkdownDescription: "Digest of the manifest",
+				Computed:            true,
+			},
+			"manifests": schema.ListNestedAttribute{
+				MarkdownDescription: "Entries of the index, one per platform. Empty for single-platform images.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"digest": schema.StringAttribute{
+							MarkdownDescription: "Digest of the platform manifest",
+							Computed:            true,
+						},
+						"media_type": schema.StringAttribute{
+							MarkdownDescription: "Media type of the platform manifest",
+							Computed:            true,
+						},
+						"size": schema.Int64Attribute{
+							MarkdownDescription: "Size of the platform manifest in bytes",
+							Computed:            true,
+						},
+						"platform": schema.StringAttribute{
+							MarkdownDescription: "Platform in the `os/architecture[/variant]` form, empty for entries without a platform such as attestations",
+							Computed:            true,
+						},
+						"architecture": schema.StringAttribute{
+							Computed: true,
+						},
+						"os": schema.StringAttribute{
+							Computed: true,
+						},
+						"os_version": schema.StringAttribute{
+							Computed: true,
+						},
+						"variant": schema.StringAttribute{
+							Computed: true,
+						},
+					},
+				},
+			},
+			"platform_digest": schema.StringAttribute{
+				MarkdownDescription: "Digest of the image manifest of `platform`, or of the image itself for single-platform images",
+				Computed:            true,
+			},
+			"config_digest": schema.StringAttribute{
+				MarkdownDescription: "Digest of the image config",
+				Computed:            true,
+			},
+			"labels": schema.MapAttribute{
+				MarkdownDescription: "Labels of the image config",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+		},
+	}
+}
+
+func (d *RegistryImageManifestDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *provider.ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = providerData.Registry
+}
+
+func (d *RegistryImageManifestDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var image types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("image"), &image)...)
+	if resp.Diagnostics.HasError() || image.IsNull() || image.IsUnknown() {
+		return
+	}
+
+	if _, err := imageref.Parse(image.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("image"), "Invalid Image Reference", err.Error())
+	}
+}
+
+func (d *RegistryImageManifestDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data RegistryImageManifestDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ref, err := imageref.Parse(data.Image.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("image"), "Invalid Image Reference", err.Error())
+		return
+	}
+	host := registryclient.RegistryHost(ref.Registry)
+	reference := ref.Tag
+	if ref.Digest != "" {
+		reference = ref.Digest
+	}
+
+	manifest, err := d.client.GetManifest(ctx, host, ref.Path(), reference)
+	if err != nil {
+		resp.Diagnostics.AddError("Registry API error reading image manifest", fmt.Sprintf("Could not read the manifest of %s, unexpected error: %s", ref, err))
+		return
+	}
+
+	data.ID = types.StringValue(ref.Name() + "@" + manifest.Descriptor.Digest)
+	data.MediaType = types.StringValue(manifest.Descriptor.MediaType)
+	data.Digest = types.StringValue(manifest.Descriptor.Digest)
+	data.Manifests = []RegistryManifestEntryModel{}
+	for _, entry := range manifest.Manifests {
+		model := RegistryManifestEntryModel{
+			Digest:       types.StringValue(entry.Digest),
+			MediaType:    types.StringValue(entry.MediaType),
+			Size:         types.Int64Value(entry.Size),
+			Platform:     types.StringValue(""),
+			Architecture: types.StringValue(""),
+			OS:           types.StringValue(""),
+			OSVersion:    types.StringValue(""),
+			Variant:      types.StringValue(""),
+		}
+		if p := entry.Platform; p != nil {
+			model.Platform = types.StringValue(p.String())
+			model.Architecture = types.StringValue(p.Architecture)
+			model.OS = types.StringValue(p.OS)
+			model.OSVersion = types.StringValue(p.OSVersion)
+			model.Variant = types.StringValue(p.Variant)
+		}
+		data.Manifests = append(data.Manifests, model)
+	}
+	data.PlatformDigest = types.StringNull()
+	data.ConfigDigest = types.StringNull()
+	data.Labels = nil
+
+	image := manifest
+	if manifest.IsIndex() {
+		if data.Platform.IsNull() {
+			resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+			return
+		}
+		entry, ok := findPlatform(manifest.Manifests, data.Platform.ValueString())
+		if !ok {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("platform"),
+				"Platform Not Found",
+				fmt.Sprintf("%s has no image for platform %s, available platforms: %s", ref, data.Platform.ValueString(), strings.Join(indexPlatforms(manifest.Manifests), ", ")),
+			)
+			return
+		}
+		image, err = d.client.GetManifest(ctx, host, ref.Path(), entry.Digest)
+		if err != nil {
+			resp.Diagnostics.AddError("Registry API error reading image manifest", fmt.Sprintf("Could not read the %s manifest of %s, unexpected error: %s", data.Platform.ValueString(), ref, err))
+			return
+		}
+	}
+
+	data.PlatformDigest = types.StringValue(image.Descriptor.Digest)
+	if image.Config == nil {
+		resp.Diagnostics.AddError("Unsupported Manifest", fmt.Sprintf("The manifest %s of %s has no config, media type %s is not supported.", image.Descriptor.Digest, ref, image.Descriptor.MediaType))
+		return
+	}
+	data.ConfigDigest = types.StringValue(image.Config.Digest)
+
+	config, err := d.client.GetImageConfig(ctx, host, ref.Path(), image.Config.Digest)
+	if err != nil {
+		resp.Diagnostics.AddError("Registry API error reading image config", fmt.Sprintf("Could not read the config of %s, unexpected error: %s", ref, err))
+		return
+	}
+	data.Labels = map[string]types.String{}
+	for k, v := range config.Config.Labels {
+		data.Labels[k] = types.StringValue(v)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// findPlatform returns the index entry for a platform in the
+// os/architecture[/variant] form. Without a variant, any variant matches.
+func findPlatform(entries []registryclient.Descriptor, platform string) (registryclient.Descriptor, bool) {
+	parts := strings.Split(platform, "/")
+	for _, entry := range entries {
+		p := entry.Platform
+		if p == nil || p.OS != parts[0] || p.Architecture != parts[1] {
+			continue
+		}
+		if len(parts) == 3 && p.Variant != parts[2] {
+			continue
+		}
+		return entry, true
+	}
+	return registryclient.Descriptor{}, false
+}
+
+// indexPlatforms returns the platforms of an index, sorted.
+func indexPlatforms(entries []registryclient.Descriptor) []string {
+	var platforms []string
+	for _, entry := range entries {
+		if entry.Platform != nil && entry.Platform.OS != "unknown" {
+			platforms = append(platforms, entry.Platform.String())
+		}
+	}
+	sort.Strings(platforms)
+	return platforms
+}
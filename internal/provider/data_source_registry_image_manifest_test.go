@@ -0,0 +1,79 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/docker/terraform-provider-docker/internal/envvar"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccRegistryImageManifestDataSource(t *testing.T) {
+	image := envvar.GetWithDefault(envvar.AccTestRegistry) + "/library/hello-world:latest"
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRegistryImageManifestDataSourceConfig(image, ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.docker_registry_image_manifest.test", "media_type", "application/vnd.oci.image.index.v1+json"),
+					resource.TestMatchResourceAttr("data.docker_registry_image_manifest.test", "digest", regexp.MustCompile(`^sha256:[0-9a-f]{64}$`)),
+					resource.TestMatchResourceAttr("data.docker_registry_image_manifest.test", "id", regexp.MustCompile(`/library/hello-world@sha256:`)),
+					resource.TestCheckTypeSetElemNestedAttrs("data.docker_registry_image_manifest.test", "manifests.*", map[string]string{
+						"platform":     "linux/amd64",
+						"os":           "linux",
+						"architecture": "amd64",
+					}),
+					resource.TestCheckNoResourceAttr("data.docker_registry_image_manifest.test", "config_digest"),
+				),
+			},
+			{
+				Config: testAccRegistryImageManifestDataSourceConfig(image, "linux/arm64/v8"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestMatchResourceAttr("data.docker_registry_image_manifest.test", "platform_digest", regexp.MustCompile(`^sha256:`)),
+					resource.TestMatchResourceAttr("data.docker_registry_image_manifest.test", "config_digest", regexp.MustCompile(`^sha256:`)),
+					resource.TestCheckResourceAttrSet("data.docker_registry_image_manifest.test", "labels.%"),
+				),
+			},
+			{
+				Config:      testAccRegistryImageManifestDataSourceConfig(image, "plan9/amd64"),
+				ExpectError: regexp.MustCompile("Platform Not Found"),
+			},
+			{
+				Config:      testAccRegistryImageManifestDataSourceConfig("Hello-World", ""),
+				ExpectError: regexp.MustCompile("Invalid Image Reference"),
+			},
+		},
+	})
+}
+
+func testAccRegistryImageManifestDataSourceConfig(image, platform string) string {
+	platformAttr := ""
+	if platform != "" {
+		platformAttr = fmt.Sprintf("platform = %q", platform)
+	}
+	return fmt.Sprintf(`
+data "docker_registry_image_manifest" "test" {
+  image = %[1]q
+  %[2]s
+}
+`, image, platformAttr)
+}
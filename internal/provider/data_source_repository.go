@@ -124,7 +124,7 @@ func (d *RepositoryDataSource) Configure(ctx context.Context, req datasource.Con
 		return
 	}
 
-	client, ok := req.ProviderData.(*hubclient.Client)
+	providerData, ok := req.ProviderData.(*ProviderData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
@@ -135,7 +135,7 @@ func (d *RepositoryDataSource) Configure(ctx context.Context, req datasource.Con
 		return
 	}
 
-	d.client = client
+	d.client = providerData.Hub
 }
 
 func (d *RepositoryDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
@@ -0,0 +1,322 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/docker/terraform-provider-docker/internal/hubclient"
+	"github.com/docker/terraform-provider-docker/internal/repositoryutils"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ datasource.DataSource                   = &RepositoryLatestTagDataSource{}
+	_ datasource.DataSourceWithConfigure      = &RepositoryLatestTagDataSource{}
+	_ datasource.DataSourceWithValidateConfig = &RepositoryLatestTagDataSource{}
+)
+
+// Values of the order_by attribute.
+const (
+	latestTagOrderSemver     = "semver"
+	latestTagOrderLastPushed = "last_pushed"
+)
+
+func NewRepositoryLatestTagDataSource() datasource.DataSource {
+	return &RepositoryLatestTagDataSource{}
+}
+
+type RepositoryLatestTagDataSource struct {
+	client *hubclient.Client
+}
+
+type RepositoryLatestTagDataSourceModel struct {
+	ID                types.String    `tfsdk:"id"`
+	Repository        types.String    `tfsdk:"repository"`
+	NameRegex         types.String    `tfsdk:"name_regex"`
+	VersionConstraint types.String    `tfsdk:"version_constraint"`
+	OrderBy           types.String    `tfsdk:"order_by"`
+	Name              types.String    `tfsdk:"name"`
+	Digest            types.String    `tfsdk:"digest"`
+	TagLastPushed     types.String    `tfsdk:"tag_last_pushed"`
+	Images            []TagImageModel `tfsdk:"images"`
+}
+
+func (d *RepositoryLatestTagDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_hub_repository_latest_tag"
+}
+
+func (d *RepositoryLatestTagDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: `Finds the newest tag of a Docker Hub repository matching a regular expression or a version constraint, to pin images by digest.
+
+By default tags are ordered as semantic versions, such as ` + "`v1.4.2`" + ` or ` + "`1.27`" + `, and tags that are not versions are ignored. Suffixes such as ` + "`-rc.1`" + ` or ` + "`-alpine`" + ` make a tag a prerelease, which ` + "`version_constraint`" + ` only matches if it names a prerelease of the same version.
+
+## Example Usage
+
+` + "```hcl" + `
+data "docker_hub_repository_latest_tag" "app" {
+  repository         = "my-organization/my-app"
+  version_constraint = "~> 1.4"
+}
+
+# Pin the image by digest
+output "image" {
+  value = "my-organization/my-app@${data.docker_hub_repository_latest_tag.app.digest}"
+}
+
+# Newest tag by push date, such as nightly builds
+data "docker_hub_repository_latest_tag" "nightly" {
+  repository = "my-organization/my-app"
+  name_regex = "^nightly-"
+  order_by   = "last_pushed"
+}
+` + "```" + `
+`,
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The namespace/name of the repository",
+				Computed:            true,
+			},
+			"repository": schema.StringAttribute{
+				MarkdownDescription: "Repository ID in format namespace/name",
+				Required:            true,
+			},
+			"name_regex": schema.StringAttribute{
+				MarkdownDescription: "Only consider tags whose name matches this regular expression (RE2 syntax). The pattern is not anchored, use `^` and `$` to match the whole name.",
+				Optional:            true,
+			},
+			"version_constraint": schema.StringAttribute{
+				MarkdownDescription: "Only consider tags that are semantic versions matching this constraint, such as `~> 1.4` or `>= 1.2, < 2`. Conditions are separated by commas and use the operators `=`, `!=`, `>`, `>=`, `<`, `<=` and `~>`.",
+				Optional:            true,
+			},
+			"order_by": schema.StringAttribute{
+				MarkdownDescription: "How to find the newest tag: `semver` for the highest semantic version or `last_pushed` for the most recently pushed tag. Defaults to `semver`.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(latestTagOrderSemver, latestTagOrderLastPushed),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Name of the newest matching tag",
+				Computed:            true,
+			},
+			"digest": schema.StringAttribute{
+				MarkdownDescription: "Digest of the tag, the image index for multi-platform images",
+				Computed:            true,
+			},
+			"tag_last_pushed": schema.StringAttribute{
+				MarkdownDescription: "When the tag was last pushed",
+				Computed:            true,
+			},
+			"images": schema.ListNestedAttribute{
+				MarkdownDescription: "Per-platform images of the tag",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: tagImageAttributes(),
+				},
+			},
+		},
+	}
+}
+
+func (d *RepositoryLatestTagDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *provider.ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = providerData.Hub
+}
+
+func (d *RepositoryLatestTagDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var data RepositoryLatestTagDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	_, diags := newLatestTagMatcher(data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (d *RepositoryLatestTagDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data RepositoryLatestTagDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	matcher, diags := newLatestTagMatcher(data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id := data.Repository.ValueString()
+	namespace, name := repositoryutils.SplitID(id)
+	params := hubclient.ListRepositoryTagsParams{
+		Name:  matcher.filter.namePrefix(),
+		Match: matcher.matches,
+	}
+	if matcher.orderBy == latestTagOrderLastPushed {
+		// Docker Hub lists the most recently pushed tags first, the first
+		// match is the newest.
+		params.Ordering = hubclient.TagOrderingLastUpdated
+		params.Limit = 1
+	}
+
+	tags, err := d.client.ListRepositoryTags(ctx, namespace, name, params)
+	if err != nil {
+		resp.Diagnostics.AddError("Docker Hub API error reading repository tags", "Could not read repository tags, unexpected error: "+err.Error())
+		return
+	}
+	// The newest tag may be on a page that was not read.
+	if tags.Truncated {
+		resp.Diagnostics.AddError(
+			"Too Many Tags",
+			fmt.Sprintf("Repository %s has more tags than max_page_results allows to read, so the latest tag cannot be determined. Raise max_page_results in the provider configuration, or set it to 0 to read every page.", id),
+		)
+		return
+	}
+
+	tag, ok := matcher.newest(tags.Results)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"No Matching Tag",
+			fmt.Sprintf("Repository %s has no tag matching the given criteria.", id),
+		)
+		return
+	}
+
+	data.ID = types.StringValue(id)
+	data.Name = types.StringValue(tag.Name)
+	data.Digest = types.StringValue(tag.Digest)
+	data.TagLastPushed = types.StringValue(tag.TagLastPushed)
+	data.Images = newTagImageModels(tag.Images)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// latestTagMatcher selects the tags a latest tag data source considers and
+// picks the newest one.
+type latestTagMatcher struct {
+	filter     tagFilter
+	constraint version.Constraints
+	orderBy    string
+}
+
+// newLatestTagMatcher builds the matcher from the configuration. Unknown
+// values are ignored, so it can be used to validate the configuration.
+func newLatestTagMatcher(data RepositoryLatestTagDataSourceModel) (latestTagMatcher, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	matcher := latestTagMatcher{orderBy: latestTagOrderSemver}
+	if !data.OrderBy.IsNull() && !data.OrderBy.IsUnknown() {
+		matcher.orderBy = data.OrderBy.ValueString()
+	}
+
+	if !data.NameRegex.IsNull() && !data.NameRegex.IsUnknown() {
+		re, err := regexp.Compile(data.NameRegex.ValueString())
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("name_regex"),
+				"Invalid Name Regex",
+				fmt.Sprintf("name_regex is not a valid regular expression: %v", err),
+			)
+		}
+		matcher.filter.nameRegex = re
+	}
+
+	if !data.VersionConstraint.IsNull() && !data.VersionConstraint.IsUnknown() {
+		constraint, err := version.NewConstraint(data.VersionConstraint.ValueString())
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("version_constraint"),
+				"Invalid Version Constraint",
+				err.Error(),
+			)
+		}
+		matcher.constraint = constraint
+	}
+
+	return matcher, diags
+}
+
+func (m latestTagMatcher) matches(tag hubclient.Tag) bool {
+	if !m.filter.matches(tag) {
+		return false
+	}
+	if m.constraint == nil && m.orderBy != latestTagOrderSemver {
+		return true
+	}
+	v, err := version.NewVersion(tag.Name)
+	if err != nil {
+		return false
+	}
+	return m.constraint == nil || m.constraint.Check(v)
+}
+
+// newest returns the newest of the matching tags.
+func (m latestTagMatcher) newest(tags []hubclient.Tag) (hubclient.Tag, bool) {
+	var newest hubclient.Tag
+	var newestVersion *version.Version
+	found := false
+	for _, tag := range tags {
+		if !m.matches(tag) {
+			continue
+		}
+		if m.orderBy == latestTagOrderLastPushed {
+			if !found || tagLastPushed(tag).After(tagLastPushed(newest)) {
+				newest, found = tag, true
+			}
+			continue
+		}
+		v, err := version.NewVersion(tag.Name)
+		if err != nil {
+			continue
+		}
+		// Between equal versions such as "1.4" and "v1.4.0", prefer the
+		// most recently pushed.
+		if !found || v.Compare(newestVersion) > 0 ||
+			(v.Compare(newestVersion) == 0 && tagLastPushed(tag).After(tagLastPushed(newest))) {
+			newest, newestVersion, found = tag, v, true
+		}
+	}
+	return newest, found
+}
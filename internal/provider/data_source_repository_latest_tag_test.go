@@ -0,0 +1,143 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/docker/terraform-provider-docker/internal/hubclient"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccRepositoryLatestTagDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "docker_hub_repository_latest_tag" "test" {
+  repository = "library/hello-world"
+  name_regex = "^lat"
+  order_by   = "last_pushed"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.docker_hub_repository_latest_tag.test", "id", "library/hello-world"),
+					resource.TestCheckResourceAttr("data.docker_hub_repository_latest_tag.test", "name", "latest"),
+					resource.TestCheckResourceAttrSet("data.docker_hub_repository_latest_tag.test", "digest"),
+					resource.TestCheckResourceAttrSet("data.docker_hub_repository_latest_tag.test", "tag_last_pushed"),
+					resource.TestCheckResourceAttrSet("data.docker_hub_repository_latest_tag.test", "images.0.digest"),
+				),
+			},
+			{
+				Config: `
+data "docker_hub_repository_latest_tag" "test" {
+  repository         = "library/hello-world"
+  version_constraint = "~> 1.0"
+}
+`,
+				ExpectError: regexp.MustCompile("No Matching Tag"),
+			},
+			{
+				Config: `
+data "docker_hub_repository_latest_tag" "test" {
+  repository         = "library/hello-world"
+  version_constraint = "~> latest"
+}
+`,
+				ExpectError: regexp.MustCompile("Invalid Version Constraint"),
+			},
+		},
+	})
+}
+
+func TestLatestTagMatcherNewest(t *testing.T) {
+	tags := []hubclient.Tag{
+		{Name: "latest", TagLastPushed: "2024-06-05T00:00:00Z"},
+		{Name: "v1.4.2", TagLastPushed: "2024-03-01T00:00:00Z"},
+		{Name: "v1.10.0", TagLastPushed: "2024-02-01T00:00:00Z"},
+		{Name: "v1.9.3", TagLastPushed: "2024-05-01T00:00:00Z"},
+		{Name: "v2.0.0-rc.1", TagLastPushed: "2024-06-01T00:00:00Z"},
+		{Name: "v2.0.0-rc.2", TagLastPushed: "2024-06-02T00:00:00Z"},
+		{Name: "1.10", TagLastPushed: "2024-04-01T00:00:00Z"},
+	}
+	constraint := func(s string) version.Constraints {
+		c, err := version.NewConstraint(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	tests := []struct {
+		name    string
+		matcher latestTagMatcher
+		want    string
+	}{
+		{
+			name:    "highest version",
+			matcher: latestTagMatcher{orderBy: latestTagOrderSemver},
+			want:    "v2.0.0-rc.2",
+		},
+		{
+			name:    "version constraint",
+			matcher: latestTagMatcher{orderBy: latestTagOrderSemver, constraint: constraint("~> 1.4")},
+			want:    "1.10",
+		},
+		{
+			name:    "patch constraint",
+			matcher: latestTagMatcher{orderBy: latestTagOrderSemver, constraint: constraint("~> 1.4.0")},
+			want:    "v1.4.2",
+		},
+		{
+			name: "name regex",
+			matcher: latestTagMatcher{
+				orderBy: latestTagOrderSemver,
+				filter:  tagFilter{nameRegex: regexp.MustCompile(`^v\d+\.\d+\.\d+$`)},
+			},
+			want: "v1.10.0",
+		},
+		{
+			name:    "last pushed",
+			matcher: latestTagMatcher{orderBy: latestTagOrderLastPushed},
+			want:    "latest",
+		},
+		{
+			name:    "last pushed version",
+			matcher: latestTagMatcher{orderBy: latestTagOrderLastPushed, constraint: constraint("< 2")},
+			want:    "v1.9.3",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.matcher.newest(tags)
+			if !ok {
+				t.Fatal("found no tag")
+			}
+			if got.Name != tt.want {
+				t.Errorf("got %s, want %s", got.Name, tt.want)
+			}
+		})
+	}
+
+	if _, ok := (latestTagMatcher{orderBy: latestTagOrderSemver, constraint: constraint("~> 3.0")}).newest(tags); ok {
+		t.Error("found a tag, want none")
+	}
+}
@@ -19,18 +19,34 @@ package provider
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"sort"
+	"time"
 
 	"github.com/docker/terraform-provider-docker/internal/hubclient"
 	"github.com/docker/terraform-provider-docker/internal/repositoryutils"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 var (
-	_ datasource.DataSource              = &RepositoryTagsDataSource{}
-	_ datasource.DataSourceWithConfigure = &RepositoryTagsDataSource{}
+	_ datasource.DataSource                   = &RepositoryTagsDataSource{}
+	_ datasource.DataSourceWithConfigure      = &RepositoryTagsDataSource{}
+	_ datasource.DataSourceWithValidateConfig = &RepositoryTagsDataSource{}
+)
+
+// Values of the sort_by attribute.
+const (
+	tagSortLastPushed = "last_pushed"
+	tagSortLastPulled = "last_pulled"
+	tagSortName       = "name"
 )
 
 func NewRepositoryTagsDataSource() datasource.DataSource {
@@ -42,11 +58,19 @@ type RepositoryTagsDataSource struct {
 }
 
 type RepositoryTagsDataSourceModel struct {
-	ID         types.String `tfsdk:"id"`
-	Repository types.String `tfsdk:"repository"`
-	Namespace  types.String `tfsdk:"namespace"`
-	Name       types.String `tfsdk:"name"`
-	Tags       types.Map    `tfsdk:"tags"`
+	ID           types.String `tfsdk:"id"`
+	Repository   types.String `tfsdk:"repository"`
+	Namespace    types.String `tfsdk:"namespace"`
+	Name         types.String `tfsdk:"name"`
+	NameRegex    types.String `tfsdk:"name_regex"`
+	PushedAfter  types.String `tfsdk:"pushed_after"`
+	Architecture types.String `tfsdk:"architecture"`
+	OS           types.String `tfsdk:"os"`
+	Status       types.String `tfsdk:"status"`
+	SortBy       types.String `tfsdk:"sort_by"`
+	Limit        types.Int64  `tfsdk:"limit"`
+	TagNames     types.List   `tfsdk:"tag_names"`
+	Tags         types.Map    `tfsdk:"tags"`
 }
 
 type RepositoryTagModel struct {
@@ -132,6 +156,22 @@ output "single_tag" {
   value       = data.docker_hub_repository_tags.example.tags["latest"]
 }
 
+# The five most recently pushed release tags for linux/arm64
+data "docker_hub_repository_tags" "releases" {
+  namespace    = "my-organization"
+  name         = "my-repo"
+  name_regex   = "^v[0-9]+\\.[0-9]+\\.[0-9]+$"
+  architecture = "arm64"
+  os           = "linux"
+  status       = "active"
+  sort_by      = "last_pushed"
+  limit        = 5
+}
+
+output "latest_release" {
+  value = data.docker_hub_repository_tags.releases.tag_names[0]
+}
+
 # Alternative usage with repository ID
 data "docker_hub_repository" "main" {
   namespace = "example"
@@ -160,6 +200,48 @@ data "docker_hub_repository_tags" "main" {
 				MarkdownDescription: "Repository name",
 				Optional:            true,
 			},
+			"name_regex": schema.StringAttribute{
+				MarkdownDescription: "Only return tags whose name matches this regular expression (RE2 syntax). The pattern is not anchored, use `^` and `$` to match the whole name. A literal prefix of the pattern is sent to Docker Hub to narrow down the listing.",
+				Optional:            true,
+			},
+			"pushed_after": schema.StringAttribute{
+				MarkdownDescription: "Only return tags last pushed after this RFC 3339 timestamp, for example `2024-01-01T00:00:00Z`",
+				Optional:            true,
+			},
+			"architecture": schema.StringAttribute{
+				MarkdownDescription: "Only return tags with an image for this architecture, for example `amd64` or `arm64`",
+				Optional:            true,
+			},
+			"os": schema.StringAttribute{
+				MarkdownDescription: "Only return tags with an image for this operating system, for example `linux` or `windows`. Combined with `architecture`, both must match the same image.",
+				Optional:            true,
+			},
+			"status": schema.StringAttribute{
+				MarkdownDescription: "Only return tags with this status, either `active` or `inactive`",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("active", "inactive"),
+				},
+			},
+			"sort_by": schema.StringAttribute{
+				MarkdownDescription: "Order of `tag_names`: `last_pushed` or `last_pulled` (most recent first) or `name`. Defaults to the Docker Hub order, most recently updated first.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(tagSortLastPushed, tagSortLastPulled, tagSortName),
+				},
+			},
+			"limit": schema.Int64Attribute{
+				MarkdownDescription: "Maximum number of tags to return, after filtering and sorting. When `sort_by` is unset or `name`, paging stops as soon as enough tags were found.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"tag_names": schema.ListAttribute{
+				MarkdownDescription: "Names of the returned tags, in the order given by `sort_by`",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
 			"tags": schema.MapNestedAttribute{
 				MarkdownDescription: "Map of tag names to tag information",
 				Computed:            true,
@@ -216,41 +298,7 @@ data "docker_hub_repository_tags" "main" {
 						"images": schema.ListNestedAttribute{
 							Computed: true,
 							NestedObject: schema.NestedAttributeObject{
-								Attributes: map[string]schema.Attribute{
-									"architecture": schema.StringAttribute{
-										Computed: true,
-									},
-									"features": schema.StringAttribute{
-										Computed: true,
-									},
-									"variant": schema.StringAttribute{
-										Computed: true,
-									},
-									"digest": schema.StringAttribute{
-										Computed: true,
-									},
-									"os": schema.StringAttribute{
-										Computed: true,
-									},
-									"os_features": schema.StringAttribute{
-										Computed: true,
-									},
-									"os_version": schema.StringAttribute{
-										Computed: true,
-									},
-									"size": schema.Int64Attribute{
-										Computed: true,
-									},
-									"status": schema.StringAttribute{
-										Computed: true,
-									},
-									"last_pulled": schema.StringAttribute{
-										Computed: true,
-									},
-									"last_pushed": schema.StringAttribute{
-										Computed: true,
-									},
-								},
+								Attributes: tagImageAttributes(),
 							},
 						},
 					},
@@ -260,24 +308,96 @@ data "docker_hub_repository_tags" "main" {
 	}
 }
 
+// tagImageAttributes returns the attributes of the per-platform images of a
+// tag.
+func tagImageAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"architecture": schema.StringAttribute{
+			Computed: true,
+		},
+		"features": schema.StringAttribute{
+			Computed: true,
+		},
+		"variant": schema.StringAttribute{
+			Computed: true,
+		},
+		"digest": schema.StringAttribute{
+			Computed: true,
+		},
+		"os": schema.StringAttribute{
+			Computed: true,
+		},
+		"os_features": schema.StringAttribute{
+			Computed: true,
+		},
+		"os_version": schema.StringAttribute{
+			Computed: true,
+		},
+		"size": schema.Int64Attribute{
+			Computed: true,
+		},
+		"status": schema.StringAttribute{
+			Computed: true,
+		},
+		"last_pulled": schema.StringAttribute{
+			Computed: true,
+		},
+		"last_pushed": schema.StringAttribute{
+			Computed: true,
+		},
+	}
+}
+
+func newTagImageModels(images []hubclient.TagImage) []TagImageModel {
+	var models []TagImageModel
+	for _, img := range images {
+		models = append(models, TagImageModel{
+			Architecture: types.StringValue(img.Architecture),
+			Features:     types.StringValue(img.Features),
+			Variant:      types.StringValue(img.Variant),
+			Digest:       types.StringValue(img.Digest),
+			OS:           types.StringValue(img.OS),
+			OSFeatures:   types.StringValue(img.OSFeatures),
+			OSVersion:    types.StringValue(img.OSVersion),
+			Size:         types.Int64Value(img.Size),
+			Status:       types.StringValue(img.Status),
+			LastPulled:   types.StringValue(img.LastPulled),
+			LastPushed:   types.StringValue(img.LastPushed),
+		})
+	}
+	return models
+}
+
 func (d *RepositoryTagsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
 	// Prevent panic if the provider has not been configured.
 	if req.ProviderData == nil {
 		return
 	}
 
-	client, ok := req.ProviderData.(*hubclient.Client)
+	providerData, ok := req.ProviderData.(*ProviderData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *hubclient.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *provider.ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	d.client = client
+	d.client = providerData.Hub
+}
+
+func (d *RepositoryTagsDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var data RepositoryTagsDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	_, diags := newTagFilter(data)
+	resp.Diagnostics.Append(diags...)
 }
 
 func (d *RepositoryTagsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
@@ -309,32 +429,46 @@ func (d *RepositoryTagsDataSource) Read(ctx context.Context, req datasource.Read
 		return
 	}
 
-	tags, err := d.client.GetRepositoryTags(ctx, namespace, name)
+	filter, diags := newTagFilter(data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	sortBy := data.SortBy.ValueString()
+	limit := int(data.Limit.ValueInt64())
+	params := hubclient.ListRepositoryTagsParams{
+		Name:  filter.namePrefix(),
+		Match: filter.matches,
+	}
+	switch sortBy {
+	case "":
+		params.Ordering = hubclient.TagOrderingLastUpdated
+		params.Limit = limit
+	case tagSortName:
+		params.Ordering = hubclient.TagOrderingName
+		params.Limit = limit
+	}
+	// Docker Hub can only order by last_updated or name. Sorting by when
+	// tags were last pushed or pulled needs every page, as last_updated does
+	// not always follow tag_last_pushed.
+
+	tags, err := d.client.ListRepositoryTags(ctx, namespace, name, params)
 	if err != nil {
 		resp.Diagnostics.AddError("Docker Hub API error reading repository tags", "Could not read repository tags, unexpected error: "+err.Error())
 		return
 	}
 
+	selected := sortTags(tags.Results, sortBy)
+	if limit > 0 && len(selected) > limit {
+		selected = selected[:limit]
+	}
+
 	// Convert tags to map structure
+	tagNames := make([]string, 0, len(selected))
 	tagsMap := make(map[string]RepositoryTagModel)
-	for _, tag := range tags.Results {
-		// Convert images
-		var images []TagImageModel
-		for _, img := range tag.Images {
-			images = append(images, TagImageModel{
-				Architecture: types.StringValue(img.Architecture),
-				Features:     types.StringValue(img.Features),
-				Variant:      types.StringValue(img.Variant),
-				Digest:       types.StringValue(img.Digest),
-				OS:           types.StringValue(img.OS),
-				OSFeatures:   types.StringValue(img.OSFeatures),
-				OSVersion:    types.StringValue(img.OSVersion),
-				Size:         types.Int64Value(img.Size),
-				Status:       types.StringValue(img.Status),
-				LastPulled:   types.StringValue(img.LastPulled),
-				LastPushed:   types.StringValue(img.LastPushed),
-			})
-		}
+	for _, tag := range selected {
+		tagNames = append(tagNames, tag.Name)
 
 		tagsMap[tag.Name] = RepositoryTagModel{
 			Name:            types.StringValue(tag.Name),
@@ -353,11 +487,17 @@ func (d *RepositoryTagsDataSource) Read(ctx context.Context, req datasource.Read
 			MediaType:       types.StringValue(tag.MediaType),
 			ContentType:     types.StringValue(tag.ContentType),
 			Digest:          types.StringValue(tag.Digest),
-			Images:          images,
+			Images:          newTagImageModels(tag.Images),
 		}
 	}
 
 	data.ID = types.StringValue(id)
+	data.TagNames, diags = types.ListValueFrom(ctx, types.StringType, tagNames)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	tagsMapValue, diags := types.MapValueFrom(ctx, types.ObjectType{
 		AttrTypes: map[string]attr.Type{
 			"name":                  types.StringType,
@@ -409,3 +549,103 @@ func (d *RepositoryTagsDataSource) Read(ctx context.Context, req datasource.Read
 		return
 	}
 }
+
+// tagFilter holds the filters of the tags data source that Docker Hub does
+// not support.
+type tagFilter struct {
+	nameRegex    *regexp.Regexp
+	pushedAfter  time.Time
+	architecture string
+	os           string
+	status       string
+}
+
+// newTagFilter builds the filter from the configuration. Unknown values are
+// ignored, so it can be used to validate the configuration.
+func newTagFilter(data RepositoryTagsDataSourceModel) (tagFilter, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	filter := tagFilter{
+		architecture: data.Architecture.ValueString(),
+		os:           data.OS.ValueString(),
+		status:       data.Status.ValueString(),
+	}
+
+	if !data.NameRegex.IsNull() && !data.NameRegex.IsUnknown() {
+		re, err := regexp.Compile(data.NameRegex.ValueString())
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("name_regex"),
+				"Invalid Name Regex",
+				fmt.Sprintf("name_regex is not a valid regular expression: %v", err),
+			)
+		}
+		filter.nameRegex = re
+	}
+
+	if !data.PushedAfter.IsNull() && !data.PushedAfter.IsUnknown() {
+		t, err := time.Parse(time.RFC3339, data.PushedAfter.ValueString())
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("pushed_after"),
+				"Invalid Timestamp",
+				fmt.Sprintf("pushed_after must be an RFC 3339 timestamp such as 2024-01-01T00:00:00Z: %v", err),
+			)
+		}
+		filter.pushedAfter = t
+	}
+
+	return filter, diags
+}
+
+// namePrefix returns a string every tag matching name_regex contains, for
+// Docker Hub to filter on.
+func (f tagFilter) namePrefix() string {
+	if f.nameRegex == nil {
+		return ""
+	}
+	prefix, _ := f.nameRegex.LiteralPrefix()
+	return prefix
+}
+
+func (f tagFilter) matches(tag hubclient.Tag) bool {
+	if f.nameRegex != nil && !f.nameRegex.MatchString(tag.Name) {
+		return false
+	}
+	if !f.pushedAfter.IsZero() && !tagLastPushed(tag).After(f.pushedAfter) {
+		return false
+	}
+	if f.status != "" && tag.TagStatus != f.status {
+		return false
+	}
+	if f.architecture == "" && f.os == "" {
+		return true
+	}
+	for _, img := range tag.Images {
+		if (f.architecture == "" || img.Architecture == f.architecture) && (f.os == "" || img.OS == f.os) {
+			return true
+		}
+	}
+	return false
+}
+
+// sortTags sorts tags by the given sort_by value. Tags are kept in the order
+// Docker Hub returned them if it is empty.
+func sortTags(tags []hubclient.Tag, sortBy string) []hubclient.Tag {
+	sorted := append([]hubclient.Tag(nil), tags...)
+	switch sortBy {
+	case tagSortName:
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return sorted[i].Name < sorted[j].Name
+		})
+	case tagSortLastPushed:
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return tagLastPushed(sorted[i]).After(tagLastPushed(sorted[j]))
+		})
+	case tagSortLastPulled:
+		// Tags that were never pulled come last.
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return parseHubTime(sorted[i].TagLastPulled).After(parseHubTime(sorted[j].TagLastPulled))
+		})
+	}
+	return sorted
+}
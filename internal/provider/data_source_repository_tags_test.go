@@ -17,6 +17,7 @@
 package provider
 
 import (
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
@@ -38,6 +39,33 @@ func TestAccRepositoryTagsDataSource(t *testing.T) {
 					resource.TestCheckResourceAttrSet("data.docker_hub_repository_tags.test", "tags.latest.full_size"),
 				),
 			},
+			{
+				Config: testAccRepositoryTagsDataSourceFilteredConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.docker_hub_repository_tags.test", "tag_names.#", "1"),
+					resource.TestCheckResourceAttr("data.docker_hub_repository_tags.test", "tag_names.0", "latest"),
+					resource.TestCheckResourceAttr("data.docker_hub_repository_tags.test", "tags.%", "1"),
+					resource.TestCheckResourceAttrSet("data.docker_hub_repository_tags.test", "tags.latest.digest"),
+				),
+			},
+			{
+				Config: `
+data "docker_hub_repository_tags" "test" {
+  repository = "library/hello-world"
+  name_regex = "^v1.(0"
+}
+`,
+				ExpectError: regexp.MustCompile("Invalid Name Regex"),
+			},
+			{
+				Config: `
+data "docker_hub_repository_tags" "test" {
+  repository   = "library/hello-world"
+  pushed_after = "yesterday"
+}
+`,
+				ExpectError: regexp.MustCompile("Invalid Timestamp"),
+			},
 		},
 	})
 }
@@ -48,3 +76,16 @@ data "docker_hub_repository_tags" "test" {
   name      = "hello-world"
 }
 `
+
+const testAccRepositoryTagsDataSourceFilteredConfig = `
+data "docker_hub_repository_tags" "test" {
+  repository   = "library/hello-world"
+  name_regex   = "^latest$"
+  pushed_after = "2015-01-01T00:00:00Z"
+  architecture = "amd64"
+  os           = "linux"
+  status       = "active"
+  sort_by      = "name"
+  limit        = 1
+}
+`
@@ -0,0 +1,185 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/docker/terraform-provider-docker/internal/hubclient"
+	"github.com/docker/terraform-provider-docker/internal/repositoryutils"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ datasource.DataSource              = &RepositoryWebhooksDataSource{}
+	_ datasource.DataSourceWithConfigure = &RepositoryWebhooksDataSource{}
+)
+
+func NewRepositoryWebhooksDataSource() datasource.DataSource {
+	return &RepositoryWebhooksDataSource{}
+}
+
+type RepositoryWebhooksDataSource struct {
+	client *hubclient.Client
+}
+
+type RepositoryWebhooksDataSourceModel struct {
+	ID        types.String             `tfsdk:"id"`
+	Namespace types.String             `tfsdk:"namespace"`
+	Name      types.String             `tfsdk:"name"`
+	Webhooks  []RepositoryWebhookModel `tfsdk:"webhooks"`
+}
+
+type RepositoryWebhookModel struct {
+	Name                types.String `tfsdk:"name"`
+	Slug                types.String `tfsdk:"slug"`
+	URL                 types.String `tfsdk:"url"`
+	ExpectFinalCallback types.Bool   `tfsdk:"expect_final_callback"`
+	Registry            types.String `tfsdk:"registry"`
+	Creator             types.String `tfsdk:"creator"`
+	Created             types.String `tfsdk:"created"`
+	LastUpdated         types.String `tfsdk:"last_updated"`
+}
+
+func (d *RepositoryWebhooksDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_hub_repository_webhooks"
+}
+
+func (d *RepositoryWebhooksDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: `Retrieves the webhooks of a Docker Hub repository. All webhooks are automatically fetched using internal pagination.
+
+## Example Usage
+
+` + "```hcl" + `
+data "docker_hub_repository_webhooks" "example" {
+  namespace = "my-organization"
+  name      = "my-repo"
+}
+
+output "webhook_urls" {
+  value = [for w in data.docker_hub_repository_webhooks.example.webhooks : w.url]
+}
+` + "```" + `
+`,
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The namespace/name of the repository",
+				Computed:            true,
+			},
+			"namespace": schema.StringAttribute{
+				MarkdownDescription: "Repository namespace",
+				Required:            true,
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Repository name",
+				Required:            true,
+			},
+			"webhooks": schema.ListNestedAttribute{
+				MarkdownDescription: "List of webhooks",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Computed: true,
+						},
+						"slug": schema.StringAttribute{
+							Computed: true,
+						},
+						"url": schema.StringAttribute{
+							Computed: true,
+						},
+						"expect_final_callback": schema.BoolAttribute{
+							Computed: true,
+						},
+						"registry": schema.StringAttribute{
+							Computed: true,
+						},
+						"creator": schema.StringAttribute{
+							Computed: true,
+						},
+						"created": schema.StringAttribute{
+							Computed: true,
+						},
+						"last_updated": schema.StringAttribute{
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *RepositoryWebhooksDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *provider.ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = providerData.Hub
+}
+
+func (d *RepositoryWebhooksDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data RepositoryWebhooksDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id := repositoryutils.NewID(data.Namespace.ValueString(), data.Name.ValueString())
+	webhooks, err := d.client.ListRepositoryWebhooks(ctx, id)
+	if err != nil {
+		resp.Diagnostics.AddError("Docker Hub API error reading repository webhooks", fmt.Sprintf("%v", err))
+		return
+	}
+
+	webhookList := []RepositoryWebhookModel{}
+	for _, webhook := range webhooks.Results {
+		webhookList = append(webhookList, RepositoryWebhookModel{
+			Name:                types.StringValue(webhook.Name),
+			Slug:                types.StringValue(webhook.Slug),
+			URL:                 types.StringValue(webhook.URL()),
+			ExpectFinalCallback: types.BoolValue(webhook.ExpectFinalCallback),
+			Registry:            types.StringValue(webhook.Registry),
+			Creator:             types.StringValue(webhook.Creator),
+			Created:             types.StringValue(webhook.Created),
+			LastUpdated:         types.StringValue(webhook.LastUpdated),
+		})
+	}
+
+	data.ID = types.StringValue(id)
+	data.Webhooks = webhookList
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
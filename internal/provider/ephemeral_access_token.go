@@ -0,0 +1,195 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/docker/terraform-provider-docker/internal/hubclient"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ ephemeral.EphemeralResource              = &AccessTokenEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithConfigure = &AccessTokenEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithClose     = &AccessTokenEphemeralResource{}
+)
+
+func NewAccessTokenEphemeralResource() ephemeral.EphemeralResource {
+	return &AccessTokenEphemeralResource{}
+}
+
+type AccessTokenEphemeralResource struct {
+	client *hubclient.Client
+}
+
+type AccessTokenEphemeralResourceModel struct {
+	UUID       types.String `tfsdk:"uuid"`
+	TokenLabel types.String `tfsdk:"token_label"`
+	Scopes     types.List   `tfsdk:"scopes"`
+	ExpiresAt  types.String `tfsdk:"expires_at"`
+	Username   types.String `tfsdk:"username"`
+	Token      types.String `tfsdk:"token"`
+	CreatedAt  types.String `tfsdk:"created_at"`
+}
+
+// accessTokenPrivateKey is the private data key holding the UUID of the
+// token to revoke on close.
+const accessTokenPrivateKey = "uuid"
+
+func (r *AccessTokenEphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Ephemeral Resource Configure Type",
+			fmt.Sprintf("Expected *provider.ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = providerData.Hub
+}
+
+func (r *AccessTokenEphemeralResource) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_access_token"
+}
+
+func (r *AccessTokenEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: `Creates a personal access token for the duration of a Terraform run, and revokes it when the run is done. Unlike the ` + "`docker_access_token`" + ` resource, the token is never written to the state.
+
+## Example Usage
+
+` + "```hcl" + `
+ephemeral "docker_access_token" "ci" {
+  token_label = "terraform-run"
+  scopes      = ["repo:read"]
+}
+
+provider "helm" {
+  registry {
+    url      = "oci://registry-1.docker.io"
+    username = ephemeral.docker_access_token.ci.username
+    password = ephemeral.docker_access_token.ci.token
+  }
+}
+` + "```" + `
+
+Set ` + "`expires_at`" + ` so the token still expires if the run is interrupted before it is revoked.
+`,
+		Attributes: map[string]schema.Attribute{
+			"uuid": schema.StringAttribute{
+				MarkdownDescription: "The UUID of the access token",
+				Computed:            true,
+			},
+			"token_label": schema.StringAttribute{
+				MarkdownDescription: "Token label",
+				Required:            true,
+			},
+			"scopes": schema.ListAttribute{
+				MarkdownDescription: "List of scopes",
+				Required:            true,
+				ElementType:         types.StringType,
+			},
+			"expires_at": schema.StringAttribute{
+				MarkdownDescription: "Time the token expires, in case it isn't revoked at the end of the run. If not set, the token will not expire",
+				Optional:            true,
+				Validators: []validator.String{
+					accessTokenExpiresAtValidator,
+				},
+			},
+			"username": schema.StringAttribute{
+				MarkdownDescription: "The username the token authenticates as",
+				Computed:            true,
+			},
+			"token": schema.StringAttribute{
+				MarkdownDescription: "The token itself",
+				Computed:            true,
+				Sensitive:           true,
+			},
+			"created_at": schema.StringAttribute{
+				MarkdownDescription: "Time the token was created",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *AccessTokenEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data AccessTokenEphemeralResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	scopes := []string{}
+	resp.Diagnostics.Append(data.Scopes.ElementsAs(ctx, &scopes, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	at, err := r.client.CreateAccessToken(ctx, hubclient.AccessTokenCreateParams{
+		TokenLabel: data.TokenLabel.ValueString(),
+		Scopes:     scopes,
+		ExpiresAt:  data.ExpiresAt.ValueString(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to create access token", err.Error())
+		return
+	}
+
+	private, err := json.Marshal(at.UUID)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to save access token", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, accessTokenPrivateKey, private)...)
+
+	data.UUID = types.StringValue(at.UUID)
+	data.Username = types.StringValue(r.client.Username())
+	data.Token = types.StringValue(at.Token)
+	data.CreatedAt = types.StringValue(at.CreatedAt)
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
+
+func (r *AccessTokenEphemeralResource) Close(ctx context.Context, req ephemeral.CloseRequest, resp *ephemeral.CloseResponse) {
+	private, diags := req.Private.GetKey(ctx, accessTokenPrivateKey)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var uuid string
+	if err := json.Unmarshal(private, &uuid); err != nil {
+		resp.Diagnostics.AddError("Unable to read access token", err.Error())
+		return
+	}
+
+	err := r.client.DeleteAccessToken(ctx, uuid)
+	if err != nil && !hubclient.IsNotFound(err) {
+		resp.Diagnostics.AddError("Unable to revoke access token", err.Error())
+	}
+}
@@ -0,0 +1,155 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/docker/terraform-provider-docker/internal/auth"
+	"github.com/docker/terraform-provider-docker/internal/hubclient"
+	"github.com/docker/terraform-provider-docker/internal/hubtest"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// objectValue returns an object of the given schema, with the given
+// attributes set and the others null.
+func objectValue(t *testing.T, s *tfprotov6.Schema, attributes map[string]tftypes.Value) *tfprotov6.DynamicValue {
+	t.Helper()
+	objectType := s.ValueType().(tftypes.Object)
+	values := map[string]tftypes.Value{}
+	for name, attributeType := range objectType.AttributeTypes {
+		values[name] = tftypes.NewValue(attributeType, nil)
+		if value, ok := attributes[name]; ok {
+			values[name] = value
+		}
+	}
+	value, err := tfprotov6.NewDynamicValue(objectType, tftypes.NewValue(objectType, values))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &value
+}
+
+func checkDiagnostics(t *testing.T, diags []*tfprotov6.Diagnostic) {
+	t.Helper()
+	for _, d := range diags {
+		if d.Severity == tfprotov6.DiagnosticSeverityError {
+			t.Fatalf("%s: %s", d.Summary, d.Detail)
+		}
+	}
+}
+
+func TestEphemeralAccessToken(t *testing.T) {
+	ctx := context.Background()
+	server := hubtest.NewServer()
+	defer server.Close()
+	server.AddUser("alice", "secret", "alice@example.com")
+
+	t.Setenv("DOCKER_HUB_HOST", server.URL)
+	t.Setenv("DOCKER_USERNAME", "alice")
+	t.Setenv("DOCKER_PASSWORD", "secret")
+	t.Setenv("DOCKER_CONFIG", t.TempDir())
+
+	providerServer := providerserver.NewProtocol6(New("test")())().(tfprotov6.ProviderServerWithEphemeralResources)
+	schemaResp, err := providerServer.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkDiagnostics(t, schemaResp.Diagnostics)
+	tokenSchema, ok := schemaResp.EphemeralResourceSchemas["docker_access_token"]
+	if !ok {
+		t.Fatalf("no docker_access_token ephemeral resource in %v", schemaResp.EphemeralResourceSchemas)
+	}
+	if _, ok := schemaResp.EphemeralResourceSchemas["docker_org_access_token"]; !ok {
+		t.Fatalf("no docker_org_access_token ephemeral resource in %v", schemaResp.EphemeralResourceSchemas)
+	}
+
+	configureResp, err := providerServer.ConfigureProvider(ctx, &tfprotov6.ConfigureProviderRequest{
+		Config: objectValue(t, schemaResp.Provider, nil),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkDiagnostics(t, configureResp.Diagnostics)
+
+	config := objectValue(t, tokenSchema, map[string]tftypes.Value{
+		"token_label": tftypes.NewValue(tftypes.String, "terraform-run"),
+		"scopes": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{
+			tftypes.NewValue(tftypes.String, "repo:read"),
+		}),
+	})
+	validateResp, err := providerServer.ValidateEphemeralResourceConfig(ctx, &tfprotov6.ValidateEphemeralResourceConfigRequest{
+		TypeName: "docker_access_token",
+		Config:   config,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkDiagnostics(t, validateResp.Diagnostics)
+
+	openResp, err := providerServer.OpenEphemeralResource(ctx, &tfprotov6.OpenEphemeralResourceRequest{
+		TypeName: "docker_access_token",
+		Config:   config,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkDiagnostics(t, openResp.Diagnostics)
+
+	result, err := openResp.Result.Unmarshal(tokenSchema.ValueType())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var attributes map[string]tftypes.Value
+	if err := result.As(&attributes); err != nil {
+		t.Fatal(err)
+	}
+	var uuid, token, username string
+	for name, target := range map[string]*string{"uuid": &uuid, "token": &token, "username": &username} {
+		if err := attributes[name].As(target); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if token == "" || username != "alice" {
+		t.Fatalf("got token %q for %q, want a token for alice", token, username)
+	}
+
+	client := hubclient.NewClient(hubclient.Config{
+		BaseURL:       server.BaseURL(),
+		TokenProvider: auth.NewLoginTokenProvider("alice", auth.StaticPassword("secret"), server.BaseURL(), http.DefaultTransport),
+	})
+	if _, err := client.GetAccessToken(ctx, uuid); err != nil {
+		t.Fatalf("token wasn't created: %v", err)
+	}
+
+	closeResp, err := providerServer.CloseEphemeralResource(ctx, &tfprotov6.CloseEphemeralResourceRequest{
+		TypeName: "docker_access_token",
+		Private:  openResp.Private,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkDiagnostics(t, closeResp.Diagnostics)
+
+	if _, err := client.GetAccessToken(ctx, uuid); !hubclient.IsNotFound(err) {
+		t.Fatalf("token wasn't revoked: %v", err)
+	}
+}
@@ -0,0 +1,248 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/docker/terraform-provider-docker/internal/hubclient"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ ephemeral.EphemeralResource                   = &OrgAccessTokenEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithConfigure      = &OrgAccessTokenEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithClose          = &OrgAccessTokenEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithValidateConfig = &OrgAccessTokenEphemeralResource{}
+)
+
+func NewOrgAccessTokenEphemeralResource() ephemeral.EphemeralResource {
+	return &OrgAccessTokenEphemeralResource{}
+}
+
+type OrgAccessTokenEphemeralResource struct {
+	client *hubclient.Client
+}
+
+type OrgAccessTokenEphemeralResourceModel struct {
+	ID          types.String                       `tfsdk:"id"`
+	OrgName     types.String                       `tfsdk:"org_name"`
+	Label       types.String                       `tfsdk:"label"`
+	Description types.String                       `tfsdk:"description"`
+	Resources   []OrgAccessTokenResourceEntryModel `tfsdk:"resources"`
+	ExpiresAt   types.String                       `tfsdk:"expires_at"`
+	Token       types.String                       `tfsdk:"token"`
+	CreatedAt   types.String                       `tfsdk:"created_at"`
+}
+
+// orgAccessTokenPrivateKey is the private data key holding the token to
+// revoke on close.
+const orgAccessTokenPrivateKey = "token"
+
+type orgAccessTokenPrivate struct {
+	OrgName string `json:"org_name"`
+	ID      string `json:"id"`
+}
+
+func (r *OrgAccessTokenEphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Ephemeral Resource Configure Type",
+			fmt.Sprintf("Expected *provider.ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = providerData.Hub
+}
+
+func (r *OrgAccessTokenEphemeralResource) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_org_access_token"
+}
+
+func (r *OrgAccessTokenEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: `Creates an organization access token for the duration of a Terraform run, and revokes it when the run is done. Unlike the ` + "`docker_org_access_token`" + ` resource, the token is never written to the state.
+
+-> **Note**: This ephemeral resource is only available when authenticated with a username and password as an owner of the org.
+
+## Example Usage
+
+` + "```hcl" + `
+ephemeral "docker_org_access_token" "ci" {
+  org_name = "my-organization"
+  label    = "terraform-run"
+  resources = [
+    {
+      type   = "TYPE_REPO"
+      path   = "my-organization/*"
+      scopes = ["scope-image-pull"]
+    }
+  ]
+}
+` + "```" + `
+
+Set ` + "`expires_at`" + ` so the token still expires if the run is interrupted before it is revoked.
+`,
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the organization access token",
+				Computed:            true,
+			},
+			"org_name": schema.StringAttribute{
+				MarkdownDescription: "The organization namespace",
+				Required:            true,
+			},
+			"label": schema.StringAttribute{
+				MarkdownDescription: "Label for the access token",
+				Required:            true,
+			},
+			"description": schema.StringAttribute{
+				MarkdownDescription: "Description for the access token",
+				Optional:            true,
+			},
+			"resources": schema.ListNestedAttribute{
+				MarkdownDescription: "Resources this token has access to",
+				Required:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"type": schema.StringAttribute{
+							MarkdownDescription: "The type of resource",
+							Required:            true,
+							Validators: []validator.String{
+								stringvalidator.OneOf(hubclient.OrgAccessTokenTypeRepo, hubclient.OrgAccessTokenTypeOrg),
+							},
+						},
+						"path": schema.StringAttribute{
+							MarkdownDescription: "The path of the resource. For TYPE_REPO, this must point to an existing repository or a supported glob such as `my-organization/*`. Use `*/*/public` for public repositories only.",
+							Required:            true,
+						},
+						"scopes": schema.ListAttribute{
+							MarkdownDescription: "The scopes this token has access to",
+							Required:            true,
+							ElementType:         types.StringType,
+						},
+					},
+				},
+			},
+			"expires_at": schema.StringAttribute{
+				MarkdownDescription: "Expiration date for the token, in case it isn't revoked at the end of the run",
+				Optional:            true,
+				Validators: []validator.String{
+					accessTokenExpiresAtValidator,
+				},
+			},
+			"token": schema.StringAttribute{
+				MarkdownDescription: "The organization access token",
+				Computed:            true,
+				Sensitive:           true,
+			},
+			"created_at": schema.StringAttribute{
+				MarkdownDescription: "Time the token was created",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *OrgAccessTokenEphemeralResource) ValidateConfig(ctx context.Context, req ephemeral.ValidateConfigRequest, resp *ephemeral.ValidateConfigResponse) {
+	var orgName types.String
+	var resources types.List
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("org_name"), &orgName)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("resources"), &resources)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if resources.IsNull() || resources.IsUnknown() {
+		return
+	}
+	var entries []OrgAccessTokenResourceEntryModel
+	resp.Diagnostics.Append(resources.ElementsAs(ctx, &entries, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(validateOrgAccessTokenResources(ctx, orgName, entries)...)
+}
+
+func (r *OrgAccessTokenEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data OrgAccessTokenEphemeralResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tokenResources, diags := expandOrgAccessTokenResources(ctx, data.Resources)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	at, err := r.client.CreateOrgAccessToken(ctx, data.OrgName.ValueString(), hubclient.OrgAccessTokenCreateParams{
+		Label:       data.Label.ValueString(),
+		Description: data.Description.ValueString(),
+		Resources:   tokenResources,
+		ExpiresAt:   data.ExpiresAt.ValueString(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to create org access token", err.Error())
+		return
+	}
+
+	private, err := json.Marshal(orgAccessTokenPrivate{OrgName: data.OrgName.ValueString(), ID: at.ID})
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to save org access token", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, orgAccessTokenPrivateKey, private)...)
+
+	data.ID = types.StringValue(at.ID)
+	data.Token = types.StringValue(at.Token)
+	data.CreatedAt = types.StringValue(at.CreatedAt)
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
+
+func (r *OrgAccessTokenEphemeralResource) Close(ctx context.Context, req ephemeral.CloseRequest, resp *ephemeral.CloseResponse) {
+	private, diags := req.Private.GetKey(ctx, orgAccessTokenPrivateKey)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var token orgAccessTokenPrivate
+	if err := json.Unmarshal(private, &token); err != nil {
+		resp.Diagnostics.AddError("Unable to read org access token", err.Error())
+		return
+	}
+
+	err := r.client.DeleteOrgAccessToken(ctx, token.OrgName, token.ID)
+	if err != nil && !hubclient.IsNotFound(err) {
+		resp.Diagnostics.AddError("Unable to revoke org access token", err.Error())
+	}
+}
@@ -0,0 +1,70 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestEphemeralOrgAccessTokenValidation(t *testing.T) {
+	ctx := context.Background()
+	providerServer := providerserver.NewProtocol6(New("test")())().(tfprotov6.ProviderServerWithEphemeralResources)
+	schemaResp, err := providerServer.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokenSchema := schemaResp.EphemeralResourceSchemas["docker_org_access_token"]
+
+	resourceType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"type":   tftypes.String,
+		"path":   tftypes.String,
+		"scopes": tftypes.List{ElementType: tftypes.String},
+	}}
+	validateResp, err := providerServer.ValidateEphemeralResourceConfig(ctx, &tfprotov6.ValidateEphemeralResourceConfigRequest{
+		TypeName: "docker_org_access_token",
+		Config: objectValue(t, tokenSchema, map[string]tftypes.Value{
+			"org_name":   tftypes.NewValue(tftypes.String, "acme"),
+			"label":      tftypes.NewValue(tftypes.String, "terraform-run"),
+			"expires_at": tftypes.NewValue(tftypes.String, "tomorrow"),
+			"resources": tftypes.NewValue(tftypes.List{ElementType: resourceType}, []tftypes.Value{
+				tftypes.NewValue(resourceType, map[string]tftypes.Value{
+					"type": tftypes.NewValue(tftypes.String, "TYPE_REPO"),
+					"path": tftypes.NewValue(tftypes.String, "acme/*"),
+					"scopes": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{
+						tftypes.NewValue(tftypes.String, "scope-member-read"),
+					}),
+				}),
+			}),
+		}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var summaries []string
+	for _, d := range validateResp.Diagnostics {
+		summaries = append(summaries, d.Summary)
+	}
+	if len(summaries) != 2 {
+		t.Fatalf("got diagnostics %q, want an invalid expires_at and an invalid scope", summaries)
+	}
+}
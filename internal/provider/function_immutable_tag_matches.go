@@ -0,0 +1,103 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+
+	"github.com/docker/terraform-provider-docker/internal/hubclient"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ function.Function = &ImmutableTagMatchesFunction{}
+
+func NewImmutableTagMatchesFunction() function.Function {
+	return &ImmutableTagMatchesFunction{}
+}
+
+type ImmutableTagMatchesFunction struct{}
+
+func (f *ImmutableTagMatchesFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "immutable_tag_matches"
+}
+
+func (f *ImmutableTagMatchesFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Check whether a tag is protected by immutable tag rules",
+		MarkdownDescription: `Returns true if the tag matches any of the immutable tag rules, using the same rules as the ` + "`immutable_tags_settings.rules`" + ` attribute of ` + "`docker_hub_repository`" + `.
+
+Each rule is a regular expression (RE2 syntax) that must match the whole tag.
+An empty list of rules matches no tag. Note that enabling immutable tags on a
+repository without any rules protects every tag.
+
+## Example Usage
+
+` + "```hcl" + `
+locals {
+  rules = ["v[0-9]+\\..*", "latest"]
+}
+
+resource "docker_hub_repository" "example" {
+  namespace = "my-org"
+  name      = "my-repo"
+
+  immutable_tags_settings = {
+    enabled = true
+    rules   = local.rules
+  }
+
+  lifecycle {
+    precondition {
+      condition     = !provider::docker::immutable_tag_matches(local.rules, "edge")
+      error_message = "The edge tag must stay mutable."
+    }
+  }
+}
+` + "```" + `
+`,
+		Parameters: []function.Parameter{
+			function.ListParameter{
+				Name:                "rules",
+				ElementType:         types.StringType,
+				MarkdownDescription: "Immutable tag rules",
+			},
+			function.StringParameter{
+				Name:                "tag",
+				MarkdownDescription: "Tag name to check",
+			},
+		},
+		Return: function.BoolReturn{},
+	}
+}
+
+func (f *ImmutableTagMatchesFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var rules []string
+	var tag string
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &rules, &tag))
+	if resp.Error != nil {
+		return
+	}
+
+	matches, err := hubclient.ImmutableTagMatches(rules, tag)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, matches))
+}
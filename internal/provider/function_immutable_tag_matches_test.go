@@ -0,0 +1,73 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccImmutableTagMatchesFunction(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+locals {
+  rules = ["v[0-9]+\\..*", "latest"]
+}
+
+output "release" {
+  value = provider::docker::immutable_tag_matches(local.rules, "v1.2.3")
+}
+
+output "latest" {
+  value = provider::docker::immutable_tag_matches(local.rules, "latest")
+}
+
+output "partial" {
+  value = provider::docker::immutable_tag_matches(local.rules, "latest-dev")
+}
+
+output "none" {
+  value = provider::docker::immutable_tag_matches([], "latest")
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckOutput("release", "true"),
+					resource.TestCheckOutput("latest", "true"),
+					resource.TestCheckOutput("partial", "false"),
+					resource.TestCheckOutput("none", "false"),
+				),
+			},
+			{
+				Config: `
+output "test" {
+  value = provider::docker::immutable_tag_matches(["v[0-9"], "v1")
+}
+`,
+				ExpectError: regexp.MustCompile(`not a valid regular expression`),
+			},
+		},
+	})
+}
@@ -0,0 +1,116 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+
+	"github.com/docker/terraform-provider-docker/internal/imageref"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ function.Function = &ParseImageRefFunction{}
+
+var imageRefAttrTypes = map[string]attr.Type{
+	"registry":   types.StringType,
+	"namespace":  types.StringType,
+	"repository": types.StringType,
+	"tag":        types.StringType,
+	"digest":     types.StringType,
+}
+
+func NewParseImageRefFunction() function.Function {
+	return &ParseImageRefFunction{}
+}
+
+type ParseImageRefFunction struct{}
+
+func (f *ParseImageRefFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "parse_image_ref"
+}
+
+func (f *ParseImageRefFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Parse an image reference",
+		MarkdownDescription: `Parses an image reference into its registry, namespace, repository, tag and digest.
+
+References are normalized the same way as the ` + "`docker`" + ` CLI does:
+references without a registry default to ` + "`docker.io`" + `, single
+component Docker Hub names are in the ` + "`library`" + ` namespace, and
+references without a tag or digest use the ` + "`latest`" + ` tag. Other
+known Docker Hub hosts, such as ` + "`index.docker.io`" + `, are normalized to
+` + "`docker.io`" + `.
+
+Attributes that are not part of the reference, such as the digest of a tagged
+reference, are null.
+
+## Example Usage
+
+` + "```hcl" + `
+output "image" {
+  # {
+  #   registry   = "docker.io"
+  #   namespace  = "library"
+  #   repository = "nginx"
+  #   tag        = "1.27"
+  #   digest     = null
+  # }
+  value = provider::docker::parse_image_ref("nginx:1.27")
+}
+` + "```" + `
+`,
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "ref",
+				MarkdownDescription: "Image reference, such as `nginx`, `docker/app:1.0` or `ghcr.io/org/app@sha256:...`",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: imageRefAttrTypes,
+		},
+	}
+}
+
+func (f *ParseImageRefFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var ref string
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &ref))
+	if resp.Error != nil {
+		return
+	}
+
+	parsed, err := imageref.Parse(ref)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	result, diags := types.ObjectValue(imageRefAttrTypes, map[string]attr.Value{
+		"registry":   types.StringValue(parsed.Registry),
+		"namespace":  stringNullIfEmpty(parsed.Namespace),
+		"repository": types.StringValue(parsed.Repository),
+		"tag":        stringNullIfEmpty(parsed.Tag),
+		"digest":     stringNullIfEmpty(parsed.Digest),
+	})
+	resp.Error = function.FuncErrorFromDiags(ctx, diags)
+	if resp.Error != nil {
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
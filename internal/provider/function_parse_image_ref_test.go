@@ -0,0 +1,94 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccParseImageRefFunction(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+locals {
+  short  = provider::docker::parse_image_ref("nginx")
+  pinned = provider::docker::parse_image_ref("ghcr.io/org/team/app@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef")
+}
+
+output "short_registry" {
+  value = local.short.registry
+}
+
+output "short_namespace" {
+  value = local.short.namespace
+}
+
+output "short_repository" {
+  value = local.short.repository
+}
+
+output "short_tag" {
+  value = local.short.tag
+}
+
+output "pinned_registry" {
+  value = local.pinned.registry
+}
+
+output "pinned_namespace" {
+  value = local.pinned.namespace
+}
+
+output "pinned_digest" {
+  value = local.pinned.digest
+}
+
+output "pinned_has_tag" {
+  value = local.pinned.tag != null
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckOutput("short_registry", "docker.io"),
+					resource.TestCheckOutput("short_namespace", "library"),
+					resource.TestCheckOutput("short_repository", "nginx"),
+					resource.TestCheckOutput("short_tag", "latest"),
+					resource.TestCheckOutput("pinned_registry", "ghcr.io"),
+					resource.TestCheckOutput("pinned_namespace", "org/team"),
+					resource.TestCheckOutput("pinned_digest", "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"),
+					resource.TestCheckOutput("pinned_has_tag", "false"),
+				),
+			},
+			{
+				Config: `
+output "test" {
+  value = provider::docker::parse_image_ref("Not A Reference")
+}
+`,
+				ExpectError: regexp.MustCompile(`invalid repository name`),
+			},
+		},
+	})
+}
@@ -0,0 +1,164 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/docker/terraform-provider-docker/internal/repositoryutils"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ function.Function = &RepositoryIDFunction{}
+	_ function.Function = &SplitRepositoryIDFunction{}
+)
+
+var repositoryIDAttrTypes = map[string]attr.Type{
+	"namespace": types.StringType,
+	"name":      types.StringType,
+}
+
+// validRepositoryIDPart checks a namespace or repository name. Neither may be
+// empty or contain a slash, otherwise the ID cannot be split again.
+func validRepositoryIDPart(part string) bool {
+	return part != "" && !strings.Contains(part, "/")
+}
+
+func NewRepositoryIDFunction() function.Function {
+	return &RepositoryIDFunction{}
+}
+
+type RepositoryIDFunction struct{}
+
+func (f *RepositoryIDFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "repository_id"
+}
+
+func (f *RepositoryIDFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Build a repository ID",
+		MarkdownDescription: `Builds the ID of a Docker Hub repository from its namespace and name, in the same format as the ` + "`id`" + ` attribute of ` + "`docker_hub_repository`" + `.
+
+## Example Usage
+
+` + "```hcl" + `
+resource "docker_hub_repository_team_permission" "example" {
+  # "my-org/my-repo"
+  repo_id    = provider::docker::repository_id("my-org", "my-repo")
+  team_id    = docker_org_team.example.id
+  permission = "read"
+}
+` + "```" + `
+`,
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "namespace",
+				MarkdownDescription: "Repository namespace, such as a user or organization name",
+			},
+			function.StringParameter{
+				Name:                "name",
+				MarkdownDescription: "Repository name",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *RepositoryIDFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var namespace, name string
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &namespace, &name))
+	if resp.Error != nil {
+		return
+	}
+
+	if !validRepositoryIDPart(namespace) {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("namespace must be non-empty and must not contain a slash, got: %q", namespace))
+		return
+	}
+	if !validRepositoryIDPart(name) {
+		resp.Error = function.NewArgumentFuncError(1, fmt.Sprintf("name must be non-empty and must not contain a slash, got: %q", name))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, repositoryutils.NewID(namespace, name)))
+}
+
+func NewSplitRepositoryIDFunction() function.Function {
+	return &SplitRepositoryIDFunction{}
+}
+
+type SplitRepositoryIDFunction struct{}
+
+func (f *SplitRepositoryIDFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "split_repository_id"
+}
+
+func (f *SplitRepositoryIDFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Split a repository ID",
+		MarkdownDescription: `Splits the ID of a Docker Hub repository into its namespace and name. This is the inverse of ` + "`provider::docker::repository_id`" + `.
+
+## Example Usage
+
+` + "```hcl" + `
+locals {
+  # { namespace = "my-org", name = "my-repo" }
+  repo = provider::docker::split_repository_id(docker_hub_repository.example.id)
+}
+` + "```" + `
+`,
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "id",
+				MarkdownDescription: "Repository ID, in the form `namespace/name`",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: repositoryIDAttrTypes,
+		},
+	}
+}
+
+func (f *SplitRepositoryIDFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var id string
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &id))
+	if resp.Error != nil {
+		return
+	}
+
+	namespace, name := repositoryutils.SplitID(id)
+	if !validRepositoryIDPart(namespace) || !validRepositoryIDPart(name) {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("repository ID must be in the form namespace/name, got: %q", id))
+		return
+	}
+
+	result, diags := types.ObjectValue(repositoryIDAttrTypes, map[string]attr.Value{
+		"namespace": types.StringValue(namespace),
+		"name":      types.StringValue(name),
+	})
+	resp.Error = function.FuncErrorFromDiags(ctx, diags)
+	if resp.Error != nil {
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
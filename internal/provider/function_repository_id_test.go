@@ -0,0 +1,72 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccRepositoryIDFunctions(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+output "id" {
+  value = provider::docker::repository_id("my-org", "my-repo")
+}
+
+output "namespace" {
+  value = provider::docker::split_repository_id("my-org/my-repo").namespace
+}
+
+output "name" {
+  value = provider::docker::split_repository_id("my-org/my-repo").name
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckOutput("id", "my-org/my-repo"),
+					resource.TestCheckOutput("namespace", "my-org"),
+					resource.TestCheckOutput("name", "my-repo"),
+				),
+			},
+			{
+				Config: `
+output "test" {
+  value = provider::docker::repository_id("my-org", "")
+}
+`,
+				ExpectError: regexp.MustCompile(`name must be non-empty`),
+			},
+			{
+				Config: `
+output "test" {
+  value = provider::docker::split_repository_id("my-repo")
+}
+`,
+				ExpectError: regexp.MustCompile(`must be in the form namespace/name`),
+			},
+		},
+	})
+}
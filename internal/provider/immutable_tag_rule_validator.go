@@ -0,0 +1,53 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+
+	"github.com/docker/terraform-provider-docker/internal/hubclient"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+var immutableTagRuleValidator validator.String = immutableTagRuleStringValidator{}
+
+// immutableTagRuleStringValidator checks that a string is an immutable tag
+// rule Docker Hub accepts, so that bad patterns are caught at plan time
+// instead of mid-apply.
+type immutableTagRuleStringValidator struct{}
+
+func (v immutableTagRuleStringValidator) Description(_ context.Context) string {
+	return "must be a valid regular expression (RE2 syntax) without commas"
+}
+
+func (v immutableTagRuleStringValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v immutableTagRuleStringValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if _, err := hubclient.CompileImmutableTagRule(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Immutable Tag Rule",
+			err.Error(),
+		)
+	}
+}
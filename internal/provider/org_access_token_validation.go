@@ -0,0 +1,184 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/docker/terraform-provider-docker/internal/hubclient"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// orgAccessTokenPublicReposPath scopes a TYPE_REPO resource to all public
+// repositories.
+const orgAccessTokenPublicReposPath = "*/*/public"
+
+var repositoryNameRegexp = regexp.MustCompile(`^[a-z0-9]+(?:[._-][a-z0-9]+)*$`)
+
+// orgAccessTokenScopes is the scope vocabulary of each resource type. Scopes
+// outside of it are only warned about, as Docker Hub may add new ones.
+var orgAccessTokenScopes = map[string][]string{
+	hubclient.OrgAccessTokenTypeRepo: {
+		"scope-image-pull",
+		"scope-image-push",
+	},
+	hubclient.OrgAccessTokenTypeOrg: {
+		"scope-member-read",
+		"scope-member-edit",
+		"scope-member-invite",
+		"scope-team-read",
+		"scope-team-edit",
+		"scope-audit-log-read",
+	},
+}
+
+// validateOrgAccessTokenResources checks the path and scopes of each resource
+// of an organization access token. Values that are not known yet are skipped.
+func validateOrgAccessTokenResources(ctx context.Context, orgName types.String, resources []OrgAccessTokenResourceEntryModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	for i, entry := range resources {
+		if entry.Type.IsUnknown() || entry.Type.IsNull() {
+			continue
+		}
+		resourceType := entry.Type.ValueString()
+		entryPath := path.Root("resources").AtListIndex(i)
+
+		if resourceType == hubclient.OrgAccessTokenTypeRepo && !entry.Path.IsUnknown() && !entry.Path.IsNull() {
+			if detail := validateOrgAccessTokenRepoPath(orgName, entry.Path.ValueString()); detail != "" {
+				diags.AddAttributeError(entryPath.AtName("path"), "Invalid Resource Path", detail)
+			}
+		}
+
+		if entry.Scopes.IsUnknown() || entry.Scopes.IsNull() {
+			continue
+		}
+		var scopes []types.String
+		diags.Append(entry.Scopes.ElementsAs(ctx, &scopes, false)...)
+		if diags.HasError() {
+			return diags
+		}
+		for j, scope := range scopes {
+			if scope.IsUnknown() || scope.IsNull() {
+				continue
+			}
+			diags.Append(validateOrgAccessTokenScope(entryPath.AtName("scopes").AtListIndex(j), resourceType, scope.ValueString())...)
+		}
+	}
+
+	return diags
+}
+
+// validateOrgAccessTokenRepoPath checks the path of a TYPE_REPO resource,
+// which is a repository or all repositories of the organization, or all public
+// repositories. It returns what is wrong with the path, if anything.
+func validateOrgAccessTokenRepoPath(orgName types.String, repoPath string) string {
+	if repoPath == orgAccessTokenPublicReposPath {
+		return ""
+	}
+
+	namespace, name, ok := strings.Cut(repoPath, "/")
+	if !ok || namespace == "" || name == "" || strings.Contains(name, "/") {
+		return fmt.Sprintf("The path of a %s resource must be namespace/repository, namespace/* or %s, got: %q", hubclient.OrgAccessTokenTypeRepo, orgAccessTokenPublicReposPath, repoPath)
+	}
+	if !orgName.IsUnknown() && !orgName.IsNull() && namespace != orgName.ValueString() {
+		return fmt.Sprintf("The path %q must be in the %s namespace of the organization, or be %s.", repoPath, orgName.ValueString(), orgAccessTokenPublicReposPath)
+	}
+	if name == "*" {
+		return ""
+	}
+	if strings.Contains(name, "*") {
+		return fmt.Sprintf("The path %q uses an unsupported glob. Use %s/* for all repositories of the organization.", repoPath, namespace)
+	}
+	if !repositoryNameRegexp.MatchString(name) {
+		return fmt.Sprintf("The path %q does not name a valid repository. Repository names only contain lowercase alphanumeric characters, '.', '_' or '-'.", repoPath)
+	}
+	return ""
+}
+
+func validateOrgAccessTokenScope(scopePath path.Path, resourceType, scope string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if slices.Contains(orgAccessTokenScopes[resourceType], scope) {
+		return diags
+	}
+	for otherType, scopes := range orgAccessTokenScopes {
+		if otherType != resourceType && slices.Contains(scopes, scope) {
+			diags.AddAttributeError(
+				scopePath,
+				"Invalid Scope",
+				fmt.Sprintf("The scope %q applies to %s resources, not %s resources. Valid scopes are: %s.", scope, otherType, resourceType, strings.Join(orgAccessTokenScopes[resourceType], ", ")),
+			)
+			return diags
+		}
+	}
+	if strings.Contains(scope, ":") {
+		diags.AddAttributeError(
+			scopePath,
+			"Invalid Scope",
+			fmt.Sprintf("The scope %q is a personal access token scope. Valid scopes for %s resources are: %s.", scope, resourceType, strings.Join(orgAccessTokenScopes[resourceType], ", ")),
+		)
+		return diags
+	}
+
+	diags.AddAttributeWarning(
+		scopePath,
+		"Unknown Scope",
+		fmt.Sprintf("The scope %q is not a known scope for %s resources and may be rejected by Docker Hub. Known scopes are: %s.", scope, resourceType, strings.Join(orgAccessTokenScopes[resourceType], ", ")),
+	)
+	return diags
+}
+
+// checkOrgAccessTokenRepositories checks that the repositories named by the
+// TYPE_REPO resources of a token exist. Globs and values that are not known
+// yet are skipped, and failures other than a missing repository only warn.
+func checkOrgAccessTokenRepositories(ctx context.Context, client *hubclient.Client, resources []OrgAccessTokenResourceEntryModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	for i, entry := range resources {
+		if entry.Type.ValueString() != hubclient.OrgAccessTokenTypeRepo || entry.Path.IsUnknown() || entry.Path.IsNull() {
+			continue
+		}
+		repoPath := entry.Path.ValueString()
+		if strings.Contains(repoPath, "*") {
+			continue
+		}
+
+		_, err := client.GetRepository(ctx, repoPath)
+		if hubclient.IsNotFound(err) {
+			diags.AddAttributeError(
+				path.Root("resources").AtListIndex(i).AtName("path"),
+				"Repository Not Found",
+				fmt.Sprintf("The repository %s does not exist. Create it first, or unset check_repositories if it is created in the same apply.", repoPath),
+			)
+		} else if err != nil {
+			diags.AddAttributeWarning(
+				path.Root("resources").AtListIndex(i).AtName("path"),
+				"Unable to Check Repository",
+				fmt.Sprintf("Could not check that the repository %s exists: %s", repoPath, err),
+			)
+		}
+	}
+
+	return diags
+}
@@ -0,0 +1,133 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/docker/terraform-provider-docker/internal/hubclient"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestValidateOrgAccessTokenResources(t *testing.T) {
+	entry := func(resourceType, path string, scopes ...string) OrgAccessTokenResourceEntryModel {
+		return OrgAccessTokenResourceEntryModel{
+			Type:   types.StringValue(resourceType),
+			Path:   types.StringValue(path),
+			Scopes: typesListFromStringSlice(scopes),
+		}
+	}
+
+	tests := []struct {
+		name     string
+		orgName  types.String
+		entry    OrgAccessTokenResourceEntryModel
+		errors   int
+		warnings int
+	}{
+		{
+			name:    "repository",
+			orgName: types.StringValue("acme"),
+			entry:   entry(hubclient.OrgAccessTokenTypeRepo, "acme/app", "scope-image-pull", "scope-image-push"),
+		},
+		{
+			name:    "all repositories",
+			orgName: types.StringValue("acme"),
+			entry:   entry(hubclient.OrgAccessTokenTypeRepo, "acme/*", "scope-image-pull"),
+		},
+		{
+			name:    "public repositories",
+			orgName: types.StringValue("acme"),
+			entry:   entry(hubclient.OrgAccessTokenTypeRepo, "*/*/public", "scope-image-pull"),
+		},
+		{
+			name:    "unknown org name",
+			orgName: types.StringUnknown(),
+			entry:   entry(hubclient.OrgAccessTokenTypeRepo, "other/app", "scope-image-pull"),
+		},
+		{
+			name:    "other namespace",
+			orgName: types.StringValue("acme"),
+			entry:   entry(hubclient.OrgAccessTokenTypeRepo, "other/app", "scope-image-pull"),
+			errors:  1,
+		},
+		{
+			name:    "missing namespace",
+			orgName: types.StringValue("acme"),
+			entry:   entry(hubclient.OrgAccessTokenTypeRepo, "app", "scope-image-pull"),
+			errors:  1,
+		},
+		{
+			name:    "unsupported glob",
+			orgName: types.StringValue("acme"),
+			entry:   entry(hubclient.OrgAccessTokenTypeRepo, "acme/app-*", "scope-image-pull"),
+			errors:  1,
+		},
+		{
+			name:    "invalid repository name",
+			orgName: types.StringValue("acme"),
+			entry:   entry(hubclient.OrgAccessTokenTypeRepo, "acme/App", "scope-image-pull"),
+			errors:  1,
+		},
+		{
+			name:    "organization scope on repository",
+			orgName: types.StringValue("acme"),
+			entry:   entry(hubclient.OrgAccessTokenTypeRepo, "acme/*", "scope-member-read"),
+			errors:  1,
+		},
+		{
+			name:    "repository scope on organization",
+			orgName: types.StringValue("acme"),
+			entry:   entry(hubclient.OrgAccessTokenTypeOrg, "acme", "scope-image-push"),
+			errors:  1,
+		},
+		{
+			name:    "personal access token scope",
+			orgName: types.StringValue("acme"),
+			entry:   entry(hubclient.OrgAccessTokenTypeOrg, "acme", "repo:write"),
+			errors:  1,
+		},
+		{
+			name:     "unknown scope",
+			orgName:  types.StringValue("acme"),
+			entry:    entry(hubclient.OrgAccessTokenTypeOrg, "acme", "scope-something-new"),
+			warnings: 1,
+		},
+		{
+			name:    "unknown scopes",
+			orgName: types.StringValue("acme"),
+			entry: OrgAccessTokenResourceEntryModel{
+				Type:   types.StringValue(hubclient.OrgAccessTokenTypeRepo),
+				Path:   types.StringUnknown(),
+				Scopes: types.ListUnknown(types.StringType),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := validateOrgAccessTokenResources(context.Background(), tt.orgName, []OrgAccessTokenResourceEntryModel{tt.entry})
+			if got := diags.ErrorsCount(); got != tt.errors {
+				t.Errorf("got %d errors, want %d: %v", got, tt.errors, diags)
+			}
+			if got := diags.WarningsCount(); got != tt.warnings {
+				t.Errorf("got %d warnings, want %d: %v", got, tt.warnings, diags)
+			}
+		})
+	}
+}
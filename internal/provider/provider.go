@@ -18,15 +18,25 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"regexp"
+	"strings"
+	"time"
 
 	"github.com/docker/terraform-provider-docker/internal/auth"
 	"github.com/docker/terraform-provider-docker/internal/hubclient"
 	"github.com/docker/terraform-provider-docker/internal/hubhttp"
+	"github.com/docker/terraform-provider-docker/internal/registryclient"
+	"github.com/docker/terraform-provider-docker/internal/totp"
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
 	"github.com/hashicorp/terraform-plugin-framework/function"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
@@ -37,7 +47,9 @@ import (
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
-var hostRegexp = regexp.MustCompile(`^[a-zA-Z0-9:.-]+$`)
+// hostRegexp matches a host name, optionally with a port and an http:// or
+// https:// scheme.
+var hostRegexp = regexp.MustCompile(`^(https?://)?[a-zA-Z0-9:.-]+$`)
 
 const (
 	dockerHubConfigfileKey      = "https://index.docker.io/v1/"
@@ -46,10 +58,30 @@ const (
 	dockerHubStageHost          = "hub-stage.docker.com"
 )
 
+// Sources of Docker Hub credentials that auth_sources can list.
+const (
+	// authSourceCredentials is the username and password of the provider.
+	authSourceCredentials = "credentials"
+	// authSourceOIDC is the OIDC ID token of the workload, exchanged as
+	// configured by the oidc block.
+	authSourceOIDC = "oidc"
+	// authSourceAccessToken is the access token Docker Desktop stores in
+	// the credential store.
+	authSourceAccessToken = "access_token"
+	// authSourcePullCredentials is the username and password or token
+	// stored by docker login.
+	authSourcePullCredentials = "pull_credentials"
+)
+
+// defaultAuthSources is the order credentials are looked up in when
+// auth_sources is not set.
+var defaultAuthSources = []string{authSourceCredentials, authSourceOIDC, authSourceAccessToken, authSourcePullCredentials}
+
 // Ensure DockerProvider satisfies various provider interfaces.
 var (
-	_ provider.Provider              = &DockerProvider{}
-	_ provider.ProviderWithFunctions = &DockerProvider{}
+	_ provider.Provider                       = &DockerProvider{}
+	_ provider.ProviderWithFunctions          = &DockerProvider{}
+	_ provider.ProviderWithEphemeralResources = &DockerProvider{}
 )
 
 // DockerProvider defines the provider implementation.
@@ -60,12 +92,49 @@ type DockerProvider struct {
 	version string
 }
 
+// ProviderData is handed to resources and data sources when they are
+// configured.
+type ProviderData struct {
+	// Hub is the client for the Docker Hub API.
+	Hub *hubclient.Client
+	// Registry is the client for the distribution API of registries.
+	Registry *registryclient.Client
+}
+
 // DockerProviderModel describes the provider data model.
 type DockerProviderModel struct {
-	Username       types.String `tfsdk:"username"`
-	Password       types.String `tfsdk:"password"`
-	Host           types.String `tfsdk:"host"`
-	MaxPageResults types.Int64  `tfsdk:"max_page_results"`
+	Username           types.String      `tfsdk:"username"`
+	Password           types.String      `tfsdk:"password"`
+	PasswordFile       types.String      `tfsdk:"password_file"`
+	PasswordCommand    []types.String    `tfsdk:"password_command"`
+	TOTPSecret         types.String      `tfsdk:"totp_secret"`
+	OTPCode            types.String      `tfsdk:"otp_code"`
+	Host               types.String      `tfsdk:"host"`
+	MaxPageResults     types.Int64       `tfsdk:"max_page_results"`
+	RequestsPerSecond  types.Float64     `tfsdk:"requests_per_second"`
+	TokenRefreshMargin types.String      `tfsdk:"token_refresh_margin"`
+	ConfigFile         types.String      `tfsdk:"config_file"`
+	CredentialHelper   types.String      `tfsdk:"credential_helper"`
+	AuthSources        []types.String    `tfsdk:"auth_sources"`
+	Retry              *DockerRetryModel `tfsdk:"retry"`
+	OIDC               *DockerOIDCModel  `tfsdk:"oidc"`
+}
+
+// DockerOIDCModel describes the oidc block of the provider.
+type DockerOIDCModel struct {
+	TokenURL     types.String `tfsdk:"token_url"`
+	Audience     types.String `tfsdk:"audience"`
+	TokenFile    types.String `tfsdk:"token_file"`
+	TokenEnv     types.String `tfsdk:"token_env"`
+	RequestURL   types.String `tfsdk:"request_url"`
+	RequestToken types.String `tfsdk:"request_token"`
+}
+
+// DockerRetryModel describes the retry block of the provider.
+type DockerRetryModel struct {
+	MaxAttempts types.Int64  `tfsdk:"max_attempts"`
+	MinBackoff  types.String `tfsdk:"min_backoff"`
+	MaxBackoff  types.String `tfsdk:"max_backoff"`
 }
 
 func (p *DockerProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -159,6 +228,52 @@ export DOCKER_PASSWORD=my-secret-token
 terraform plan ...
 ` + "```" + `
 
+To keep the secret out of the environment of child processes, set
+` + "`DOCKER_PASSWORD_FILE`" + ` to a file containing it instead:
+
+` + "```" + `
+export DOCKER_USERNAME=my-username
+export DOCKER_PASSWORD_FILE=/run/secrets/docker-token
+terraform plan ...
+` + "```" + `
+
+### Setting credentials with a file or a command
+
+The password can also be read from a file, or printed by a command such as a
+secret manager CLI. Both are read again whenever the Docker Hub token expires,
+so secrets rotated during a long run are picked up:
+
+` + "```" + `hcl
+provider "docker" {
+  username         = "my-username"
+  password_command = ["vault", "kv", "get", "-field=token", "secret/docker"]
+}
+` + "```" + `
+
+The command runs without a shell and is killed after 30 seconds. Surrounding
+whitespace is trimmed from its output.
+
+### Two-factor authentication
+
+Accounts with two-factor authentication can log in with their password and the
+TOTP secret of their authenticator app, from which the provider computes a code
+whenever it logs in:
+
+` + "```" + `hcl
+variable "docker_totp_secret" {
+  type      = string
+  sensitive = true
+}
+
+provider "docker" {
+  username    = "break-glass-admin"
+  totp_secret = var.docker_totp_secret
+}
+` + "```" + `
+
+Personal access tokens and organization access tokens do not need a second
+factor.
+
 ### Setting credentials in Terraform (NOT RECOMMENDED)
 
 > [!WARNING]
@@ -175,6 +290,50 @@ provider "docker" {
 }
 ` + "```" + `
 
+### Choosing where credentials are read from
+
+By default, the provider uses the first credentials it finds among:
+
+1. ` + "`credentials`" + `: the ` + "`username`" + ` and ` + "`password`" + ` of the provider, or ` + "`DOCKER_USERNAME`" + ` and ` + "`DOCKER_PASSWORD`" + `.
+2. ` + "`oidc`" + `: the OIDC ID token of the CI job, when the ` + "`oidc`" + ` block is set.
+3. ` + "`access_token`" + `: the access token Docker Desktop keeps in the credential store.
+4. ` + "`pull_credentials`" + `: the credentials stored by ` + "`docker login`" + `.
+
+Set ` + "`auth_sources`" + ` to change the order or leave sources out. The Docker
+config file and its credential store can be chosen explicitly, for example on
+build agents running as a different user than the one that ran ` + "`docker login`" + `:
+
+` + "```" + `hcl
+provider "docker" {
+  config_file       = "/etc/ci/docker/config.json"
+  credential_helper = "pass"
+  auth_sources      = ["pull_credentials"]
+}
+` + "```" + `
+
+### Setting credentials with OIDC workload identity
+
+CI systems such as GitHub Actions and GitLab CI issue OIDC ID tokens to their
+jobs. Instead of storing a long-lived token in ` + "`DOCKER_PASSWORD`" + `, the
+provider can exchange the ID token of the job for a short-lived Docker Hub
+token at an OAuth 2.0 token exchange (RFC 8693) endpoint, and exchange it again
+when it expires:
+
+` + "```" + `hcl
+provider "docker" {
+  username = "my-org"
+
+  oidc {
+    token_url = "https://sts.example.com/token"
+    audience  = "docker-hub"
+  }
+}
+` + "```" + `
+
+In GitHub Actions jobs with the ` + "`id-token: write`" + ` permission, the ID
+token is requested from the Actions runtime. In other CI systems, set
+` + "`token_file`" + ` or ` + "`token_env`" + ` to where the job finds its ID token.
+
 ### Pagination Limits
 
 You can control the number of pages fetched when retrieving paginated data:
@@ -187,6 +346,28 @@ provider "docker" {
 
 Setting ` + "`max_page_results`" + ` to 0 disables pagination limits and fetches all available data.
 
+### Rate Limits and Retries
+
+Requests that are rate limited (HTTP 429) or fail with a server error are
+retried with exponential backoff. When Docker Hub sends a ` + "`Retry-After`" + ` or
+` + "`X-RateLimit-Reset`" + ` header, the provider waits as long as Docker Hub asks, up
+to ` + "`max_backoff`" + `. If Docker Hub asks for a longer wait, the request fails
+with the rate limit error instead of being retried.
+
+For large organizations, you can also throttle requests on the client side:
+
+` + "```" + `hcl
+provider "docker" {
+  requests_per_second = 5
+
+  retry {
+    max_attempts = 8
+    min_backoff  = "2s"
+    max_backoff  = "1m"
+  }
+}
+` + "```" + `
+
 ### Credential types
 
 You can create a personal access token (PAT) to use as an alternative to your
@@ -213,7 +394,7 @@ the required permissions for the target API, such as ` + "`Member Read`" + `,
 ` + "`Group Read`" + `, and ` + "`Group Edit`" + `.
 
 When the provider auto-resolves credentials from Docker's config file, it
-currently prefers Docker Desktop's cached access token before ` + "`docker login`" + `
+prefers Docker Desktop's cached access token before ` + "`docker login`" + `
 pull credentials. If you want to force OAT usage, set ` + "`DOCKER_USERNAME`" + ` and
 ` + "`DOCKER_PASSWORD`" + ` explicitly or configure them in the provider block.
 
@@ -223,7 +404,7 @@ features.
 `,
 		Attributes: map[string]schema.Attribute{
 			"host": schema.StringAttribute{
-				MarkdownDescription: "Docker Hub API Host. Default is `hub.docker.com`.",
+				MarkdownDescription: "Docker Hub API Host. Default is `hub.docker.com`. The host may include an `http://` or `https://` scheme, for example to point the provider at a local test server. Without a scheme, `https` is used.",
 				Optional:            true,
 				Validators: []validator.String{
 					stringvalidator.RegexMatches(hostRegexp, "Must be a valid host"),
@@ -237,11 +418,141 @@ features.
 				MarkdownDescription: "Password, PAT, or OAT for authentication",
 				Optional:            true,
 				Sensitive:           true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("password_file"), path.MatchRoot("password_command")),
+				},
+			},
+			"password_file": schema.StringAttribute{
+				MarkdownDescription: "Path of a file containing the password, PAT, or OAT for authentication. Can also be set with the `DOCKER_PASSWORD_FILE` environment variable.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("password_command")),
+				},
+			},
+			"password_command": schema.ListAttribute{
+				MarkdownDescription: "Command printing the password, PAT, or OAT for authentication, as a list of the program and its arguments. It is run again whenever the Docker Hub token expires.",
+				ElementType:         types.StringType,
+				Optional:            true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+			},
+			"totp_secret": schema.StringAttribute{
+				MarkdownDescription: "Base32 TOTP secret of an account with two-factor authentication, as shown when setting up an authenticator app. A code is computed from it whenever the provider logs in with the password.",
+				Optional:            true,
+				Sensitive:           true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("otp_code")),
+				},
+			},
+			"otp_code": schema.StringAttribute{
+				MarkdownDescription: "One-time code of an account with two-factor authentication. As a code can only be used once, logging in again when the Docker Hub token expires fails. Prefer `totp_secret` for long runs.",
+				Optional:            true,
+				Sensitive:           true,
 			},
 			"max_page_results": schema.Int64Attribute{
 				MarkdownDescription: "Maximum number of pages to fetch when retrieving paginated data. Default is 50. Set to 0 for unlimited pages.",
 				Optional:            true,
 			},
+			"requests_per_second": schema.Float64Attribute{
+				MarkdownDescription: "Maximum number of requests per second sent to the Docker Hub API. Default is 0, which means unlimited.",
+				Optional:            true,
+				Validators: []validator.Float64{
+					float64validator.AtLeast(0),
+				},
+			},
+			"token_refresh_margin": schema.StringAttribute{
+				MarkdownDescription: fmt.Sprintf("How long before its expiry the Docker Hub token is refreshed, as a duration such as `30s` or `5m`. Default is `%s`. Tokens living for less than twice the margin are refreshed halfway through their lifetime.", auth.DefaultRefreshMargin),
+				Optional:            true,
+				Validators: []validator.String{
+					durationValidator,
+				},
+			},
+			"config_file": schema.StringAttribute{
+				MarkdownDescription: "Path of the Docker config file to read credentials from. Default is `config.json` in the `DOCKER_CONFIG` directory, or `~/.docker/config.json`.",
+				Optional:            true,
+			},
+			"credential_helper": schema.StringAttribute{
+				MarkdownDescription: "Credential helper to read credentials with, such as `pass`, `ecr-login` or `docker-credential-pass`, instead of the `credsStore` and `credHelpers` of the Docker config file. The `docker-credential-<name>` program must be in the `PATH`.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"auth_sources": schema.ListAttribute{
+				MarkdownDescription: fmt.Sprintf("Sources of Docker Hub credentials, in the order they are tried. Valid values are `%s`, `%s`, `%s` and `%s`. Default is `%s`.", authSourceCredentials, authSourceOIDC, authSourceAccessToken, authSourcePullCredentials, `["`+strings.Join(defaultAuthSources, `", "`)+`"]`),
+				ElementType:         types.StringType,
+				Optional:            true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+					listvalidator.UniqueValues(),
+					listvalidator.ValueStringsAre(stringvalidator.OneOf(defaultAuthSources...)),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"retry": schema.SingleNestedBlock{
+				MarkdownDescription: "Retry behavior for rate limited and failed requests",
+				Attributes: map[string]schema.Attribute{
+					"max_attempts": schema.Int64Attribute{
+						MarkdownDescription: fmt.Sprintf("Maximum number of attempts for a request, including the first one. Default is %d.", hubclient.DefaultRetryMaxAttempts),
+						Optional:            true,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(1),
+						},
+					},
+					"min_backoff": schema.StringAttribute{
+						MarkdownDescription: fmt.Sprintf("Minimum time to wait between attempts, as a duration such as `500ms` or `2s`. Default is `%s`.", hubclient.DefaultRetryMinBackoff),
+						Optional:            true,
+						Validators: []validator.String{
+							durationValidator,
+						},
+					},
+					"max_backoff": schema.StringAttribute{
+						MarkdownDescription: fmt.Sprintf("Maximum time to wait between attempts, as a duration such as `30s` or `1m`. Default is `%s`. Docker Hub asking for a longer wait through the `Retry-After` or `X-RateLimit-Reset` headers fails the request instead of retrying it.", hubclient.DefaultRetryMaxBackoff),
+						Optional:            true,
+						Validators: []validator.String{
+							durationValidator,
+						},
+					},
+				},
+			},
+			"oidc": schema.SingleNestedBlock{
+				MarkdownDescription: "Exchange the OIDC ID token of the workload, such as a CI job, for a short-lived Docker Hub token. The ID token is read from `token_file`, `token_env` or `request_url`, defaulting to the ID token endpoint of GitHub Actions.",
+				Attributes: map[string]schema.Attribute{
+					"token_url": schema.StringAttribute{
+						MarkdownDescription: "URL of the OAuth 2.0 token exchange endpoint to exchange the ID token at. Required when the block is set.",
+						Optional:            true,
+					},
+					"audience": schema.StringAttribute{
+						MarkdownDescription: "Audience of the ID token requested from `request_url`, also sent to the token exchange endpoint",
+						Optional:            true,
+					},
+					"token_file": schema.StringAttribute{
+						MarkdownDescription: "Path of a file containing the ID token, read again for every exchange",
+						Optional:            true,
+						Validators: []validator.String{
+							stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("token_env"), path.MatchRelative().AtParent().AtName("request_url")),
+						},
+					},
+					"token_env": schema.StringAttribute{
+						MarkdownDescription: "Name of an environment variable containing the ID token, such as a GitLab CI `id_tokens` variable",
+						Optional:            true,
+						Validators: []validator.String{
+							stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("request_url")),
+						},
+					},
+					"request_url": schema.StringAttribute{
+						MarkdownDescription: fmt.Sprintf("URL returning the ID token in the `value` field of a JSON object. Default is `%s` when neither `token_file` nor `token_env` is set.", auth.GitHubActionsRequestURLEnv),
+						Optional:            true,
+					},
+					"request_token": schema.StringAttribute{
+						MarkdownDescription: fmt.Sprintf("Bearer token to request the ID token from `request_url` with. Default is `%s`.", auth.GitHubActionsRequestTokenEnv),
+						Optional:            true,
+						Sensitive:           true,
+					},
+				},
+			},
 		},
 	}
 }
@@ -277,6 +588,30 @@ func (p *DockerProvider) Configure(ctx context.Context, req provider.ConfigureRe
 			"The provider cannot create the Docker Hub API client as there is an unknown configuration value for the Docker Hub API password.",
 		)
 	}
+	if data.PasswordFile.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("password_file"),
+			"Unknown Docker Hub API Password File",
+			"The provider cannot create the Docker Hub API client as there is an unknown configuration value for the Docker Hub API password file. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the DOCKER_PASSWORD_FILE environment variable.",
+		)
+	}
+
+	if data.ConfigFile.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("config_file"),
+			"Unknown Docker Config File",
+			"The provider cannot read credentials as there is an unknown configuration value for the Docker config file. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the DOCKER_CONFIG environment variable.",
+		)
+	}
+	if data.CredentialHelper.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("credential_helper"),
+			"Unknown Credential Helper",
+			"The provider cannot read credentials as there is an unknown configuration value for the credential helper.",
+		)
+	}
 
 	if resp.Diagnostics.HasError() {
 		return
@@ -297,9 +632,24 @@ func (p *DockerProvider) Configure(ctx context.Context, req provider.ConfigureRe
 		username = data.Username.ValueString()
 	}
 
-	password := os.Getenv("DOCKER_PASSWORD")
-	if !data.Password.IsNull() {
-		password = data.Password.ValueString()
+	// Configuration values take precedence over the environment, and files
+	// over the plain DOCKER_PASSWORD.
+	var password auth.PasswordSource
+	switch {
+	case data.Password.ValueString() != "":
+		password = auth.StaticPassword(data.Password.ValueString())
+	case data.PasswordFile.ValueString() != "":
+		password = auth.PasswordFile(data.PasswordFile.ValueString())
+	case len(data.PasswordCommand) > 0:
+		args := make([]string, 0, len(data.PasswordCommand))
+		for _, arg := range data.PasswordCommand {
+			args = append(args, arg.ValueString())
+		}
+		password = auth.PasswordCommand(args, auth.DefaultPasswordCommandTimeout)
+	case os.Getenv("DOCKER_PASSWORD_FILE") != "":
+		password = auth.PasswordFile(os.Getenv("DOCKER_PASSWORD_FILE"))
+	case os.Getenv("DOCKER_PASSWORD") != "":
+		password = auth.StaticPassword(os.Getenv("DOCKER_PASSWORD"))
 	}
 
 	maxPageResults := int64(50) // Default value
@@ -310,37 +660,100 @@ func (p *DockerProvider) Configure(ctx context.Context, req provider.ConfigureRe
 		})
 	}
 
+	requestsPerSecond := float64(0)
+	if !data.RequestsPerSecond.IsNull() {
+		requestsPerSecond = data.RequestsPerSecond.ValueFloat64()
+	}
+
+	refreshMargin := auth.DefaultRefreshMargin
+	if !data.TokenRefreshMargin.IsNull() {
+		// The duration has already been checked by the schema validator.
+		refreshMargin, _ = time.ParseDuration(data.TokenRefreshMargin.ValueString())
+	}
+
+	var retry hubclient.RetryConfig
+	if data.Retry != nil {
+		retry.MaxAttempts = int(data.Retry.MaxAttempts.ValueInt64())
+		// Durations have already been checked by the schema validators.
+		retry.MinBackoff, _ = time.ParseDuration(data.Retry.MinBackoff.ValueString())
+		retry.MaxBackoff, _ = time.ParseDuration(data.Retry.MaxBackoff.ValueString())
+	}
+
+	authSources := defaultAuthSources
+	if data.AuthSources != nil {
+		authSources = make([]string, 0, len(data.AuthSources))
+		for _, source := range data.AuthSources {
+			authSources = append(authSources, source.ValueString())
+		}
+	}
+
+	var otp auth.OTPSource
+	switch {
+	case data.TOTPSecret.ValueString() != "":
+		if _, err := totp.ParseSecret(data.TOTPSecret.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("totp_secret"), "Invalid TOTP Secret", err.Error())
+			return
+		}
+		otp = auth.TOTP(data.TOTPSecret.ValueString())
+	case data.OTPCode.ValueString() != "":
+		otp = auth.StaticOTP(data.OTPCode.ValueString())
+	}
+
+	var oidcConfig *auth.OIDCConfig
+	if data.OIDC != nil {
+		if data.OIDC.TokenURL.ValueString() == "" {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("oidc").AtName("token_url"),
+				"Missing OIDC Token URL",
+				"The provider cannot exchange OIDC ID tokens without the URL of the token exchange endpoint.",
+			)
+			return
+		}
+		oidcConfig = newOIDCConfig(data.OIDC)
+	}
+
 	// Create a shared transport with user agent
 	sharedTransport := hubhttp.NewUserAgentTransport(p.version)
+	configStore, err := auth.LoadConfigStore(auth.ConfigStoreOptions{
+		ConfigFile:       data.ConfigFile.ValueString(),
+		CredentialHelper: data.CredentialHelper.ValueString(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("config_file"),
+			"Docker Config File Error",
+			fmt.Sprintf("Failed to read the Docker config file: %v", err),
+		)
+		return
+	}
+	tflog.Debug(ctx, "Using Docker config file", map[string]interface{}{
+		"configFile": configStore.ConfigFile(),
+	})
+	if err := configStore.LoadError(); err != nil {
+		tflog.Warn(ctx, "Failed to load the Docker config file", map[string]interface{}{
+			"configFile": configStore.ConfigFile(),
+			"error":      err.Error(),
+		})
+	}
 
 	// Determine the authentication method
-	var tokenProvider hubclient.TokenProvider
-	var err error
-	baseURL := fmt.Sprintf("https://%s/v2", host)
-
-	// If username and password are provided, use login auth
-	if username != "" && password != "" {
-		tflog.Info(ctx, "Using login authentication from configuration")
-		tokenProvider = auth.NewLoginTokenProvider(username, password, baseURL, sharedTransport)
-	} else {
-		// Try credential store - prefer access tokens, fallback to pull credentials
-		configfileKey := getConfigfileKey(host)
-		configStore := auth.NewConfigStore()
-
-		// First try to use access tokens if available
-		tokenProvider, err = auth.NewAccessTokenProviderFromStore(configStore, configfileKey)
-		if err != nil {
-			// Fallback to pull credentials (username/password or PAT)
-			tokenProvider, err = auth.NewLoginTokenProviderFromStore(configStore, configfileKey, baseURL, sharedTransport)
-			if err != nil {
-				resp.Diagnostics.AddError("Credential Store Error",
-					fmt.Sprintf("Failed to retrieve valid credentials from the Docker config file: %v", err))
-			} else {
-				tflog.Info(ctx, "Using login authentication from credential store")
-			}
-		} else {
-			tflog.Info(ctx, "Using access token authentication from credential store")
-		}
+	baseURL := hubBaseURL(host)
+	tokenProvider, err := newTokenProvider(ctx, authSources, tokenProviderConfig{
+		username:      username,
+		password:      password,
+		otp:           otp,
+		baseURL:       baseURL,
+		configfileKey: getConfigfileKey(host),
+		configStore:   configStore,
+		oidc:          oidcConfig,
+		refreshMargin: refreshMargin,
+		transport:     sharedTransport,
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Authentication Error",
+			fmt.Sprintf("No authentication method available. Please provide username/password or ensure Docker credentials are available in the credential store: %v", err),
+		)
 	}
 
 	// If any of the expected configurations are missing, return
@@ -357,15 +770,7 @@ func (p *DockerProvider) Configure(ctx context.Context, req provider.ConfigureRe
 		resp.Diagnostics.AddAttributeError(
 			path.Root("host"),
 			"Invalid Docker Hub API Host",
-			"DOCKER_HUB_HOST must be a valid host (of the form 'hub.docker.com').")
-	}
-
-	// Verify we have a token provider
-	if tokenProvider == nil {
-		resp.Diagnostics.AddError(
-			"Authentication Error",
-			"No authentication method available. Please provide username/password or ensure Docker credentials are available in the credential store.",
-		)
+			"DOCKER_HUB_HOST must be a valid host (of the form 'hub.docker.com' or 'http://localhost:8080').")
 	}
 
 	if resp.Diagnostics.HasError() {
@@ -376,18 +781,124 @@ func (p *DockerProvider) Configure(ctx context.Context, req provider.ConfigureRe
 
 	tflog.Debug(ctx, "Creating Docker Hub client")
 
-	client := hubclient.NewClient(hubclient.Config{
-		BaseURL:        baseURL,
-		TokenProvider:  tokenProvider,
-		Transport:      sharedTransport,
-		MaxPageResults: maxPageResults,
-	})
+	providerData := &ProviderData{
+		Hub: hubclient.NewClient(hubclient.Config{
+			BaseURL:        baseURL,
+			TokenProvider:  tokenProvider,
+			Transport:      sharedTransport,
+			MaxPageResults: maxPageResults,
+			Retry:          retry,
+
+			RequestsPerSecond: requestsPerSecond,
+		}),
+		Registry: registryclient.NewClient(registryclient.Config{
+			Transport:   sharedTransport,
+			Credentials: registryCredentials(host, tokenProvider, configStore),
+		}),
+	}
+
+	resp.DataSourceData = providerData
+	resp.EphemeralResourceData = providerData
+	resp.ResourceData = providerData
+}
+
+// tokenProviderConfig is what newTokenProvider authenticates with.
+type tokenProviderConfig struct {
+	username      string
+	password      auth.PasswordSource
+	otp           auth.OTPSource
+	baseURL       string
+	configfileKey string
+	configStore   *auth.ConfigStore
+	oidc          *auth.OIDCConfig
+	refreshMargin time.Duration
+	transport     http.RoundTripper
+}
+
+// newTokenProvider returns a token provider for the first of the auth
+// sources that has credentials.
+func newTokenProvider(ctx context.Context, sources []string, config tokenProviderConfig) (hubclient.TokenProvider, error) {
+	var errs []string
+	for _, source := range sources {
+		switch source {
+		case authSourceCredentials:
+			if config.username == "" || config.password == nil {
+				errs = append(errs, fmt.Sprintf("%s: no username and password configured", source))
+				continue
+			}
+			tflog.Info(ctx, "Using login authentication from configuration")
+			tokenProvider := auth.NewLoginTokenProvider(config.username, config.password, config.baseURL, config.transport)
+			tokenProvider.SetRefreshMargin(config.refreshMargin)
+			if config.otp != nil {
+				tokenProvider.SetOTPSource(config.otp)
+			}
+			return tokenProvider, nil
+		case authSourceOIDC:
+			if config.oidc == nil {
+				errs = append(errs, fmt.Sprintf("%s: no oidc block configured", source))
+				continue
+			}
+			tflog.Info(ctx, "Using OIDC token exchange authentication")
+			tokenProvider := auth.NewOIDCTokenProvider(*config.oidc, config.username, config.transport)
+			tokenProvider.SetRefreshMargin(config.refreshMargin)
+			return tokenProvider, nil
+		case authSourceAccessToken:
+			tokenProvider, err := auth.NewAccessTokenProviderFromStore(config.configStore, config.configfileKey)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %v", source, err))
+				continue
+			}
+			tflog.Info(ctx, "Using access token authentication from credential store")
+			tokenProvider.SetRefreshMargin(config.refreshMargin)
+			return tokenProvider, nil
+		case authSourcePullCredentials:
+			tokenProvider, err := auth.NewLoginTokenProviderFromStore(config.configStore, config.configfileKey, config.baseURL, config.transport)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %v", source, err))
+				continue
+			}
+			tflog.Info(ctx, "Using login authentication from credential store")
+			tokenProvider.SetRefreshMargin(config.refreshMargin)
+			return tokenProvider, nil
+		default:
+			errs = append(errs, fmt.Sprintf("%s: unknown auth source", source))
+		}
+	}
+	return nil, errors.New(strings.Join(errs, "; "))
+}
+
+// newOIDCConfig returns the OIDC configuration of the oidc block. Without an
+// ID token source, the ID token is requested like GitHub Actions jobs do.
+func newOIDCConfig(data *DockerOIDCModel) *auth.OIDCConfig {
+	config := &auth.OIDCConfig{
+		TokenURL:     data.TokenURL.ValueString(),
+		Audience:     data.Audience.ValueString(),
+		TokenFile:    data.TokenFile.ValueString(),
+		TokenEnv:     data.TokenEnv.ValueString(),
+		RequestURL:   data.RequestURL.ValueString(),
+		RequestToken: data.RequestToken.ValueString(),
+	}
+	if config.TokenFile == "" && config.TokenEnv == "" && config.RequestURL == "" {
+		config.RequestURL = os.Getenv(auth.GitHubActionsRequestURLEnv)
+	}
+	if config.RequestURL != "" && config.RequestToken == "" {
+		config.RequestToken = os.Getenv(auth.GitHubActionsRequestTokenEnv)
+	}
+	return config
+}
 
-	resp.DataSourceData = client
-	resp.ResourceData = client
+// hubBaseURL returns the URL of the v2 API for the given host. Hosts without
+// a scheme use https.
+func hubBaseURL(host string) string {
+	if strings.HasPrefix(host, "http://") || strings.HasPrefix(host, "https://") {
+		return host + "/v2"
+	}
+	return fmt.Sprintf("https://%s/v2", host)
 }
 
 func getConfigfileKey(host string) string {
+	host = strings.TrimPrefix(strings.TrimPrefix(host, "https://"), "http://")
+
 	// The Docker Hub host is a special case that stores its credentials differently in the store.
 	configfileKey := host
 	switch host {
@@ -399,6 +910,37 @@ func getConfigfileKey(host string) string {
 	return configfileKey
 }
 
+// registryCredentials looks up the credentials for a registry in the Docker
+// config file. When the provider manages Docker Hub, the Docker Hub registry
+// is accessed as the same user as the API, whichever auth source it came
+// from.
+func registryCredentials(host string, tokenProvider hubclient.TokenProvider, configStore *auth.ConfigStore) registryclient.CredentialsFunc {
+	return func(ctx context.Context, registryHost string) (string, string, error) {
+		if registryHost == registryclient.DockerHubRegistry {
+			if host == dockerHubHost {
+				token, err := tokenProvider.EnsureToken(ctx)
+				if err != nil {
+					return "", "", err
+				}
+				// Some token providers only know the user once they have
+				// a token.
+				return tokenProvider.Username(), token, nil
+			}
+			return configStore.GetCredentialStorePullTokens(dockerHubConfigfileKey)
+		}
+		return configStore.GetCredentialStorePullTokens(getConfigfileKey(registryHost))
+	}
+}
+
+// listingError describes an error from a listing that has to be complete,
+// with a way out when it is longer than max_page_results allows.
+func listingError(err error) string {
+	if hubclient.IsTruncated(err) {
+		return fmt.Sprintf("%v. Raise max_page_results in the provider configuration, or set it to 0 to fetch every page.", err)
+	}
+	return err.Error()
+}
+
 func (p *DockerProvider) Resources(ctx context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewAccessTokenResource,
@@ -407,8 +949,14 @@ func (p *DockerProvider) Resources(ctx context.Context) []func() resource.Resour
 		NewOrgSettingRegistryAccessManagementResource,
 		NewOrgTeamResource,
 		NewOrgTeamMemberResource,
+		NewOrgTeamMembersResource,
 		NewRepositoryResource,
+		NewRepositoryTagRetentionResource,
 		NewRepositoryTeamPermissionResource,
+		NewRepositoryTeamPermissionsResource,
+		NewRepositoryCollaboratorResource,
+		NewRepositoryWebhookResource,
+		NewRegistryTagResource,
 		NewOrgMemberResource,
 	}
 }
@@ -417,10 +965,15 @@ func (p *DockerProvider) DataSources(ctx context.Context) []func() datasource.Da
 	return []func() datasource.DataSource{
 		NewOrgDataSource,
 		NewOrgMembersDataSource,
+		NewOrgAccessTokensDataSource,
 		NewOrgTeamMemberDataSource,
 		NewRepositoryDataSource,
 		NewRepositoriesDataSource,
 		NewRepositoryTagsDataSource,
+		NewRepositoryLatestTagDataSource,
+		NewRegistryImageManifestDataSource,
+		NewPullRateLimitDataSource,
+		NewRepositoryWebhooksDataSource,
 		NewAccessTokenDataSource,
 		NewAccessTokensDataSource,
 		NewOrgTeamDataSource,
@@ -428,8 +981,20 @@ func (p *DockerProvider) DataSources(ctx context.Context) []func() datasource.Da
 	}
 }
 
+func (p *DockerProvider) EphemeralResources(ctx context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		NewAccessTokenEphemeralResource,
+		NewOrgAccessTokenEphemeralResource,
+	}
+}
+
 func (p *DockerProvider) Functions(ctx context.Context) []func() function.Function {
-	return []func() function.Function{}
+	return []func() function.Function{
+		NewImmutableTagMatchesFunction,
+		NewParseImageRefFunction,
+		NewRepositoryIDFunction,
+		NewSplitRepositoryIDFunction,
+	}
 }
 
 func New(version string) func() provider.Provider {
@@ -17,10 +17,24 @@
 package provider
 
 import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/docker/terraform-provider-docker/internal/auth"
+	"github.com/docker/terraform-provider-docker/internal/envvar"
+	"github.com/docker/terraform-provider-docker/internal/hubclient"
+	"github.com/docker/terraform-provider-docker/internal/hubtest"
+	"github.com/docker/terraform-provider-docker/internal/registryclient"
+	"github.com/docker/terraform-provider-docker/internal/registrytest"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 )
 
@@ -32,6 +46,20 @@ var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServe
 	"docker": providerserver.NewProtocol6WithError(New("test")()),
 }
 
+// testAccHubClient returns a client for the Docker Hub the acceptance tests
+// run against, to change things outside of Terraform.
+func testAccHubClient() *hubclient.Client {
+	host := os.Getenv("DOCKER_HUB_HOST")
+	if host == "" {
+		host = dockerHubHost
+	}
+	baseURL := hubBaseURL(host)
+	return hubclient.NewClient(hubclient.Config{
+		BaseURL:       baseURL,
+		TokenProvider: auth.NewLoginTokenProvider(os.Getenv("DOCKER_USERNAME"), auth.StaticPassword(os.Getenv("DOCKER_PASSWORD")), baseURL, http.DefaultTransport),
+	})
+}
+
 func testAccPreCheck(t *testing.T) {
 	// You can add code here to run prior to any test case execution, for example assertions
 	// about the appropriate environment variables being set are common to see in a pre-check
@@ -40,3 +68,228 @@ func testAccPreCheck(t *testing.T) {
 		t.Fatal("DOCKER_USERNAME must be set for acceptance tests")
 	}
 }
+
+func testAccSkipFakeHub(t *testing.T, reason string) {
+	if os.Getenv(envvar.AccTestFakeHub) != "" {
+		t.Skipf("Skipping against the fake Docker Hub: %s", reason)
+	}
+}
+
+func TestMain(m *testing.M) {
+	os.Exit(runTests(m))
+}
+
+func runTests(m *testing.M) int {
+	if os.Getenv(envvar.AccTestFakeHub) != "" {
+		server := startFakeHub()
+		defer server.Close()
+		registry, configDir := startFakeRegistry(os.Getenv("DOCKER_USERNAME"), os.Getenv("DOCKER_PASSWORD"))
+		defer registry.Close()
+		defer os.RemoveAll(configDir)
+	}
+	return m.Run()
+}
+
+// startFakeHub starts a fake Docker Hub seeded with the fixtures the
+// acceptance tests expect to find on the real one, and points the provider
+// at it.
+func startFakeHub() *hubtest.Server {
+	server := hubtest.NewServer()
+
+	username := "acctest"
+	password := randString(20)
+	orgName := envvar.GetWithDefault(envvar.AccTestOrganization)
+
+	server.AddUser(username, password, username+"@example.com")
+	server.AddUser("nick20241127", randString(20), "nick.santos+nick20241127@docker.com")
+
+	server.AddOrg(orgName, username)
+	server.AddOrgMember(orgName, "nick20241127", hubclient.OrgRoleParamMember)
+
+	// Large enough to need several pages.
+	server.AddOrg("docker", username)
+	for i := 0; i < 3*hubtest.DefaultPageSize; i++ {
+		member := fmt.Sprintf("member%02d", i)
+		server.AddUser(member, randString(20), member+"@example.com")
+		server.AddOrgMember("docker", member, hubclient.OrgRoleParamMember)
+	}
+
+	server.AddRepository(hubclient.Repository{Namespace: "library", Name: "hello-world"})
+	server.AddTag("library", "hello-world", hubclient.Tag{Name: "latest"})
+	server.AddRepository(hubclient.Repository{Namespace: "dockerterraform", Name: "docker-terraform-repo-demo"})
+
+	os.Setenv("DOCKER_HUB_HOST", server.URL)
+	os.Setenv("DOCKER_USERNAME", username)
+	os.Setenv("DOCKER_PASSWORD", password)
+	return server
+}
+
+// startFakeRegistry starts a fake registry serving the images the acceptance
+// tests pull from Docker Hub, with the pull rate limit of a free account, and
+// points the tests at it. The credentials of
+// the user are stored in a Docker config file, in a directory the caller
+// should remove when finished.
+func startFakeRegistry(username, password string) (*registrytest.Server, string) {
+	server := registrytest.NewServer()
+	server.AddUser(username, password)
+	server.AddImage("library/hello-world", "latest", registrytest.Image{
+		Platforms: []registryclient.Platform{
+			{OS: "linux", Architecture: "amd64"},
+			{OS: "linux", Architecture: "arm64", Variant: "v8"},
+		},
+	})
+	server.AddImage(registryclient.RateLimitRepository, registryclient.RateLimitTag, registrytest.Image{})
+	server.SetPullRateLimit(200, 6*time.Hour)
+
+	configDir, err := os.MkdirTemp("", "acctest-docker-config")
+	if err != nil {
+		panic(err)
+	}
+	configFile := fmt.Sprintf(`{"auths": {%q: {"auth": %q}}}`, server.Host(), base64.StdEncoding.EncodeToString([]byte(username+":"+password)))
+	if err := os.WriteFile(filepath.Join(configDir, "config.json"), []byte(configFile), 0o600); err != nil {
+		panic(err)
+	}
+
+	os.Setenv("DOCKER_CONFIG", configDir)
+	os.Setenv(envvar.AccTestRegistry, server.Host())
+	return server, configDir
+}
+
+func TestNewTokenProvider(t *testing.T) {
+	ctx := context.Background()
+	configFile := filepath.Join(t.TempDir(), "config.json")
+	auths := fmt.Sprintf(`{"auths": {"hub.example.com": {"auth": %q}}}`, base64.StdEncoding.EncodeToString([]byte("store-user:store-secret")))
+	if err := os.WriteFile(configFile, []byte(auths), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	configStore, err := auth.LoadConfigStore(auth.ConfigStoreOptions{ConfigFile: configFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := tokenProviderConfig{
+		username:      "provider-user",
+		password:      auth.StaticPassword("provider-secret"),
+		baseURL:       "https://hub.example.com/v2",
+		configfileKey: "hub.example.com",
+		configStore:   configStore,
+	}
+
+	tests := []struct {
+		name         string
+		sources      []string
+		noPassword   bool
+		oidc         *auth.OIDCConfig
+		wantUsername string
+		wantErr      string
+	}{
+		{name: "default", sources: defaultAuthSources, wantUsername: "provider-user"},
+		{name: "default without password", sources: defaultAuthSources, noPassword: true, wantUsername: "store-user"},
+		{name: "store first", sources: []string{authSourcePullCredentials, authSourceCredentials}, wantUsername: "store-user"},
+		{name: "oidc", sources: defaultAuthSources, noPassword: true, oidc: &auth.OIDCConfig{TokenURL: "https://sts.example.com/token"}, wantUsername: "provider-user"},
+		{name: "no oidc block", sources: []string{authSourceOIDC}, wantErr: "oidc: no oidc block configured"},
+		{name: "no access token", sources: []string{authSourceAccessToken}, wantErr: "access_token: no valid access token available"},
+		{name: "all fail", sources: []string{authSourceCredentials, authSourceAccessToken}, noPassword: true, wantErr: "credentials: no username and password configured; access_token:"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := config
+			if tt.noPassword {
+				config.password = nil
+			}
+			config.oidc = tt.oidc
+			tokenProvider, err := newTokenProvider(ctx, tt.sources, config)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tokenProvider.Username() != tt.wantUsername {
+				t.Errorf("got username %q, want %q", tokenProvider.Username(), tt.wantUsername)
+			}
+		})
+	}
+}
+
+// staticTokenProvider is a token provider that only knows its user once it
+// handed out a token, like the access token provider.
+type staticTokenProvider struct {
+	username string
+	token    string
+	issued   bool
+}
+
+func (p *staticTokenProvider) EnsureToken(ctx context.Context) (string, error) {
+	p.issued = true
+	return p.token, nil
+}
+
+func (p *staticTokenProvider) Username() string {
+	if !p.issued {
+		return ""
+	}
+	return p.username
+}
+
+func (p *staticTokenProvider) InvalidateToken(token string) {}
+
+func TestRegistryCredentials(t *testing.T) {
+	ctx := context.Background()
+	configFile := filepath.Join(t.TempDir(), "config.json")
+	auths := fmt.Sprintf(`{"auths": {%q: {"auth": %q}, "registry.example.com": {"auth": %q}}}`,
+		dockerHubConfigfileKey,
+		base64.StdEncoding.EncodeToString([]byte("store-user:store-secret")),
+		base64.StdEncoding.EncodeToString([]byte("registry-user:registry-secret")))
+	if err := os.WriteFile(configFile, []byte(auths), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	configStore, err := auth.LoadConfigStore(auth.ConfigStoreOptions{ConfigFile: configFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		host         string
+		registryHost string
+		wantUsername string
+		wantSecret   string
+	}{
+		{name: "docker hub", host: dockerHubHost, registryHost: registryclient.DockerHubRegistry, wantUsername: "api-user", wantSecret: "api-token"},
+		{name: "docker hub from another host", host: "hub.example.com", registryHost: registryclient.DockerHubRegistry, wantUsername: "store-user", wantSecret: "store-secret"},
+		{name: "other registry", host: dockerHubHost, registryHost: "registry.example.com", wantUsername: "registry-user", wantSecret: "registry-secret"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokenProvider := &staticTokenProvider{username: "api-user", token: "api-token"}
+			username, secret, err := registryCredentials(tt.host, tokenProvider, configStore)(ctx, tt.registryHost)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if username != tt.wantUsername || secret != tt.wantSecret {
+				t.Errorf("got %s:%s, want %s:%s", username, secret, tt.wantUsername, tt.wantSecret)
+			}
+		})
+	}
+}
+
+func TestNewOIDCConfig(t *testing.T) {
+	t.Setenv(auth.GitHubActionsRequestURLEnv, "https://actions.example.com/token?api-version=2.0")
+	t.Setenv(auth.GitHubActionsRequestTokenEnv, "request-secret")
+
+	config := newOIDCConfig(&DockerOIDCModel{TokenURL: types.StringValue("https://sts.example.com/token")})
+	if config.RequestURL != "https://actions.example.com/token?api-version=2.0" || config.RequestToken != "request-secret" {
+		t.Errorf("got %+v, want the ID token to be requested from GitHub Actions", config)
+	}
+
+	config = newOIDCConfig(&DockerOIDCModel{
+		TokenURL: types.StringValue("https://sts.example.com/token"),
+		TokenEnv: types.StringValue("CI_JOB_JWT"),
+	})
+	if config.RequestURL != "" || config.RequestToken != "" || config.TokenEnv != "CI_JOB_JWT" {
+		t.Errorf("got %+v, want the ID token to be read from CI_JOB_JWT only", config)
+	}
+}
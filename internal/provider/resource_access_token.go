@@ -19,6 +19,7 @@ package provider
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/docker/terraform-provider-docker/internal/hubclient"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -32,9 +33,11 @@ import (
 )
 
 var (
-	_ resource.Resource                = &AccessTokenResource{}
-	_ resource.ResourceWithConfigure   = &AccessTokenResource{}
-	_ resource.ResourceWithImportState = &AccessTokenResource{}
+	_ resource.Resource                   = &AccessTokenResource{}
+	_ resource.ResourceWithConfigure      = &AccessTokenResource{}
+	_ resource.ResourceWithImportState    = &AccessTokenResource{}
+	_ resource.ResourceWithValidateConfig = &AccessTokenResource{}
+	_ resource.ResourceWithModifyPlan     = &AccessTokenResource{}
 )
 
 func NewAccessTokenResource() resource.Resource {
@@ -46,12 +49,16 @@ type AccessTokenResource struct {
 }
 
 type AccessTokenResourceModel struct {
-	UUID       types.String `tfsdk:"uuid"`
-	IsActive   types.Bool   `tfsdk:"is_active"`
-	TokenLabel types.String `tfsdk:"token_label"`
-	Scopes     types.List   `tfsdk:"scopes"`
-	Token      types.String `tfsdk:"token"`
-	ExpiresAt  types.String `tfsdk:"expires_at"`
+	UUID          types.String              `tfsdk:"uuid"`
+	IsActive      types.Bool                `tfsdk:"is_active"`
+	TokenLabel    types.String              `tfsdk:"token_label"`
+	Scopes        types.List                `tfsdk:"scopes"`
+	Token         types.String              `tfsdk:"token"`
+	ExpiresAt     types.String              `tfsdk:"expires_at"`
+	CreatedAt     types.String              `tfsdk:"created_at"`
+	Rotation      *AccessTokenRotationModel `tfsdk:"rotation"`
+	PreviousUUID  types.String              `tfsdk:"previous_uuid"`
+	PreviousToken types.String              `tfsdk:"previous_token"`
 }
 
 func (r *AccessTokenResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
@@ -59,17 +66,17 @@ func (r *AccessTokenResource) Configure(ctx context.Context, req resource.Config
 		return
 	}
 
-	client, ok := req.ProviderData.(*hubclient.Client)
+	providerData, ok := req.ProviderData.(*ProviderData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *hubclient.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *provider.ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
 
-	r.client = client
+	r.client = providerData.Hub
 }
 
 func (r *AccessTokenResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -87,6 +94,22 @@ resource "docker_access_token" "example" {
 	token_label = "my-pat-token"
 	scopes      = ["repo:read", "repo:write"]
 }
+` + "```" + `
+
+## Rotation
+
+With a ` + "`rotation`" + ` block, the first apply after ` + "`rotate_after`" + ` has passed creates a replacement token with the same label and scopes. During the overlap both the new ` + "`token`" + ` and the replaced ` + "`previous_token`" + ` are valid, and the first apply after the overlap revokes the replaced token.
+
+` + "```hcl" + `
+resource "docker_access_token" "rotated" {
+	token_label = "ci-token"
+	scopes      = ["repo:read"]
+
+	rotation {
+		rotate_after = "720h"
+		overlap      = "24h"
+	}
+}
 ` + "```" + `
 
 	`,
@@ -124,10 +147,62 @@ resource "docker_access_token" "example" {
 					accessTokenExpiresAtValidator,
 				},
 			},
+			"created_at": schema.StringAttribute{
+				MarkdownDescription: "Time the current token was created. The rotation schedule is tracked from this time.",
+				Computed:            true,
+			},
+			"previous_uuid": schema.StringAttribute{
+				MarkdownDescription: "UUID of the token replaced by the last rotation, while it is still valid during the overlap",
+				Computed:            true,
+			},
+			"previous_token": schema.StringAttribute{
+				MarkdownDescription: "The token replaced by the last rotation, while it is still valid during the overlap",
+				Computed:            true,
+				Sensitive:           true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"rotation": accessTokenRotationBlock(),
 		},
 	}
 }
 
+func (r *AccessTokenResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data AccessTokenResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	_, _, diags := expandAccessTokenRotation(data.Rotation)
+	resp.Diagnostics.Append(diags...)
+}
+
+// ModifyPlan plans an update once the token is due for rotation or the token
+// it replaced is due to be revoked.
+func (r *AccessTokenResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		return
+	}
+
+	var plan, state AccessTokenResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	_, rotationPlan, diags := planAccessTokenRotation(plan.Rotation, state.CreatedAt, !state.PreviousUUID.IsNull(), time.Now())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || rotationPlan == accessTokenRotationNone {
+		return
+	}
+
+	for _, name := range append([]string{"uuid", "previous_uuid"}, accessTokenRotationComputedAttributes...) {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root(name), types.StringUnknown())...)
+	}
+}
+
 func (r *AccessTokenResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var data AccessTokenResourceModel
 
@@ -143,22 +218,15 @@ func (r *AccessTokenResource) Create(ctx context.Context, req resource.CreateReq
 		return
 	}
 
-	createReq := hubclient.AccessTokenCreateParams{
-		Scopes:     scopes,
-		TokenLabel: data.TokenLabel.ValueString(),
-	}
-
-	if !data.ExpiresAt.IsNull() {
-		createReq.ExpiresAt = data.ExpiresAt.ValueString()
-	}
-
-	at, err := r.client.CreateAccessToken(ctx, createReq)
+	at, err := r.client.CreateAccessToken(ctx, accessTokenCreateParams(data, scopes))
 	if err != nil {
 		resp.Diagnostics.AddError("Unable to create access token", err.Error())
 		return
 	}
 
+	rotation := data.Rotation
 	data = r.toModel(ctx, at, nil)
+	data.Rotation = rotation
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -178,7 +246,7 @@ func (r *AccessTokenResource) Read(ctx context.Context, req resource.ReadRequest
 
 	at, err := r.client.GetAccessToken(ctx, fromState.UUID.ValueString())
 	// Treat HTTP 404 Not Found status as a signal to recreate resource and return early
-	if isNotFound(err) {
+	if hubclient.IsNotFound(err) {
 		resp.State.RemoveResource(ctx)
 		return
 	} else if err != nil {
@@ -214,6 +282,17 @@ func (r *AccessTokenResource) Update(ctx context.Context, req resource.UpdateReq
 		return
 	}
 
+	_, rotationPlan, diags := planAccessTokenRotation(fromPlan.Rotation, fromState.CreatedAt, !fromState.PreviousUUID.IsNull(), time.Now())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if rotationPlan == accessTokenRotationRotate {
+		r.rotate(ctx, fromState, fromPlan, resp)
+		return
+	}
+
 	updateReq := hubclient.AccessTokenUpdateParams{
 		TokenLabel: fromPlan.TokenLabel.ValueString(),
 		IsActive:   fromPlan.IsActive.ValueBool(),
@@ -226,21 +305,94 @@ func (r *AccessTokenResource) Update(ctx context.Context, req resource.UpdateReq
 	}
 
 	fromAPI := r.toModel(ctx, at, &fromState)
+	fromAPI.Rotation = fromPlan.Rotation
+
+	if rotationPlan == accessTokenRotationRevokePrevious {
+		if err := r.revoke(ctx, fromAPI.PreviousUUID.ValueString()); err != nil {
+			resp.Diagnostics.AddError("Unable to revoke previous access token", err.Error())
+		} else {
+			fromAPI.PreviousUUID = types.StringNull()
+			fromAPI.PreviousToken = types.StringNull()
+		}
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &fromAPI)...)
+}
+
+// rotate replaces the token in fromState with a new one created from
+// fromPlan. A token still left over from an earlier rotation is revoked
+// first, and the replaced token is kept as the previous token for the
+// overlap.
+func (r *AccessTokenResource) rotate(ctx context.Context, fromState, fromPlan AccessTokenResourceModel, resp *resource.UpdateResponse) {
+	rotation, _, diags := expandAccessTokenRotation(fromPlan.Rotation)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	scopes := []string{}
+	resp.Diagnostics.Append(fromPlan.Scopes.ElementsAs(ctx, &scopes, false)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	if !fromState.PreviousUUID.IsNull() {
+		if err := r.revoke(ctx, fromState.PreviousUUID.ValueString()); err != nil {
+			resp.Diagnostics.AddError("Unable to revoke previous access token", err.Error())
+			return
+		}
+	}
+
+	at, err := r.client.CreateAccessToken(ctx, accessTokenCreateParams(fromPlan, scopes))
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to rotate access token", err.Error())
+		return
+	}
+
+	fromAPI := r.toModel(ctx, at, nil)
+	fromAPI.Rotation = fromPlan.Rotation
+	fromAPI.PreviousUUID = fromState.UUID
+	fromAPI.PreviousToken = fromState.Token
+
+	// Without an overlap the replaced token is revoked straight away. If that
+	// fails it stays the previous token, so the next apply retries.
+	if rotation.overlap == 0 {
+		if err := r.revoke(ctx, fromState.UUID.ValueString()); err != nil {
+			resp.Diagnostics.AddError("Unable to revoke rotated access token", err.Error())
+		} else {
+			fromAPI.PreviousUUID = types.StringNull()
+			fromAPI.PreviousToken = types.StringNull()
+		}
+	}
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &fromAPI)...)
 }
 
+// revoke deletes a token that has been replaced, ignoring tokens that are
+// already gone.
+func (r *AccessTokenResource) revoke(ctx context.Context, accessTokenID string) error {
+	err := r.client.DeleteAccessToken(ctx, accessTokenID)
+	if hubclient.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
 func (r *AccessTokenResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 	var data AccessTokenResourceModel
 
 	// Read Terraform prior state data into the model
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
 
+	if !data.PreviousUUID.IsNull() {
+		if err := r.revoke(ctx, data.PreviousUUID.ValueString()); err != nil {
+			resp.Diagnostics.AddError("Unable to delete previous access token", err.Error())
+			return
+		}
+	}
+
 	err := r.client.DeleteAccessToken(ctx, data.UUID.ValueString())
-	if isNotFound(err) {
+	if hubclient.IsNotFound(err) {
 		return
 	} else if err != nil {
 		resp.Diagnostics.AddError("Unable to delete access token", err.Error())
@@ -255,12 +407,15 @@ func (r *AccessTokenResource) ImportState(ctx context.Context, req resource.Impo
 func (r *AccessTokenResource) toModel(ctx context.Context, at hubclient.AccessToken, currentState *AccessTokenResourceModel) AccessTokenResourceModel {
 	scopes, _ := types.ListValueFrom(ctx, types.StringType, at.Scopes)
 	result := AccessTokenResourceModel{
-		UUID:       types.StringValue(at.UUID),
-		IsActive:   types.BoolValue(at.IsActive),
-		TokenLabel: types.StringValue(at.TokenLabel),
-		Scopes:     scopes,
-		Token:      types.StringValue(at.Token),
-		ExpiresAt:  types.StringValue(at.ExpiresAt),
+		UUID:          types.StringValue(at.UUID),
+		IsActive:      types.BoolValue(at.IsActive),
+		TokenLabel:    types.StringValue(at.TokenLabel),
+		Scopes:        scopes,
+		Token:         types.StringValue(at.Token),
+		ExpiresAt:     types.StringValue(at.ExpiresAt),
+		CreatedAt:     types.StringValue(at.CreatedAt),
+		PreviousUUID:  types.StringNull(),
+		PreviousToken: types.StringNull(),
 	}
 
 	// If the current state is null, keep it as null instead of changing to empty string.
@@ -272,7 +427,23 @@ func (r *AccessTokenResource) toModel(ctx context.Context, at hubclient.AccessTo
 	// so we need to copy it from the state
 	if currentState != nil {
 		result.Token = currentState.Token
+		result.Rotation = currentState.Rotation
+
+		// The previous token is only known to state.
+		result.PreviousUUID = currentState.PreviousUUID
+		result.PreviousToken = currentState.PreviousToken
 	}
 
 	return result
 }
+
+func accessTokenCreateParams(data AccessTokenResourceModel, scopes []string) hubclient.AccessTokenCreateParams {
+	params := hubclient.AccessTokenCreateParams{
+		Scopes:     scopes,
+		TokenLabel: data.TokenLabel.ValueString(),
+	}
+	if !data.ExpiresAt.IsNull() {
+		params.ExpiresAt = data.ExpiresAt.ValueString()
+	}
+	return params
+}
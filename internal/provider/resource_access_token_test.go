@@ -18,6 +18,7 @@ package provider
 
 import (
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
@@ -100,7 +101,49 @@ resource "docker_access_token" "test" {
 	})
 }
 
+func TestAccessTokenResource_Rotation(t *testing.T) {
+	config := `
+resource "docker_access_token" "test" {
+  token_label = "test-label"
+  scopes      = ["repo:read"]
+
+  rotation {
+    rotate_after = "10s"
+  }
+}
+`
+	var firstUUID string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("docker_access_token.test", "created_at"),
+					storeResourceAttribute("docker_access_token.test", "uuid", &firstUUID),
+				),
+			},
+			{
+				// Without an overlap the replaced token is revoked by the
+				// same apply that rotates it.
+				PreConfig: func() { time.Sleep(11 * time.Second) },
+				Config:    config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					assertResourceAttributeChanged("docker_access_token.test", "uuid", firstUUID),
+					resource.TestCheckResourceAttrSet("docker_access_token.test", "token"),
+					resource.TestCheckNoResourceAttr("docker_access_token.test", "previous_uuid"),
+					resource.TestCheckNoResourceAttr("docker_access_token.test", "previous_token"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccessTokenResource_Upgrade(t *testing.T) {
+	testAccSkipFakeHub(t, "released providers only support https hosts")
+
 	config := `
 resource "docker_access_token" "test" {
   token_label = "test-label"
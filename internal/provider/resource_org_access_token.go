@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/docker/terraform-provider-docker/internal/hubclient"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
@@ -27,6 +28,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
@@ -34,9 +36,11 @@ import (
 )
 
 var (
-	_ resource.Resource                = &OrgAccessTokenResource{}
-	_ resource.ResourceWithConfigure   = &OrgAccessTokenResource{}
-	_ resource.ResourceWithImportState = &OrgAccessTokenResource{}
+	_ resource.Resource                   = &OrgAccessTokenResource{}
+	_ resource.ResourceWithConfigure      = &OrgAccessTokenResource{}
+	_ resource.ResourceWithImportState    = &OrgAccessTokenResource{}
+	_ resource.ResourceWithValidateConfig = &OrgAccessTokenResource{}
+	_ resource.ResourceWithModifyPlan     = &OrgAccessTokenResource{}
 )
 
 func NewOrgAccessTokenResource() resource.Resource {
@@ -48,13 +52,19 @@ type OrgAccessTokenResource struct {
 }
 
 type OrgAccessTokenResourceModel struct {
-	ID          types.String                       `tfsdk:"id"`
-	OrgName     types.String                       `tfsdk:"org_name"`
-	Label       types.String                       `tfsdk:"label"`
-	Description types.String                       `tfsdk:"description"`
-	Resources   []OrgAccessTokenResourceEntryModel `tfsdk:"resources"`
-	ExpiresAt   types.String                       `tfsdk:"expires_at"`
-	Token       types.String                       `tfsdk:"token"`
+	ID                types.String                       `tfsdk:"id"`
+	OrgName           types.String                       `tfsdk:"org_name"`
+	Label             types.String                       `tfsdk:"label"`
+	Description       types.String                       `tfsdk:"description"`
+	IsActive          types.Bool                         `tfsdk:"is_active"`
+	Resources         []OrgAccessTokenResourceEntryModel `tfsdk:"resources"`
+	ExpiresAt         types.String                       `tfsdk:"expires_at"`
+	Token             types.String                       `tfsdk:"token"`
+	CreatedAt         types.String                       `tfsdk:"created_at"`
+	Rotation          *AccessTokenRotationModel          `tfsdk:"rotation"`
+	CheckRepositories types.Bool                         `tfsdk:"check_repositories"`
+	PreviousID        types.String                       `tfsdk:"previous_id"`
+	PreviousToken     types.String                       `tfsdk:"previous_token"`
 }
 
 type OrgAccessTokenResourceEntryModel struct {
@@ -68,16 +78,16 @@ func (r *OrgAccessTokenResource) Configure(ctx context.Context, req resource.Con
 		return
 	}
 
-	client, ok := req.ProviderData.(*hubclient.Client)
+	providerData, ok := req.ProviderData.(*ProviderData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *hubclient.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *provider.ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
 
-	r.client = client
+	r.client = providerData.Hub
 }
 
 func (r *OrgAccessTokenResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -108,7 +118,7 @@ resource "docker_org_access_token" "example" {
 }
 ` + "```" + `
 
-For ` + "`TYPE_REPO`" + ` resources, ` + "`path`" + ` must point to an existing repository or a supported glob such as ` + "`my-organization/*`" + `.
+For ` + "`TYPE_REPO`" + ` resources, ` + "`path`" + ` must point to an existing repository or a supported glob such as ` + "`my-organization/*`" + `. Paths and scopes are checked when planning: ` + "`TYPE_REPO`" + ` resources take the ` + "`scope-image-pull`" + ` and ` + "`scope-image-push`" + ` scopes, and organization scopes such as ` + "`scope-member-read`" + ` belong to ` + "`TYPE_ORG`" + ` resources. Set ` + "`check_repositories`" + ` to also check that the repositories exist.
 
 ## Public-Only Repositories
 
@@ -128,6 +138,30 @@ resource "docker_org_access_token" "public_pull" {
   ]
 }
 ` + "```" + `
+
+## Rotation
+
+With a ` + "`rotation`" + ` block, the first apply after ` + "`rotate_after`" + ` has passed creates a replacement token with the same settings. During the overlap both the new ` + "`token`" + ` and the replaced ` + "`previous_token`" + ` are valid, so consumers can be moved over before the first apply after the overlap revokes the replaced token.
+
+` + "```hcl" + `
+resource "docker_org_access_token" "rotated" {
+  org_name = "my-organization"
+  label    = "ci-token"
+
+  resources = [
+    {
+      type   = "TYPE_REPO"
+      path   = "my-organization/*"
+      scopes = ["scope-image-pull"]
+    }
+  ]
+
+  rotation {
+    rotate_after = "720h"
+    overlap      = "24h"
+  }
+}
+` + "```" + `
 `,
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
@@ -149,6 +183,14 @@ resource "docker_org_access_token" "public_pull" {
 				MarkdownDescription: "Description for the access token",
 				Optional:            true,
 			},
+			"is_active": schema.BoolAttribute{
+				MarkdownDescription: "Whether the token is active. Deactivating a token keeps it, and its usage history, without allowing it to be used. If not set, new tokens are active and Terraform leaves the state of the token alone, so a token deactivated outside of Terraform stays deactivated.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
 			"resources": schema.ListNestedAttribute{
 				MarkdownDescription: "Resources this token has access to",
 				Required:            true,
@@ -173,6 +215,10 @@ resource "docker_org_access_token" "public_pull" {
 					},
 				},
 			},
+			"check_repositories": schema.BoolAttribute{
+				MarkdownDescription: "Check at plan time that every repository named by a `TYPE_REPO` resource exists. Leave unset when the repositories are created in the same apply as the token.",
+				Optional:            true,
+			},
 			"expires_at": schema.StringAttribute{
 				MarkdownDescription: "Expiration date for the token. Changing this value recreates the token.",
 				Optional:            true,
@@ -188,7 +234,101 @@ resource "docker_org_access_token" "public_pull" {
 				Computed:            true,
 				Sensitive:           true,
 			},
+			"created_at": schema.StringAttribute{
+				MarkdownDescription: "Time the current token was created. The rotation schedule is tracked from this time.",
+				Computed:            true,
+			},
+			"previous_id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the token replaced by the last rotation, while it is still valid during the overlap",
+				Computed:            true,
+			},
+			"previous_token": schema.StringAttribute{
+				MarkdownDescription: "The token replaced by the last rotation, while it is still valid during the overlap",
+				Computed:            true,
+				Sensitive:           true,
+			},
 		},
+		Blocks: map[string]schema.Block{
+			"rotation": accessTokenRotationBlock(),
+		},
+	}
+}
+
+func (r *OrgAccessTokenResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var orgName types.String
+	var resources types.List
+	var rotation *AccessTokenRotationModel
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("org_name"), &orgName)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("resources"), &resources)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("rotation"), &rotation)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	_, _, diags := expandAccessTokenRotation(rotation)
+	resp.Diagnostics.Append(diags...)
+
+	if resources.IsNull() || resources.IsUnknown() {
+		return
+	}
+	var entries []OrgAccessTokenResourceEntryModel
+	resp.Diagnostics.Append(resources.ElementsAs(ctx, &entries, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(validateOrgAccessTokenResources(ctx, orgName, entries)...)
+}
+
+// ModifyPlan checks that the repositories the token gives access to exist,
+// if asked to, and plans an update once the token is due for rotation or the
+// token it replaced is due to be revoked.
+func (r *OrgAccessTokenResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	// The resources may not be known yet, so attributes are read one by one
+	// rather than into OrgAccessTokenResourceModel.
+	var checkRepositories types.Bool
+	var resources types.List
+	var rotation *AccessTokenRotationModel
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("check_repositories"), &checkRepositories)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("resources"), &resources)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("rotation"), &rotation)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if checkRepositories.ValueBool() && !resources.IsUnknown() && r.client != nil {
+		var entries []OrgAccessTokenResourceEntryModel
+		resp.Diagnostics.Append(resources.ElementsAs(ctx, &entries, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		resp.Diagnostics.Append(checkOrgAccessTokenRepositories(ctx, r.client, entries)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if req.State.Raw.IsNull() {
+		return
+	}
+	var createdAt, previousID types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("created_at"), &createdAt)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("previous_id"), &previousID)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	_, rotationPlan, diags := planAccessTokenRotation(rotation, createdAt, !previousID.IsNull(), time.Now())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || rotationPlan == accessTokenRotationNone {
+		return
+	}
+
+	for _, name := range append([]string{"id", "previous_id"}, accessTokenRotationComputedAttributes...) {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root(name), types.StringUnknown())...)
 	}
 }
 
@@ -205,16 +345,7 @@ func (r *OrgAccessTokenResource) Create(ctx context.Context, req resource.Create
 		return
 	}
 
-	createReq := hubclient.OrgAccessTokenCreateParams{
-		Label:       data.Label.ValueString(),
-		Description: stringValueOrEmpty(data.Description),
-		Resources:   tokenResources,
-	}
-	if !data.ExpiresAt.IsNull() {
-		createReq.ExpiresAt = data.ExpiresAt.ValueString()
-	}
-
-	at, err := r.client.CreateOrgAccessToken(ctx, data.OrgName.ValueString(), createReq)
+	at, err := r.client.CreateOrgAccessToken(ctx, data.OrgName.ValueString(), orgAccessTokenCreateParams(data, tokenResources))
 	if err != nil {
 		resp.Diagnostics.AddError("Unable to create org access token", err.Error())
 		return
@@ -225,6 +356,7 @@ func (r *OrgAccessTokenResource) Create(ctx context.Context, req resource.Create
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	r.deactivateIfInactive(ctx, at, data, &model, &resp.Diagnostics)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
 }
@@ -237,7 +369,7 @@ func (r *OrgAccessTokenResource) Read(ctx context.Context, req resource.ReadRequ
 	}
 
 	at, err := r.client.GetOrgAccessToken(ctx, fromState.OrgName.ValueString(), fromState.ID.ValueString())
-	if isNotFound(err) {
+	if hubclient.IsNotFound(err) {
 		resp.State.RemoveResource(ctx)
 		return
 	} else if err != nil {
@@ -269,11 +401,31 @@ func (r *OrgAccessTokenResource) Update(ctx context.Context, req resource.Update
 		return
 	}
 
+	_, rotationPlan, diags := planAccessTokenRotation(fromPlan.Rotation, fromState.CreatedAt, !fromState.PreviousID.IsNull(), time.Now())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if rotationPlan == accessTokenRotationRotate {
+		r.rotate(ctx, fromState, fromPlan, tokenResources, resp)
+		return
+	}
+
 	updateReq := hubclient.OrgAccessTokenUpdateParams{
 		Label:       fromPlan.Label.ValueString(),
 		Description: stringValueOrEmpty(fromPlan.Description),
 		Resources:   tokenResources,
 	}
+	// A token whose is_active is not managed keeps the state it was read
+	// with, and is left alone when that is not known either.
+	isActive := fromPlan.IsActive
+	if isActive.IsUnknown() || isActive.IsNull() {
+		isActive = fromState.IsActive
+	}
+	if !isActive.IsUnknown() && !isActive.IsNull() {
+		updateReq.IsActive = isActive.ValueBoolPointer()
+	}
 
 	at, err := r.client.UpdateOrgAccessToken(ctx, fromState.OrgName.ValueString(), fromState.ID.ValueString(), updateReq)
 	if err != nil {
@@ -286,10 +438,104 @@ func (r *OrgAccessTokenResource) Update(ctx context.Context, req resource.Update
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	model.Rotation = fromPlan.Rotation
+	model.CheckRepositories = fromPlan.CheckRepositories
+
+	if rotationPlan == accessTokenRotationRevokePrevious {
+		if err := r.revoke(ctx, model.OrgName.ValueString(), model.PreviousID.ValueString()); err != nil {
+			resp.Diagnostics.AddError("Unable to revoke previous org access token", err.Error())
+		} else {
+			model.PreviousID = types.StringNull()
+			model.PreviousToken = types.StringNull()
+		}
+	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
 }
 
+// rotate replaces the token in fromState with a new one created from
+// fromPlan. A token still left over from an earlier rotation is revoked
+// first, and the replaced token is kept as the previous token for the
+// overlap.
+func (r *OrgAccessTokenResource) rotate(ctx context.Context, fromState, fromPlan OrgAccessTokenResourceModel, tokenResources []hubclient.OrgAccessTokenResource, resp *resource.UpdateResponse) {
+	rotation, _, diags := expandAccessTokenRotation(fromPlan.Rotation)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	orgName := fromState.OrgName.ValueString()
+	if !fromState.PreviousID.IsNull() {
+		if err := r.revoke(ctx, orgName, fromState.PreviousID.ValueString()); err != nil {
+			resp.Diagnostics.AddError("Unable to revoke previous org access token", err.Error())
+			return
+		}
+	}
+
+	at, err := r.client.CreateOrgAccessToken(ctx, orgName, orgAccessTokenCreateParams(fromPlan, tokenResources))
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to rotate org access token", err.Error())
+		return
+	}
+
+	model, diags := r.toModel(ctx, at, nil)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	model.OrgName = fromState.OrgName
+	model.Rotation = fromPlan.Rotation
+	model.CheckRepositories = fromPlan.CheckRepositories
+	model.PreviousID = fromState.ID
+	model.PreviousToken = fromState.Token
+	r.deactivateIfInactive(ctx, at, fromPlan, &model, &resp.Diagnostics)
+
+	// Without an overlap the replaced token is revoked straight away. If that
+	// fails it stays the previous token, so the next apply retries.
+	if rotation.overlap == 0 {
+		if err := r.revoke(ctx, orgName, fromState.ID.ValueString()); err != nil {
+			resp.Diagnostics.AddError("Unable to revoke rotated org access token", err.Error())
+		} else {
+			model.PreviousID = types.StringNull()
+			model.PreviousToken = types.StringNull()
+		}
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+// deactivateIfInactive deactivates the newly created token at when data asks
+// for an inactive token, as tokens are always created active. The token is
+// already in model, so a failure leaves it active for the next apply to fix.
+func (r *OrgAccessTokenResource) deactivateIfInactive(ctx context.Context, at hubclient.OrgAccessToken, data OrgAccessTokenResourceModel, model *OrgAccessTokenResourceModel, diags *diag.Diagnostics) {
+	if data.IsActive.IsUnknown() || data.IsActive.ValueBool() {
+		return
+	}
+
+	inactive := false
+	updated, err := r.client.UpdateOrgAccessToken(ctx, model.OrgName.ValueString(), at.ID, hubclient.OrgAccessTokenUpdateParams{
+		Label:       at.Label,
+		Description: at.Description,
+		Resources:   at.Resources,
+		IsActive:    &inactive,
+	})
+	if err != nil {
+		diags.AddError("Unable to deactivate org access token", err.Error())
+		return
+	}
+	model.IsActive = types.BoolValue(updated.IsActive)
+}
+
+// revoke deletes a token that has been replaced, ignoring tokens that are
+// already gone.
+func (r *OrgAccessTokenResource) revoke(ctx context.Context, orgName, accessTokenID string) error {
+	err := r.client.DeleteOrgAccessToken(ctx, orgName, accessTokenID)
+	if hubclient.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
 func (r *OrgAccessTokenResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 	var data OrgAccessTokenResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
@@ -297,8 +543,15 @@ func (r *OrgAccessTokenResource) Delete(ctx context.Context, req resource.Delete
 		return
 	}
 
+	if !data.PreviousID.IsNull() {
+		if err := r.revoke(ctx, data.OrgName.ValueString(), data.PreviousID.ValueString()); err != nil {
+			resp.Diagnostics.AddError("Unable to delete previous org access token", err.Error())
+			return
+		}
+	}
+
 	err := r.client.DeleteOrgAccessToken(ctx, data.OrgName.ValueString(), data.ID.ValueString())
-	if isNotFound(err) {
+	if hubclient.IsNotFound(err) {
 		return
 	} else if err != nil {
 		resp.Diagnostics.AddError("Unable to delete org access token", err.Error())
@@ -320,6 +573,18 @@ func (r *OrgAccessTokenResource) ImportState(ctx context.Context, req resource.I
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), idParts[1])...)
 }
 
+func orgAccessTokenCreateParams(data OrgAccessTokenResourceModel, tokenResources []hubclient.OrgAccessTokenResource) hubclient.OrgAccessTokenCreateParams {
+	params := hubclient.OrgAccessTokenCreateParams{
+		Label:       data.Label.ValueString(),
+		Description: stringValueOrEmpty(data.Description),
+		Resources:   tokenResources,
+	}
+	if !data.ExpiresAt.IsNull() {
+		params.ExpiresAt = data.ExpiresAt.ValueString()
+	}
+	return params
+}
+
 func expandOrgAccessTokenResources(ctx context.Context, resources []OrgAccessTokenResourceEntryModel) ([]hubclient.OrgAccessTokenResource, diag.Diagnostics) {
 	var diags diag.Diagnostics
 
@@ -373,16 +638,29 @@ func (r *OrgAccessTokenResource) toModel(
 	}
 
 	model := OrgAccessTokenResourceModel{
-		ID:          types.StringValue(at.ID),
-		Label:       types.StringValue(at.Label),
-		Description: types.StringValue(at.Description),
-		Resources:   resources,
-		ExpiresAt:   types.StringValue(at.ExpiresAt),
-		Token:       types.StringValue(at.Token),
+		ID:            types.StringValue(at.ID),
+		Label:         types.StringValue(at.Label),
+		Description:   types.StringValue(at.Description),
+		IsActive:      types.BoolValue(at.IsActive),
+		Resources:     resources,
+		ExpiresAt:     types.StringValue(at.ExpiresAt),
+		Token:         types.StringValue(at.Token),
+		CreatedAt:     types.StringValue(at.CreatedAt),
+		PreviousID:    types.StringNull(),
+		PreviousToken: types.StringNull(),
 	}
 
 	if currentState != nil {
 		model.OrgName = currentState.OrgName
+		model.Rotation = currentState.Rotation
+		model.CheckRepositories = currentState.CheckRepositories
+
+		// The previous token is only known to state, and is not set yet
+		// while a token is being created.
+		if !currentState.PreviousID.IsUnknown() {
+			model.PreviousID = currentState.PreviousID
+			model.PreviousToken = currentState.PreviousToken
+		}
 
 		// The token is not returned by the API after initial creation,
 		// so we need to preserve it from state on subsequent reads.
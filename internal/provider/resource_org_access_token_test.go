@@ -17,8 +17,11 @@
 package provider
 
 import (
+	"context"
 	"fmt"
+	"regexp"
 	"testing"
+	"time"
 
 	"github.com/docker/terraform-provider-docker/internal/envvar"
 	"github.com/docker/terraform-provider-docker/internal/hubclient"
@@ -51,7 +54,7 @@ func TestAccOrgAccessTokenResource(t *testing.T) {
 					resource.TestCheckResourceAttr("docker_org_access_token.test", "resources.0.scopes.0", "scope-image-pull"),
 					resource.TestCheckResourceAttr("docker_org_access_token.test", "expires_at", "2029-12-31T23:59:59Z"),
 					resource.TestCheckResourceAttrSet("docker_org_access_token.test", "token"),
-					resource.TestCheckNoResourceAttr("docker_org_access_token.test", "is_active"),
+					resource.TestCheckResourceAttr("docker_org_access_token.test", "is_active", "true"),
 					resource.TestCheckNoResourceAttr("docker_org_access_token.test", "last_used_at"),
 				),
 			},
@@ -110,6 +113,247 @@ func TestAccOrgAccessTokenResource_ExpiresAtReplaces(t *testing.T) {
 	})
 }
 
+func TestAccOrgAccessTokenResource_IsActive(t *testing.T) {
+	orgName := envvar.GetWithDefault(envvar.AccTestOrganization)
+	label := "test-" + randString(10)
+	allReposPath := orgName + "/*"
+	var firstID string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccOrgAccessTokenResourceIsActiveConfig(orgName, label, allReposPath, true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("docker_org_access_token.test", "is_active", "true"),
+					storeResourceAttribute("docker_org_access_token.test", "id", &firstID),
+				),
+			},
+			{
+				Config: testAccOrgAccessTokenResourceIsActiveConfig(orgName, label, allReposPath, false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("docker_org_access_token.test", "is_active", "false"),
+					resource.TestCheckResourceAttrPtr("docker_org_access_token.test", "id", &firstID),
+					resource.TestCheckResourceAttrSet("docker_org_access_token.test", "token"),
+				),
+			},
+			{
+				ResourceName:                         "docker_org_access_token.test",
+				ImportState:                          true,
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "id",
+				ImportStateVerifyIgnore: []string{
+					"token",
+				},
+				ImportStateIdFunc: func(state *terraform.State) (string, error) {
+					return orgName + "/" + state.RootModule().Resources["docker_org_access_token.test"].Primary.Attributes["id"], nil
+				},
+			},
+			{
+				Config: testAccOrgAccessTokenResourceIsActiveConfig(orgName, label, allReposPath, true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("docker_org_access_token.test", "is_active", "true"),
+					resource.TestCheckResourceAttrPtr("docker_org_access_token.test", "id", &firstID),
+				),
+			},
+		},
+	})
+}
+
+func TestAccOrgAccessTokenResource_CreateInactive(t *testing.T) {
+	orgName := envvar.GetWithDefault(envvar.AccTestOrganization)
+	label := "test-" + randString(10)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccOrgAccessTokenResourceIsActiveConfig(orgName, label, orgName+"/*", false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("docker_org_access_token.test", "is_active", "false"),
+					resource.TestCheckResourceAttrSet("docker_org_access_token.test", "token"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccOrgAccessTokenResource_IsActiveUnset(t *testing.T) {
+	orgName := envvar.GetWithDefault(envvar.AccTestOrganization)
+	label := "test-" + randString(10)
+	allReposPath := orgName + "/*"
+	var id string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccOrgAccessTokenResourceConfig(orgName, label, "test description", allReposPath, "2029-12-31T23:59:59Z"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("docker_org_access_token.test", "is_active", "true"),
+					storeResourceAttribute("docker_org_access_token.test", "id", &id),
+				),
+			},
+			{
+				// A token deactivated outside of Terraform stays deactivated
+				// while is_active is not set.
+				PreConfig: func() {
+					ctx := context.Background()
+					client := testAccHubClient()
+					at, err := client.GetOrgAccessToken(ctx, orgName, id)
+					if err != nil {
+						t.Fatal(err)
+					}
+					inactive := false
+					_, err = client.UpdateOrgAccessToken(ctx, orgName, id, hubclient.OrgAccessTokenUpdateParams{
+						Label:       at.Label,
+						Description: at.Description,
+						Resources:   at.Resources,
+						IsActive:    &inactive,
+					})
+					if err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config:   testAccOrgAccessTokenResourceConfig(orgName, label, "test description", allReposPath, "2029-12-31T23:59:59Z"),
+				PlanOnly: true,
+			},
+			{
+				Config: testAccOrgAccessTokenResourceConfig(orgName, label, "updated description", allReposPath, "2029-12-31T23:59:59Z"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("docker_org_access_token.test", "description", "updated description"),
+					resource.TestCheckResourceAttr("docker_org_access_token.test", "is_active", "false"),
+				),
+			},
+		},
+	})
+}
+
+func testAccOrgAccessTokenResourceIsActiveConfig(orgName, label, resourcePath string, isActive bool) string {
+	return fmt.Sprintf(`
+resource "docker_org_access_token" "test" {
+  org_name  = "%s"
+  label     = "%s"
+  is_active = %t
+  resources = [
+    {
+      type   = "%s"
+      path   = "%s"
+      scopes = ["scope-image-pull"]
+    }
+  ]
+}
+`, orgName, label, isActive, hubclient.OrgAccessTokenTypeRepo, resourcePath)
+}
+
+func TestAccOrgAccessTokenResource_PlanValidation(t *testing.T) {
+	orgName := envvar.GetWithDefault(envvar.AccTestOrganization)
+	label := "test-" + randString(10)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccOrgAccessTokenResourceScopeConfig(orgName, label, hubclient.OrgAccessTokenTypeOrg, orgName, "repo:write", false),
+				ExpectError: regexp.MustCompile(`personal access token scope`),
+			},
+			{
+				Config:      testAccOrgAccessTokenResourceScopeConfig(orgName, label, hubclient.OrgAccessTokenTypeRepo, orgName+"/app-*", "scope-image-pull", false),
+				ExpectError: regexp.MustCompile(`unsupported glob`),
+			},
+			{
+				Config:      testAccOrgAccessTokenResourceScopeConfig(orgName, label, hubclient.OrgAccessTokenTypeRepo, orgName+"/missing-"+randString(10), "scope-image-pull", true),
+				ExpectError: regexp.MustCompile(`Repository Not Found`),
+			},
+		},
+	})
+}
+
+func testAccOrgAccessTokenResourceScopeConfig(orgName, label, resourceType, resourcePath, scope string, checkRepositories bool) string {
+	return fmt.Sprintf(`
+resource "docker_org_access_token" "test" {
+  org_name           = "%s"
+  label              = "%s"
+  check_repositories = %t
+  resources = [
+    {
+      type   = "%s"
+      path   = "%s"
+      scopes = ["%s"]
+    }
+  ]
+}
+`, orgName, label, checkRepositories, resourceType, resourcePath, scope)
+}
+
+func TestAccOrgAccessTokenResource_Rotation(t *testing.T) {
+	orgName := envvar.GetWithDefault(envvar.AccTestOrganization)
+	label := "test-" + randString(10)
+	allReposPath := orgName + "/*"
+	var firstID, secondID string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccOrgAccessTokenResourceRotationConfig(orgName, label, allReposPath, "10s", "1h"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("docker_org_access_token.test", "created_at"),
+					resource.TestCheckNoResourceAttr("docker_org_access_token.test", "previous_id"),
+					resource.TestCheckNoResourceAttr("docker_org_access_token.test", "previous_token"),
+					storeResourceAttribute("docker_org_access_token.test", "id", &firstID),
+				),
+			},
+			{
+				PreConfig: func() { time.Sleep(11 * time.Second) },
+				Config:    testAccOrgAccessTokenResourceRotationConfig(orgName, label, allReposPath, "10s", "1h"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					assertResourceAttributeChanged("docker_org_access_token.test", "id", firstID),
+					resource.TestCheckResourceAttrPtr("docker_org_access_token.test", "previous_id", &firstID),
+					resource.TestCheckResourceAttrSet("docker_org_access_token.test", "previous_token"),
+					resource.TestCheckResourceAttrSet("docker_org_access_token.test", "token"),
+					storeResourceAttribute("docker_org_access_token.test", "id", &secondID),
+				),
+			},
+			{
+				PreConfig: func() { time.Sleep(2 * time.Second) },
+				Config:    testAccOrgAccessTokenResourceRotationConfig(orgName, label, allReposPath, "1h", "1s"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPtr("docker_org_access_token.test", "id", &secondID),
+					resource.TestCheckNoResourceAttr("docker_org_access_token.test", "previous_id"),
+					resource.TestCheckNoResourceAttr("docker_org_access_token.test", "previous_token"),
+				),
+			},
+		},
+	})
+}
+
+func testAccOrgAccessTokenResourceRotationConfig(orgName, label, resourcePath, rotateAfter, overlap string) string {
+	return fmt.Sprintf(`
+resource "docker_org_access_token" "test" {
+  org_name = "%s"
+  label    = "%s"
+  resources = [
+    {
+      type   = "%s"
+      path   = "%s"
+      scopes = ["scope-image-pull"]
+    }
+  ]
+
+  rotation {
+    rotate_after = "%s"
+    overlap      = "%s"
+  }
+}
+`, orgName, label, hubclient.OrgAccessTokenTypeRepo, resourcePath, rotateAfter, overlap)
+}
+
 func testAccOrgAccessTokenResourceConfig(orgName, label, description, resourcePath, expiresAt string) string {
 	return fmt.Sprintf(`
 resource "docker_org_access_token" "test" {
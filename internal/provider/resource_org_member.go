@@ -66,16 +66,16 @@ func (r *OrgMemberResource) Configure(ctx context.Context, req resource.Configur
 		return
 	}
 
-	client, ok := req.ProviderData.(*hubclient.Client)
+	providerData, ok := req.ProviderData.(*ProviderData)
 	if !ok {
-		errMsg := fmt.Sprintf("Expected *hubclient.Client, got: %T", req.ProviderData)
+		errMsg := fmt.Sprintf("Expected *provider.ProviderData, got: %T", req.ProviderData)
 		resp.Diagnostics.AddError("Unexpected Resource Configure Type", errMsg)
 		return
 	}
 
 	r.orgMembers = make(map[string][]hubclient.OrgMember)
 	r.orgInvites = make(map[string][]hubclient.OrgInvite)
-	r.client = client
+	r.client = providerData.Hub
 }
 
 func (r *OrgMemberResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -255,13 +255,16 @@ func (r *OrgMemberResource) Read(ctx context.Context, req resource.ReadRequest,
 	}
 
 	data, found, err := r.orgMember(ctx, state.OrgName.ValueString(), invitee)
-	if err != nil {
+	if hubclient.IsNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	} else if err != nil {
 		resp.Diagnostics.AddError("Error Reading Resource", err.Error())
 		return
 	}
 	if !found {
-		resp.Diagnostics.AddError("Resource Not Found",
-			fmt.Sprintf("Member not found in %s: %s", state.OrgName.ValueString(), invitee))
+		// The member left or the invite was revoked outside of Terraform
+		resp.State.RemoveResource(ctx)
 		return
 	}
 
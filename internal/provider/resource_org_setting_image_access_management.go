@@ -55,7 +55,7 @@ func (r *OrgSettingImageAccessManagementResource) Configure(ctx context.Context,
 		return
 	}
 
-	client, ok := req.ProviderData.(*hubclient.Client)
+	providerData, ok := req.ProviderData.(*ProviderData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
@@ -66,7 +66,7 @@ func (r *OrgSettingImageAccessManagementResource) Configure(ctx context.Context,
 		return
 	}
 
-	r.client = client
+	r.client = providerData.Hub
 }
 
 func (r *OrgSettingImageAccessManagementResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -150,7 +150,11 @@ func (r *OrgSettingImageAccessManagementResource) Read(ctx context.Context, req
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
 
 	iamResp, err := r.client.GetOrgSettingImageAccessManagement(ctx, data.OrgName.ValueString())
-	if err != nil {
+	// Treat HTTP 404 Not Found status as a signal to recreate resource and return early
+	if hubclient.IsNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	} else if err != nil {
 		resp.Diagnostics.AddError("Unable to read org_setting_image_access_management resource", err.Error())
 		return
 	}
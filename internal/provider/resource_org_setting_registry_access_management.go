@@ -82,7 +82,7 @@ func (r *OrgSettingRegistryAccessManagementResource) Configure(ctx context.Conte
 		return
 	}
 
-	client, ok := req.ProviderData.(*hubclient.Client)
+	providerData, ok := req.ProviderData.(*ProviderData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
@@ -93,7 +93,7 @@ func (r *OrgSettingRegistryAccessManagementResource) Configure(ctx context.Conte
 		return
 	}
 
-	r.client = client
+	r.client = providerData.Hub
 }
 
 func (r *OrgSettingRegistryAccessManagementResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -214,7 +214,11 @@ func (r *OrgSettingRegistryAccessManagementResource) Read(ctx context.Context, r
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
 
 	reamResp, err := r.client.GetOrgSettingRegistryAccessManagement(ctx, data.OrgName.ValueString())
-	if err != nil {
+	// Treat HTTP 404 Not Found status as a signal to recreate resource and return early
+	if hubclient.IsNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	} else if err != nil {
 		resp.Diagnostics.AddError("Unable to read org_setting_registry_access_management resource", err.Error())
 		return
 	}
@@ -60,7 +60,7 @@ func (r *OrgTeamResource) Configure(ctx context.Context, req resource.ConfigureR
 		return
 	}
 
-	client, ok := req.ProviderData.(*hubclient.Client)
+	providerData, ok := req.ProviderData.(*ProviderData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
@@ -71,7 +71,7 @@ func (r *OrgTeamResource) Configure(ctx context.Context, req resource.ConfigureR
 		return
 	}
 
-	r.client = client
+	r.client = providerData.Hub
 }
 
 func (r *OrgTeamResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -164,7 +164,7 @@ func (r *OrgTeamResource) Read(ctx context.Context, req resource.ReadRequest, re
 
 	orgTeam, err := r.client.GetOrgTeam(ctx, data.OrgName.ValueString(), data.TeamName.ValueString())
 	// Treat HTTP 404 Not Found status as a signal to recreate resource and return early
-	if isNotFound(err) {
+	if hubclient.IsNotFound(err) {
 		resp.State.RemoveResource(ctx)
 		return
 	} else if err != nil {
@@ -226,7 +226,7 @@ func (r *OrgTeamResource) Delete(ctx context.Context, req resource.DeleteRequest
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
 
 	err := r.client.DeleteOrgTeam(ctx, data.OrgName.ValueString(), data.TeamName.ValueString())
-	if isNotFound(err) {
+	if hubclient.IsNotFound(err) {
 		return
 	} else if err != nil {
 		resp.Diagnostics.AddError("Unable to delete org_team resource", err.Error())
@@ -249,8 +249,3 @@ func (r *OrgTeamResource) ImportState(ctx context.Context, req resource.ImportSt
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("org_name"), idParts[0])...)
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("team_name"), idParts[1])...)
 }
-
-func isNotFound(err error) bool {
-	// todo: better
-	return err != nil && strings.Contains(strings.ToLower(err.Error()), "not found")
-}
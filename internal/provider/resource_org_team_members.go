@@ -0,0 +1,424 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/docker/terraform-provider-docker/internal/hubclient"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource                = &OrgTeamMembersResource{}
+	_ resource.ResourceWithConfigure   = &OrgTeamMembersResource{}
+	_ resource.ResourceWithImportState = &OrgTeamMembersResource{}
+)
+
+// defaultTeamMembersParallelism is how many members are added or removed at
+// the same time by default.
+const defaultTeamMembersParallelism = 10
+
+func NewOrgTeamMembersResource() resource.Resource {
+	return &OrgTeamMembersResource{}
+}
+
+type OrgTeamMembersResource struct {
+	client *hubclient.Client
+}
+
+type OrgTeamMembersResourceModel struct {
+	ID          types.String `tfsdk:"id"`
+	OrgName     types.String `tfsdk:"org_name"`
+	TeamName    types.String `tfsdk:"team_name"`
+	UserNames   types.Set    `tfsdk:"user_names"`
+	Parallelism types.Int64  `tfsdk:"parallelism"`
+}
+
+func (r *OrgTeamMembersResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *provider.ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = providerData.Hub
+}
+
+func (r *OrgTeamMembersResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_org_team_members"
+}
+
+func (r *OrgTeamMembersResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: `Manages the complete set of members of an organization team.
+
+This resource is authoritative: members added outside of Terraform, for example
+through the Docker Hub UI, show up as drift and are removed on the next apply.
+Use ` + "`docker_org_team_member`" + ` instead to manage individual members of a
+team that is also managed elsewhere. Do not use both resources for the same team.
+
+-> **Note**: This resource requires credentials that can read and manage organization groups, such as an owner login with a user password or an organization access token (OAT) with the ` + "`Group Read`" + ` and ` + "`Group Edit`" + ` scopes.
+
+-> **Note**: Team members are read using the provider's ` + "`max_page_results`" + ` limit, with 100 members per page. Reading a team with more members than that fails, set ` + "`max_page_results`" + ` to 0 for teams with more than 5000 members.
+
+## Example Usage
+
+` + "```hcl" + `
+resource "docker_org_team_members" "example" {
+  org_name  = "my-organization"
+  team_name = "dev-team"
+  user_names = [
+    "johndoe",
+    "janedoe",
+  ]
+}
+` + "```" + `
+
+## Import
+
+Team members can be imported with the organization and team name:
+
+` + "```shell" + `
+terraform import docker_org_team_members.example my-organization/dev-team
+` + "```" + `
+`,
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the team, in the form `org_name/team_name`",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"org_name": schema.StringAttribute{
+				MarkdownDescription: "Organization name",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"team_name": schema.StringAttribute{
+				MarkdownDescription: "Team name",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^[a-zA-Z0-9_-]{3,30}$`), "Team name must be 3-30 characters long and can only contain letters, numbers, underscores, or hyphens."),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"user_names": schema.SetAttribute{
+				MarkdownDescription: "User names of all the members of the team",
+				Required:            true,
+				ElementType:         types.StringType,
+			},
+			"parallelism": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("Maximum number of members added or removed at the same time. Defaults to %d.", defaultTeamMembersParallelism),
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(defaultTeamMembersParallelism),
+				Validators: []validator.Int64{
+					int64validator.Between(1, 50),
+				},
+			},
+		},
+	}
+}
+
+func (r *OrgTeamMembersResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data OrgTeamMembersResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	current, err := r.client.ListOrgTeamMembers(ctx, data.OrgName.ValueString(), data.TeamName.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to read team members", listingError(err))
+		return
+	}
+
+	data.ID = types.StringValue(fmt.Sprintf("%s/%s", data.OrgName.ValueString(), data.TeamName.ValueString()))
+	r.reconcile(ctx, &data, teamMemberUserNames(current), &resp.Diagnostics)
+
+	// Save data into Terraform state, even on partial failure, so that the
+	// next plan shows the remaining difference.
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *OrgTeamMembersResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data OrgTeamMembersResourceModel
+
+	// Read Terraform state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	members, err := r.client.ListOrgTeamMembers(ctx, data.OrgName.ValueString(), data.TeamName.ValueString())
+	// If the team itself is gone, the memberships are gone too
+	if hubclient.IsNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	} else if err != nil {
+		resp.Diagnostics.AddError("Unable to read org_team_members resource", fmt.Sprintf("Error retrieving team members: %s", listingError(err)))
+		return
+	}
+
+	// Keep the spelling of the configuration for user names that only
+	// differ in case, to avoid a perpetual diff.
+	var prior []string
+	if !data.UserNames.IsNull() {
+		resp.Diagnostics.Append(data.UserNames.ElementsAs(ctx, &prior, false)...)
+	}
+	spelling := make(map[string]string, len(prior))
+	for _, userName := range prior {
+		spelling[strings.ToLower(userName)] = userName
+	}
+	current := teamMemberUserNames(members)
+	for i, userName := range current {
+		if s, ok := spelling[strings.ToLower(userName)]; ok {
+			current[i] = s
+		}
+	}
+
+	userNames, diags := types.SetValueFrom(ctx, types.StringType, current)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.ID = types.StringValue(fmt.Sprintf("%s/%s", data.OrgName.ValueString(), data.TeamName.ValueString()))
+	data.UserNames = userNames
+	if data.Parallelism.IsNull() {
+		data.Parallelism = types.Int64Value(defaultTeamMembersParallelism)
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *OrgTeamMembersResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data OrgTeamMembersResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	current, err := r.client.ListOrgTeamMembers(ctx, data.OrgName.ValueString(), data.TeamName.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to read team members", listingError(err))
+		return
+	}
+
+	r.reconcile(ctx, &data, teamMemberUserNames(current), &resp.Diagnostics)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *OrgTeamMembersResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data OrgTeamMembersResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	current, err := r.client.ListOrgTeamMembers(ctx, data.OrgName.ValueString(), data.TeamName.ValueString())
+	if hubclient.IsNotFound(err) {
+		return
+	} else if err != nil {
+		resp.Diagnostics.AddError("Unable to read team members", listingError(err))
+		return
+	}
+
+	// Remove every member, including those added since the last refresh.
+	data.UserNames = types.SetValueMust(types.StringType, nil)
+	r.reconcile(ctx, &data, teamMemberUserNames(current), &resp.Diagnostics)
+}
+
+func (r *OrgTeamMembersResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	idParts := strings.Split(req.ID, "/")
+
+	if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected import identifier with format: org_name/team_name. Got: %q", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("org_name"), idParts[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("team_name"), idParts[1])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("parallelism"), int64(defaultTeamMembersParallelism))...)
+}
+
+// reconcile adds and removes team members until the team has exactly the
+// members in data.UserNames. Callers pass the current members as read from
+// Docker Hub right before, so that members added since the last refresh are
+// removed too.
+//
+// On failure, data.UserNames is set to the members the team ends up with.
+func (r *OrgTeamMembersResource) reconcile(ctx context.Context, data *OrgTeamMembersResourceModel, current []string, diags *diag.Diagnostics) {
+	orgName := data.OrgName.ValueString()
+	teamName := data.TeamName.ValueString()
+
+	var desired []string
+	diags.Append(data.UserNames.ElementsAs(ctx, &desired, false)...)
+	if diags.HasError() {
+		return
+	}
+
+	toAdd, toRemove := diffTeamMembers(current, desired)
+
+	parallelism := int(data.Parallelism.ValueInt64())
+	if parallelism <= 0 {
+		parallelism = defaultTeamMembersParallelism
+	}
+
+	failedAdds := forEachLimit(toAdd, parallelism, func(userName string) error {
+		return r.client.AddOrgTeamMember(ctx, orgName, teamName, userName)
+	})
+	failedRemoves := forEachLimit(toRemove, parallelism, func(userName string) error {
+		err := r.client.DeleteOrgTeamMember(ctx, orgName, teamName, userName)
+		if hubclient.IsNotFound(err) {
+			return nil
+		}
+		return err
+	})
+	if len(failedAdds) == 0 && len(failedRemoves) == 0 {
+		return
+	}
+
+	for _, userName := range toAdd {
+		if err, failed := failedAdds[userName]; failed {
+			diags.AddError("Unable to add team member", fmt.Sprintf("Unable to add %s to team %s: %v", userName, teamName, err))
+		}
+	}
+	for _, userName := range toRemove {
+		if err, failed := failedRemoves[userName]; failed {
+			diags.AddError("Unable to remove team member", fmt.Sprintf("Unable to remove %s from team %s: %v", userName, teamName, err))
+		}
+	}
+
+	// Record what actually happened, so that the next plan retries the
+	// failed changes.
+	actual := make([]string, 0, len(desired)+len(failedRemoves))
+	for _, userName := range desired {
+		if _, failed := failedAdds[userName]; !failed {
+			actual = append(actual, userName)
+		}
+	}
+	for userName := range failedRemoves {
+		actual = append(actual, userName)
+	}
+	userNames, d := types.SetValueFrom(ctx, types.StringType, actual)
+	diags.Append(d...)
+	data.UserNames = userNames
+}
+
+func teamMemberUserNames(members []hubclient.OrgTeamMember) []string {
+	userNames := make([]string, 0, len(members))
+	for _, member := range members {
+		userNames = append(userNames, member.Username)
+	}
+	return userNames
+}
+
+// diffTeamMembers returns the users in desired but not in current, and the
+// users in current but not in desired. Docker Hub user names are case
+// insensitive.
+func diffTeamMembers(current, desired []string) (toAdd, toRemove []string) {
+	currentSet := make(map[string]bool, len(current))
+	for _, userName := range current {
+		currentSet[strings.ToLower(userName)] = true
+	}
+	desiredSet := make(map[string]bool, len(desired))
+	for _, userName := range desired {
+		desiredSet[strings.ToLower(userName)] = true
+		if !currentSet[strings.ToLower(userName)] {
+			toAdd = append(toAdd, userName)
+		}
+	}
+	for _, userName := range current {
+		if !desiredSet[strings.ToLower(userName)] {
+			toRemove = append(toRemove, userName)
+		}
+	}
+	sort.Strings(toAdd)
+	sort.Strings(toRemove)
+	return toAdd, toRemove
+}
+
+// forEachLimit calls fn for each item, running at most limit calls at the
+// same time. It returns the errors of the failed calls by item.
+func forEachLimit(items []string, limit int, fn func(item string) error) map[string]error {
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		failed = map[string]error{}
+		sem    = make(chan struct{}, limit)
+	)
+	for _, item := range items {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(item string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := fn(item); err != nil {
+				mu.Lock()
+				failed[item] = err
+				mu.Unlock()
+			}
+		}(item)
+	}
+	wg.Wait()
+	return failed
+}
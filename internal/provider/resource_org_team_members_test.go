@@ -0,0 +1,94 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package provider
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/docker/terraform-provider-docker/internal/envvar"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// By design, this test requires the user "nick20241127" to already be a member
+// of the organization.
+func TestAccOrgTeamMembers(t *testing.T) {
+	orgName := envvar.GetWithDefault(envvar.AccTestOrganization)
+	teamName := fmt.Sprintf("test%s", randString(5))
+	userName := os.Getenv("DOCKER_USERNAME")
+	otherUserName := "nick20241127"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// create
+				Config: testAccOrgTeamMembersConfig(orgName, teamName, userName, otherUserName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("docker_org_team_members.test", "id", orgName+"/"+teamName),
+					resource.TestCheckResourceAttr("docker_org_team_members.test", "user_names.#", "2"),
+					resource.TestCheckTypeSetElemAttr("docker_org_team_members.test", "user_names.*", userName),
+					resource.TestCheckTypeSetElemAttr("docker_org_team_members.test", "user_names.*", otherUserName),
+				),
+			},
+			{
+				// import
+				ResourceName:      "docker_org_team_members.test",
+				ImportState:       true,
+				ImportStateId:     orgName + "/" + teamName,
+				ImportStateVerify: true,
+			},
+			{
+				// remove a member
+				Config: testAccOrgTeamMembersConfig(orgName, teamName, userName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("docker_org_team_members.test", "user_names.#", "1"),
+					resource.TestCheckTypeSetElemAttr("docker_org_team_members.test", "user_names.*", userName),
+				),
+			},
+			{
+				// remove all members
+				Config: testAccOrgTeamMembersConfig(orgName, teamName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("docker_org_team_members.test", "user_names.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func testAccOrgTeamMembersConfig(orgName, teamName string, userNames ...string) string {
+	quoted := make([]string, 0, len(userNames))
+	for _, userName := range userNames {
+		quoted = append(quoted, fmt.Sprintf("%q", userName))
+	}
+	return fmt.Sprintf(`
+resource "docker_org_team" "test" {
+  org_name   = "%[1]s"
+  team_name  = "%[2]s"
+}
+
+resource "docker_org_team_members" "test" {
+  org_name   = docker_org_team.test.org_name
+  team_name  = docker_org_team.test.team_name
+  user_names = [%[3]s]
+}
+`, orgName, teamName, strings.Join(quoted, ", "))
+}
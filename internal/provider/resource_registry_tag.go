@@ -0,0 +1,397 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/docker/terraform-provider-docker/internal/hubclient"
+	"github.com/docker/terraform-provider-docker/internal/imageref"
+	"github.com/docker/terraform-provider-docker/internal/registryclient"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource                   = &RegistryTagResource{}
+	_ resource.ResourceWithConfigure      = &RegistryTagResource{}
+	_ resource.ResourceWithImportState    = &RegistryTagResource{}
+	_ resource.ResourceWithModifyPlan     = &RegistryTagResource{}
+	_ resource.ResourceWithValidateConfig = &RegistryTagResource{}
+)
+
+func NewRegistryTagResource() resource.Resource {
+	return &RegistryTagResource{}
+}
+
+type RegistryTagResource struct {
+	client   *hubclient.Client
+	registry *registryclient.Client
+}
+
+type RegistryTagResourceModel struct {
+	ID              types.String `tfsdk:"id"`
+	SourceImage     types.String `tfsdk:"source_image"`
+	TargetImage     types.String `tfsdk:"target_image"`
+	Digest          types.String `tfsdk:"digest"`
+	DeleteOnDestroy types.Bool   `tfsdk:"delete_on_destroy"`
+}
+
+func (r *RegistryTagResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *provider.ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = providerData.Hub
+	r.registry = providerData.Registry
+}
+
+func (r *RegistryTagResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_registry_tag"
+}
+
+func (r *RegistryTagResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: `Tags an image in a repository by copying it from another repository, for example to promote a build to a release over the OCI distribution API.
+
+The manifest and everything it references are copied. Between repositories of the same registry, layers are mounted instead of copied, so promoting an image does not transfer any layer data. Content already in the target repository is not copied again.
+
+The resource tracks the digest of the tag: if the source moves to another image, or the target tag is pushed to outside of Terraform, the next plan copies the source again.
+
+Credentials are read from the Docker config file (` + "`docker login`" + `). For Docker Hub, the provider authenticates as the same user as for the Docker Hub API when it manages ` + "`hub.docker.com`" + `, whichever auth source that user came from.
+
+## Example Usage
+
+` + "```hcl" + `
+resource "docker_registry_tag" "release" {
+  source_image = "my-organization/app-staging:${var.git_sha}"
+  target_image = "my-organization/app:1.2.3"
+}
+
+output "release_image" {
+  value = "my-organization/app@${docker_registry_tag.release.digest}"
+}
+` + "```" + `
+
+## Import
+
+Tags can be imported with the target image. The source image is then set to the target image pinned to its digest:
+
+` + "```shell" + `
+terraform import docker_registry_tag.release my-organization/app:1.2.3
+` + "```" + `
+`,
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The fully qualified target image",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"source_image": schema.StringAttribute{
+				MarkdownDescription: "Image to copy, such as `my-organization/app-staging:abc123` or an image pinned to a digest",
+				Required:            true,
+			},
+			"target_image": schema.StringAttribute{
+				MarkdownDescription: "Image to create, with the tag to create, such as `my-organization/app:1.2.3`. It may be on another registry than the source.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"digest": schema.StringAttribute{
+				MarkdownDescription: "Digest of the manifest the target tag points to",
+				Computed:            true,
+			},
+			"delete_on_destroy": schema.BoolAttribute{
+				MarkdownDescription: "Delete the target tag when the resource is destroyed, if it still points to `digest`. Defaults to `false`, which leaves the tag in place. The image itself is never deleted.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+		},
+	}
+}
+
+func (r *RegistryTagResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data RegistryTagResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.SourceImage.IsNull() && !data.SourceImage.IsUnknown() {
+		if _, err := imageref.Parse(data.SourceImage.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("source_image"), "Invalid Image Reference", err.Error())
+		}
+	}
+	if !data.TargetImage.IsNull() && !data.TargetImage.IsUnknown() {
+		ref, err := imageref.Parse(data.TargetImage.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("target_image"), "Invalid Image Reference", err.Error())
+		} else if ref.Digest != "" {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("target_image"),
+				"Invalid Image Reference",
+				fmt.Sprintf("The target image must be a tag, got the digest %s.", ref.Digest),
+			)
+		}
+	}
+}
+
+// ModifyPlan plans a new copy when the source no longer points to the digest
+// of the target tag.
+func (r *RegistryTagResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		return
+	}
+
+	var plan, state RegistryTagResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() || plan.SourceImage.IsUnknown() || r.registry == nil {
+		return
+	}
+
+	source, err := registryImage(plan.SourceImage.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("source_image"), "Invalid Image Reference", err.Error())
+		return
+	}
+	sourceDigest := source.Reference
+	if ref, _ := imageref.Parse(plan.SourceImage.ValueString()); ref.Digest == "" {
+		desc, err := r.registry.HeadManifest(ctx, source.Host, source.Repository, source.Reference)
+		if err != nil {
+			resp.Diagnostics.AddError("Registry API error reading source image", fmt.Sprintf("Could not resolve %s, unexpected error: %s", source, err))
+			return
+		}
+		sourceDigest = desc.Digest
+	}
+
+	if sourceDigest == state.Digest.ValueString() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("digest"), state.Digest)...)
+	} else {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("digest"), types.StringUnknown())...)
+	}
+}
+
+func (r *RegistryTagResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data RegistryTagResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.copy(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RegistryTagResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data RegistryTagResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	target, err := registryImage(data.TargetImage.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("target_image"), "Invalid Image Reference", err.Error())
+		return
+	}
+
+	desc, err := r.registry.HeadManifest(ctx, target.Host, target.Repository, target.Reference)
+	// Treat HTTP 404 Not Found status as a signal to recreate resource and return early
+	if registryclient.IsNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	} else if err != nil {
+		resp.Diagnostics.AddError("Unable to read registry_tag resource", err.Error())
+		return
+	}
+
+	data.ID = types.StringValue(targetImageID(data.TargetImage.ValueString()))
+	data.Digest = types.StringValue(desc.Digest)
+	if data.SourceImage.IsNull() {
+		// Imported, the tag is its own source.
+		ref, _ := imageref.Parse(data.TargetImage.ValueString())
+		data.SourceImage = types.StringValue(ref.Name() + "@" + desc.Digest)
+	}
+	if data.DeleteOnDestroy.IsNull() {
+		data.DeleteOnDestroy = types.BoolValue(false)
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RegistryTagResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data RegistryTagResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.copy(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RegistryTagResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data RegistryTagResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() || !data.DeleteOnDestroy.ValueBool() {
+		return
+	}
+
+	target, err := registryImage(data.TargetImage.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("target_image"), "Invalid Image Reference", err.Error())
+		return
+	}
+
+	desc, err := r.registry.HeadManifest(ctx, target.Host, target.Repository, target.Reference)
+	if registryclient.IsNotFound(err) {
+		return
+	} else if err != nil {
+		resp.Diagnostics.AddError("Unable to delete registry_tag resource", err.Error())
+		return
+	}
+	if desc.Digest != data.Digest.ValueString() {
+		resp.Diagnostics.AddWarning(
+			"Tag Not Deleted",
+			fmt.Sprintf("%s was pushed to outside of Terraform and now points to %s, it was left in place.", target, desc.Digest),
+		)
+		return
+	}
+
+	if target.Host == registryclient.DockerHubRegistry {
+		// Docker Hub does not support deleting tags over the distribution
+		// API, only over the Hub API.
+		ref, _ := imageref.Parse(data.TargetImage.ValueString())
+		err = r.client.DeleteRepositoryTag(ctx, ref.Namespace, ref.Repository, ref.Tag)
+		if hubclient.IsNotFound(err) {
+			err = nil
+		}
+	} else {
+		err = r.registry.DeleteManifest(ctx, target.Host, target.Repository, target.Reference)
+		if registryclient.IsNotFound(err) {
+			err = nil
+		}
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to delete registry_tag resource", err.Error())
+		return
+	}
+}
+
+func (r *RegistryTagResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	ref, err := imageref.Parse(req.ID)
+	if err != nil || ref.Digest != "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected import identifier to be an image with a tag, such as my-organization/app:1.2.3. Got: %q", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("target_image"), req.ID)...)
+}
+
+// copy copies the source image to the target tag and records the digest.
+func (r *RegistryTagResource) copy(ctx context.Context, data *RegistryTagResourceModel) (diags diag.Diagnostics) {
+	source, err := registryImage(data.SourceImage.ValueString())
+	if err != nil {
+		diags.AddAttributeError(path.Root("source_image"), "Invalid Image Reference", err.Error())
+		return diags
+	}
+	target, err := registryImage(data.TargetImage.ValueString())
+	if err != nil {
+		diags.AddAttributeError(path.Root("target_image"), "Invalid Image Reference", err.Error())
+		return diags
+	}
+
+	desc, err := r.registry.CopyImage(ctx, source, target)
+	if err != nil {
+		diags.AddError("Registry API error copying image", fmt.Sprintf("Could not copy %s to %s, unexpected error: %s", source, target, err))
+		return diags
+	}
+
+	data.ID = types.StringValue(targetImageID(data.TargetImage.ValueString()))
+	data.Digest = types.StringValue(desc.Digest)
+	return diags
+}
+
+// registryImage parses an image reference into the image to ask the
+// registry for.
+func registryImage(image string) (registryclient.Image, error) {
+	ref, err := imageref.Parse(image)
+	if err != nil {
+		return registryclient.Image{}, err
+	}
+	reference := ref.Tag
+	if ref.Digest != "" {
+		reference = ref.Digest
+	}
+	return registryclient.Image{
+		Host:       registryclient.RegistryHost(ref.Registry),
+		Repository: ref.Path(),
+		Reference:  reference,
+	}, nil
+}
+
+// targetImageID returns the fully qualified target image.
+func targetImageID(image string) string {
+	ref, err := imageref.Parse(image)
+	if err != nil {
+		return image
+	}
+	return ref.String()
+}
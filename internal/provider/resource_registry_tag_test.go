@@ -0,0 +1,91 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/docker/terraform-provider-docker/internal/envvar"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccRegistryTag(t *testing.T) {
+	registry := envvar.GetWithDefault(envvar.AccTestRegistry)
+	orgName := envvar.GetWithDefault(envvar.AccTestOrganization)
+	repoName := "test" + randString(10)
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRegistryTag(registry, orgName, repoName, "library/hello-world:latest"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("docker_registry_tag.test", "id", fmt.Sprintf("%s/%s/%s:promoted", registry, orgName, repoName)),
+					resource.TestMatchResourceAttr("docker_registry_tag.test", "digest", regexp.MustCompile(`^sha256:[0-9a-f]{64}$`)),
+					resource.TestCheckResourceAttrPair("docker_registry_tag.test", "digest", "data.docker_registry_image_manifest.source", "digest"),
+					resource.TestCheckResourceAttr("docker_registry_tag.test", "delete_on_destroy", "true"),
+				),
+			},
+			{
+				ResourceName:            "docker_registry_tag.test",
+				ImportState:             true,
+				ImportStateId:           fmt.Sprintf("%s/%s/%s:promoted", registry, orgName, repoName),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"source_image", "delete_on_destroy"},
+			},
+			{
+				// Pinning the source to the digest it already points to is a
+				// no-op copy.
+				Config: testAccRegistryTag(registry, orgName, repoName, "library/hello-world@${data.docker_registry_image_manifest.source.digest}"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("docker_registry_tag.test", "digest", "data.docker_registry_image_manifest.source", "digest"),
+				),
+			},
+			{
+				Config: `
+resource "docker_registry_tag" "test" {
+  source_image = "library/hello-world:latest"
+  target_image = "library/hello-world@sha256:0000000000000000000000000000000000000000000000000000000000000000"
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("The target image must be a tag"),
+			},
+		},
+	})
+}
+
+func testAccRegistryTag(registry, orgName, repoName, source string) string {
+	return fmt.Sprintf(`
+resource "docker_hub_repository" "test" {
+  namespace = "%[2]s"
+  name      = "%[3]s"
+}
+
+data "docker_registry_image_manifest" "source" {
+  image = "%[1]s/library/hello-world:latest"
+}
+
+resource "docker_registry_tag" "test" {
+  source_image      = "%[1]s/%[4]s"
+  target_image      = "%[1]s/${docker_hub_repository.test.id}:promoted"
+  delete_on_destroy = true
+}
+`, registry, orgName, repoName, source)
+}
@@ -20,11 +20,11 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"regexp"
 	"strings"
 
 	"github.com/docker/terraform-provider-docker/internal/hubclient"
 	"github.com/docker/terraform-provider-docker/internal/repositoryutils"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -91,12 +91,15 @@ func immutableTagsSettingsSchema() schema.SingleNestedAttribute {
 				Optional:            true,
 			},
 			"rules": schema.ListAttribute{
-				MarkdownDescription: "List of immutable tag rules for the repository",
+				MarkdownDescription: "List of immutable tag rules for the repository. Each rule is a regular expression (RE2 syntax) that must match the whole tag, such as `v[0-9]+\\..*` or `latest`. Use the `provider::docker::immutable_tag_matches` function to check which tags a set of rules protects.",
 				Required:            false,
 				Optional:            true,
 				Computed:            true,
 				ElementType:         types.StringType,
 				Default:             listdefault.StaticValue(types.ListValueMust(types.StringType, nil)),
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(immutableTagRuleValidator),
+				},
 			},
 		},
 	}
@@ -109,7 +112,7 @@ func (r *RepositoryResource) Configure(_ context.Context, req resource.Configure
 		return
 	}
 
-	client, ok := req.ProviderData.(*hubclient.Client)
+	providerData, ok := req.ProviderData.(*ProviderData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
@@ -120,7 +123,7 @@ func (r *RepositoryResource) Configure(_ context.Context, req resource.Configure
 		return
 	}
 
-	r.client = client
+	r.client = providerData.Hub
 }
 
 // Create implements resource.Resource.
@@ -209,7 +212,9 @@ func (r *RepositoryResource) Delete(ctx context.Context, req resource.DeleteRequ
 	}
 
 	err := r.client.DeleteRepository(ctx, state.ID.ValueString())
-	if err != nil {
+	if hubclient.IsNotFound(err) {
+		return
+	} else if err != nil {
 		log.Printf("Failed to delete repository with ID: %s, error: %v", state.ID.ValueString(), err)
 		resp.Diagnostics.AddError("Docker Hub API error deleting repository", "Could not delete repository, unexpected error: "+err.Error())
 		return
@@ -231,7 +236,11 @@ func (r *RepositoryResource) Read(ctx context.Context, req resource.ReadRequest,
 	}
 
 	getResp, err := r.client.GetRepository(ctx, state.ID.ValueString())
-	if err != nil {
+	// Treat HTTP 404 Not Found status as a signal to recreate resource and return early
+	if hubclient.IsNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	} else if err != nil {
 		resp.Diagnostics.AddError(
 			"Error reading Docker Hub repository",
 			"Could not read Docker Hub repository "+state.ID.ValueString()+": "+err.Error(),
@@ -320,7 +329,7 @@ terraform import docker_hub_repository.docker-repo docker-namespace/docker-repo
 				},
 				Validators: []validator.String{
 					stringvalidator.RegexMatches(
-						regexp.MustCompile(`^[a-z0-9]+(?:[._-][a-z0-9]+)*$`),
+						repositoryNameRegexp,
 						"Name must only contain lowercase alphanumeric characters, '.', or '-', and must start and end with a lowercase alphanumeric character",
 					),
 				},
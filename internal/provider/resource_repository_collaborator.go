@@ -0,0 +1,246 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/docker/terraform-provider-docker/internal/hubclient"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource                = &RepositoryCollaboratorResource{}
+	_ resource.ResourceWithConfigure   = &RepositoryCollaboratorResource{}
+	_ resource.ResourceWithImportState = &RepositoryCollaboratorResource{}
+)
+
+func NewRepositoryCollaboratorResource() resource.Resource {
+	return &RepositoryCollaboratorResource{}
+}
+
+type RepositoryCollaboratorResource struct {
+	client *hubclient.Client
+}
+
+type RepositoryCollaboratorResourceModel struct {
+	RepoID     types.String `tfsdk:"repo_id"`
+	UserName   types.String `tfsdk:"user_name"`
+	Permission types.String `tfsdk:"permission"`
+}
+
+func (r *RepositoryCollaboratorResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *provider.ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = providerData.Hub
+}
+
+func (r *RepositoryCollaboratorResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_hub_repository_collaborator"
+}
+
+func (r *RepositoryCollaboratorResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: `Grants a user direct access to an image repository as a collaborator.
+
+Collaborators are typically used for repositories in a personal namespace. For
+repositories owned by an organization, prefer ` + "`docker_hub_repository_team_permission`" + `.
+
+-> **Note** When used with a Personal Access Token authentication (PAT), the PAT should
+   have the "Read, Write, and Delete" scope to create and delete collaborators. The
+   owner of the PAT must be an admin of the repository.
+
+## Example Usage
+
+` + "```hcl" + `
+resource "docker_hub_repository" "my_repo" {
+  namespace        = "my-namespace"
+  name             = "my-repo"
+}
+
+resource "docker_hub_repository_collaborator" "my_repo" {
+  repo_id    = docker_hub_repository.my_repo.id
+  user_name  = "my-friend"
+  permission = "write"
+}
+` + "```" + `
+
+## Import
+
+Collaborators can be imported with the repository namespace, repository name and user name:
+
+` + "```shell" + `
+terraform import docker_hub_repository_collaborator.my_repo my-namespace/my-repo/my-friend
+` + "```" + `
+`,
+
+		Attributes: map[string]schema.Attribute{
+			"repo_id": schema.StringAttribute{
+				MarkdownDescription: "The namespace/name of the repository",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"user_name": schema.StringAttribute{
+				MarkdownDescription: "The Docker Hub username of the collaborator",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"permission": schema.StringAttribute{
+				MarkdownDescription: "The permission to assign to the user on the repository.",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(
+						hubclient.TeamRepoPermissionLevelRead,
+						hubclient.TeamRepoPermissionLevelWrite,
+						hubclient.TeamRepoPermissionLevelAdmin,
+					),
+				},
+			},
+		},
+	}
+}
+
+func (r *RepositoryCollaboratorResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data RepositoryCollaboratorResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	collaborator, err := r.client.CreateRepositoryCollaborator(
+		ctx,
+		data.RepoID.ValueString(),
+		data.UserName.ValueString(),
+		data.Permission.ValueString(),
+	)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to create repository_collaborator resource", err.Error())
+		return
+	}
+
+	data.Permission = types.StringValue(collaborator.Permission)
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RepositoryCollaboratorResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data RepositoryCollaboratorResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	collaborator, err := r.client.GetRepositoryCollaborator(
+		ctx,
+		data.RepoID.ValueString(),
+		data.UserName.ValueString(),
+	)
+	// Treat HTTP 404 Not Found status as a signal to recreate resource and return early
+	if hubclient.IsNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	} else if err != nil {
+		resp.Diagnostics.AddError("Unable to read repository_collaborator resource", err.Error())
+		return
+	}
+
+	data.Permission = types.StringValue(collaborator.Permission)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RepositoryCollaboratorResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data RepositoryCollaboratorResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	collaborator, err := r.client.UpdateRepositoryCollaborator(
+		ctx,
+		data.RepoID.ValueString(),
+		data.UserName.ValueString(),
+		data.Permission.ValueString(),
+	)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to update repository_collaborator resource", err.Error())
+		return
+	}
+
+	data.Permission = types.StringValue(collaborator.Permission)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RepositoryCollaboratorResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data RepositoryCollaboratorResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	err := r.client.DeleteRepositoryCollaborator(
+		ctx,
+		data.RepoID.ValueString(),
+		data.UserName.ValueString(),
+	)
+	if hubclient.IsNotFound(err) {
+		return
+	} else if err != nil {
+		resp.Diagnostics.AddError("Unable to delete repository_collaborator resource", err.Error())
+		return
+	}
+}
+
+func (r *RepositoryCollaboratorResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	idParts := strings.Split(req.ID, "/")
+
+	if len(idParts) != 3 || idParts[0] == "" || idParts[1] == "" || idParts[2] == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected import identifier with format: repo_namespace/repo_name/user_name. Got: %q", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("repo_id"), idParts[0]+"/"+idParts[1])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("user_name"), idParts[2])...)
+}
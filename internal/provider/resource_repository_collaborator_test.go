@@ -0,0 +1,87 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/docker/terraform-provider-docker/internal/envvar"
+	"github.com/docker/terraform-provider-docker/internal/hubclient"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// By design, this test requires the user "nick20241127" to exist.
+func TestAccRepositoryCollaborator(t *testing.T) {
+	orgName := envvar.GetWithDefault(envvar.AccTestOrganization)
+	repoName := "test" + randString(10)
+	userName := "nick20241127"
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// create
+				Config: testAccRepositoryCollaborator(orgName, repoName, userName, hubclient.TeamRepoPermissionLevelRead),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("docker_hub_repository_collaborator.test", "repo_id", "docker_hub_repository.test", "id"),
+					resource.TestCheckResourceAttr("docker_hub_repository_collaborator.test", "user_name", userName),
+					resource.TestCheckResourceAttr("docker_hub_repository_collaborator.test", "permission", hubclient.TeamRepoPermissionLevelRead),
+				),
+			},
+			{
+				// import
+				ResourceName:                         "docker_hub_repository_collaborator.test",
+				ImportState:                          true,
+				ImportStateId:                        orgName + "/" + repoName + "/" + userName,
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "user_name",
+			},
+			{
+				// update permission
+				Config: testAccRepositoryCollaborator(orgName, repoName, userName, hubclient.TeamRepoPermissionLevelWrite),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("docker_hub_repository_collaborator.test", "permission", hubclient.TeamRepoPermissionLevelWrite),
+				),
+			},
+			{
+				// delete
+				Config: testAccRepositoryCollaboratorBase(orgName, repoName),
+			},
+		},
+	})
+}
+
+func testAccRepositoryCollaboratorBase(orgName, repoName string) string {
+	return fmt.Sprintf(`
+resource "docker_hub_repository" "test" {
+  namespace = "%[1]s"
+  name      = "%[2]s"
+}`, orgName, repoName)
+}
+
+func testAccRepositoryCollaborator(orgName, repoName, userName string, permission hubclient.TeamRepoPermissionLevel) string {
+	return fmt.Sprintf(`
+%[1]s
+
+resource "docker_hub_repository_collaborator" "test" {
+  repo_id    = docker_hub_repository.test.id
+  user_name  = "%[2]s"
+  permission = "%[3]s"
+}
+`, testAccRepositoryCollaboratorBase(orgName, repoName), userName, permission)
+}
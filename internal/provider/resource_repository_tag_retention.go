@@ -0,0 +1,461 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/docker/terraform-provider-docker/internal/hubclient"
+	"github.com/docker/terraform-provider-docker/internal/repositoryutils"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource                     = &RepositoryTagRetentionResource{}
+	_ resource.ResourceWithConfigure        = &RepositoryTagRetentionResource{}
+	_ resource.ResourceWithConfigValidators = &RepositoryTagRetentionResource{}
+	_ resource.ResourceWithModifyPlan       = &RepositoryTagRetentionResource{}
+)
+
+// maxPreviewTags is the number of tags listed in the plan warnings.
+const maxPreviewTags = 20
+
+func NewRepositoryTagRetentionResource() resource.Resource {
+	return &RepositoryTagRetentionResource{}
+}
+
+type RepositoryTagRetentionResource struct {
+	client *hubclient.Client
+}
+
+type RepositoryTagRetentionResourceModel struct {
+	ID              types.String `tfsdk:"id"`
+	RepoID          types.String `tfsdk:"repo_id"`
+	KeepLast        types.Int64  `tfsdk:"keep_last"`
+	NotPulledInDays types.Int64  `tfsdk:"not_pulled_in_days"`
+	Include         types.List   `tfsdk:"include"`
+	Exclude         types.List   `tfsdk:"exclude"`
+	DryRun          types.Bool   `tfsdk:"dry_run"`
+	TagsToDelete    types.List   `tfsdk:"tags_to_delete"`
+	DeletedTags     types.List   `tfsdk:"deleted_tags"`
+}
+
+func (r *RepositoryTagRetentionResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *provider.ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = providerData.Hub
+}
+
+func (r *RepositoryTagRetentionResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_hub_repository_tag_retention"
+}
+
+func (r *RepositoryTagRetentionResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: `Deletes the tags of an image repository that fall outside a retention policy.
+
+The policy is evaluated on every plan. Tags that would be deleted are listed in
+a warning and in ` + "`tags_to_delete`" + `, and are deleted when the plan is applied.
+Applying a plan only deletes the tags it lists, and skips those the policy no
+longer selects by then, for example because they were pulled in the meantime.
+Set ` + "`dry_run`" + ` to only preview the tags the policy selects.
+
+When the policy cannot be evaluated at plan time, for example because the
+repository is created in the same apply, the apply only fills in
+` + "`tags_to_delete`" + `, and the next plan deletes them.
+
+A tag is deleted when all of the following are true:
+
+- It matches one of the ` + "`include`" + ` patterns, if any, and none of the ` + "`exclude`" + ` patterns.
+- It is not protected by the immutable tag rules of the repository.
+- It is not one of the ` + "`keep_last`" + ` most recently pushed tags, if set.
+- It has not been pulled in the last ` + "`not_pulled_in_days`" + ` days, if set. Tags that were never pulled count from their last push.
+
+Patterns are RE2 regular expressions that must match the whole tag, like immutable tag rules.
+
+~> **Warning** Deleted tags cannot be restored. Destroying this resource stops
+   applying the policy but does not restore any tag.
+
+-> **Note** When used with a Personal Access Token authentication (PAT), the PAT should
+   have the "Read, Write, and Delete" scope to delete tags.
+
+## Example Usage
+
+` + "```hcl" + `
+resource "docker_hub_repository_tag_retention" "example" {
+  repo_id            = docker_hub_repository.example.id
+  keep_last          = 10
+  not_pulled_in_days = 90
+  include            = ["pr-.*", "sha-[0-9a-f]+"]
+  exclude            = ["latest"]
+}
+` + "```" + `
+`,
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The namespace/name of the repository",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"repo_id": schema.StringAttribute{
+				MarkdownDescription: "The namespace/name of the repository",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"keep_last": schema.Int64Attribute{
+				MarkdownDescription: "Number of most recently pushed tags to keep, by `tag_last_pushed`. Every tag has to be read to rank them, so the policy fails for repositories with more tags than `max_page_results` allows to read.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"not_pulled_in_days": schema.Int64Attribute{
+				MarkdownDescription: "Delete tags that have not been pulled in this many days",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"include": schema.ListAttribute{
+				MarkdownDescription: "Only apply the policy to tags matching one of these patterns. Defaults to all tags.",
+				Optional:            true,
+				ElementType:         types.StringType,
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(tagPatternValidator),
+				},
+			},
+			"exclude": schema.ListAttribute{
+				MarkdownDescription: "Never delete tags matching one of these patterns",
+				Optional:            true,
+				ElementType:         types.StringType,
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(tagPatternValidator),
+				},
+			},
+			"dry_run": schema.BoolAttribute{
+				MarkdownDescription: "Only preview the tags to delete, without deleting them. Defaults to `false`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"tags_to_delete": schema.ListAttribute{
+				MarkdownDescription: "The tags the policy selected in the plan. Without `dry_run`, the value from the last plan that selected any tags is kept until the policy selects new ones.",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"deleted_tags": schema.ListAttribute{
+				MarkdownDescription: "The tags deleted by the last apply",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+		},
+	}
+}
+
+func (r *RepositoryTagRetentionResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.AtLeastOneOf(
+			path.MatchRoot("keep_last"),
+			path.MatchRoot("not_pulled_in_days"),
+		),
+	}
+}
+
+func (r *RepositoryTagRetentionResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to preview on destroy, or before the provider is configured.
+	if req.Plan.Raw.IsNull() || r.client == nil {
+		return
+	}
+
+	var plan RepositoryTagRetentionResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tagsToDelete := types.ListValueMust(types.StringType, nil)
+	deletedTags := types.ListValueMust(types.StringType, nil)
+	if !req.State.Raw.IsNull() {
+		var state RepositoryTagRetentionResourceModel
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		tagsToDelete = state.TagsToDelete
+		deletedTags = state.DeletedTags
+	}
+
+	// The policy cannot be evaluated until the whole configuration is known,
+	// for example when the repository is created in the same apply.
+	if plan.RepoID.IsUnknown() || plan.KeepLast.IsUnknown() || plan.NotPulledInDays.IsUnknown() ||
+		plan.Include.IsUnknown() || plan.Exclude.IsUnknown() || plan.DryRun.IsUnknown() {
+		plan.TagsToDelete = types.ListUnknown(types.StringType)
+		plan.DeletedTags = types.ListUnknown(types.StringType)
+		resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+		return
+	}
+
+	selected, err := r.selectTags(ctx, &plan)
+	if hubclient.IsNotFound(err) {
+		selected = []string{}
+	} else if err != nil {
+		resp.Diagnostics.AddError("Unable to evaluate tag retention policy", err.Error())
+		return
+	}
+
+	if plan.DryRun.ValueBool() {
+		tagsToDelete, diags := types.ListValueFrom(ctx, types.StringType, selected)
+		resp.Diagnostics.Append(diags...)
+		plan.TagsToDelete = tagsToDelete
+		plan.DeletedTags = deletedTags
+		if len(selected) > 0 {
+			resp.Diagnostics.AddWarning(
+				"Tag retention dry run",
+				fmt.Sprintf("The retention policy of %s would delete %d tags: %s", plan.RepoID.ValueString(), len(selected), previewTags(selected)),
+			)
+		}
+	} else if len(selected) == 0 {
+		// Keep the tags listed by the last plan that selected any, so that
+		// the plan that follows a deletion is empty.
+		plan.TagsToDelete = tagsToDelete
+		plan.DeletedTags = deletedTags
+	} else {
+		// The apply deletes the tags listed here, and only when the deleted
+		// tags are unknown.
+		listed, diags := types.ListValueFrom(ctx, types.StringType, selected)
+		resp.Diagnostics.Append(diags...)
+		plan.TagsToDelete = listed
+		plan.DeletedTags = types.ListUnknown(types.StringType)
+		resp.Diagnostics.AddWarning(
+			"Tags will be deleted",
+			fmt.Sprintf("The retention policy of %s will delete %d tags: %s", plan.RepoID.ValueString(), len(selected), previewTags(selected)),
+		)
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+func (r *RepositoryTagRetentionResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data RepositoryTagRetentionResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.ID = data.RepoID
+	r.apply(ctx, &data, &resp.Diagnostics)
+
+	// Save data into Terraform state, even on partial failure, so that the
+	// tags that were deleted are recorded.
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RepositoryTagRetentionResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data RepositoryTagRetentionResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	_, err := r.client.GetRepository(ctx, data.RepoID.ValueString())
+	// Treat HTTP 404 Not Found status as a signal to recreate resource and return early
+	if hubclient.IsNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	} else if err != nil {
+		resp.Diagnostics.AddError("Unable to read repository_tag_retention resource", err.Error())
+		return
+	}
+
+	// The tags to delete are evaluated at plan time, not on refresh, so that
+	// pending deletions show up as a change.
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RepositoryTagRetentionResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data RepositoryTagRetentionResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.apply(ctx, &data, &resp.Diagnostics)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RepositoryTagRetentionResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Deleted tags cannot be restored, so there is nothing to undo.
+}
+
+// apply deletes the tags listed in the plan that the policy still selects,
+// unless dry_run is set or the plan did not expect any deletion.
+func (r *RepositoryTagRetentionResource) apply(ctx context.Context, data *RepositoryTagRetentionResourceModel, diags *diag.Diagnostics) {
+	if data.TagsToDelete.IsUnknown() {
+		// The policy could not be evaluated at plan time. Only list what it
+		// selects, the next plan deletes the tags after showing them.
+		selected, err := r.selectTags(ctx, data)
+		if err != nil {
+			diags.AddError("Unable to evaluate tag retention policy", err.Error())
+			selected = []string{}
+		}
+		tagsToDelete, d := types.ListValueFrom(ctx, types.StringType, selected)
+		diags.Append(d...)
+		data.TagsToDelete = tagsToDelete
+		if data.DeletedTags.IsUnknown() {
+			data.DeletedTags = types.ListValueMust(types.StringType, nil)
+		}
+		return
+	}
+
+	if data.DryRun.ValueBool() || !data.DeletedTags.IsUnknown() {
+		// Nothing to delete, or the plan did not find anything to delete.
+		return
+	}
+
+	var planned []string
+	diags.Append(data.TagsToDelete.ElementsAs(ctx, &planned, false)...)
+	data.DeletedTags = types.ListValueMust(types.StringType, nil)
+	if diags.HasError() {
+		return
+	}
+
+	// Tags may have been pulled, pushed or protected since the plan was
+	// made, so check them against the policy again. Tags it selects now but
+	// were not in the plan are left for the next plan.
+	selected, err := r.selectTags(ctx, data)
+	if err != nil {
+		diags.AddError("Unable to evaluate tag retention policy", err.Error())
+		return
+	}
+	stillSelected := make(map[string]bool, len(selected))
+	for _, tag := range selected {
+		stillSelected[tag] = true
+	}
+
+	namespace, name := repositoryutils.SplitID(data.RepoID.ValueString())
+	deleted := []string{}
+	for _, tag := range planned {
+		if !stillSelected[tag] {
+			continue
+		}
+		err := r.client.DeleteRepositoryTag(ctx, namespace, name, tag)
+		if hubclient.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			diags.AddError("Unable to delete tag", fmt.Sprintf("Unable to delete tag %s of %s: %v", tag, data.RepoID.ValueString(), err))
+			continue
+		}
+		deleted = append(deleted, tag)
+	}
+
+	deletedTags, d := types.ListValueFrom(ctx, types.StringType, deleted)
+	diags.Append(d...)
+	data.DeletedTags = deletedTags
+}
+
+// selectTags evaluates the retention policy against the current tags of the
+// repository.
+func (r *RepositoryTagRetentionResource) selectTags(ctx context.Context, data *RepositoryTagRetentionResourceModel) ([]string, error) {
+	policy := tagRetentionPolicy{KeepLast: -1}
+	if !data.KeepLast.IsNull() {
+		policy.KeepLast = int(data.KeepLast.ValueInt64())
+	}
+	if !data.NotPulledInDays.IsNull() {
+		policy.NotPulledIn = time.Duration(data.NotPulledInDays.ValueInt64()) * 24 * time.Hour
+	}
+
+	var include, exclude []string
+	if diags := data.Include.ElementsAs(ctx, &include, false); diags.HasError() {
+		return nil, fmt.Errorf("invalid include patterns")
+	}
+	if diags := data.Exclude.ElementsAs(ctx, &exclude, false); diags.HasError() {
+		return nil, fmt.Errorf("invalid exclude patterns")
+	}
+	var err error
+	if policy.Include, err = compileTagPatterns(include); err != nil {
+		return nil, err
+	}
+	if policy.Exclude, err = compileTagPatterns(exclude); err != nil {
+		return nil, err
+	}
+
+	repo, err := r.client.GetRepository(ctx, data.RepoID.ValueString())
+	if err != nil {
+		return nil, err
+	}
+	policy.Immutable = repo.ImmutableTagsSettings
+
+	namespace, name := repositoryutils.SplitID(data.RepoID.ValueString())
+	tags, err := r.client.GetRepositoryTags(ctx, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	// keep_last ranks tags by tag_last_pushed, which Docker Hub cannot order
+	// by, so a partial listing could delete some of the most recent tags.
+	if tags.Truncated && policy.KeepLast >= 0 {
+		return nil, fmt.Errorf("repository %s has more tags than max_page_results allows to read, so keep_last cannot be applied. Raise max_page_results in the provider configuration, or set it to 0 to read every page", data.RepoID.ValueString())
+	}
+
+	return policy.SelectTags(tags.Results, time.Now())
+}
+
+// previewTags formats a list of tags for a plan warning.
+func previewTags(tags []string) string {
+	if len(tags) <= maxPreviewTags {
+		return strings.Join(tags, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(tags[:maxPreviewTags], ", "), len(tags)-maxPreviewTags)
+}
@@ -0,0 +1,119 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/docker/terraform-provider-docker/internal/envvar"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccRepositoryTagRetention(t *testing.T) {
+	orgName := envvar.GetWithDefault(envvar.AccTestOrganization)
+	repoName := "test" + randString(10)
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// An empty repository has nothing to delete.
+				Config: testAccRepositoryTagRetention(orgName, repoName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("docker_hub_repository_tag_retention.test", "id", "docker_hub_repository.test", "id"),
+					resource.TestCheckResourceAttr("docker_hub_repository_tag_retention.test", "dry_run", "false"),
+					resource.TestCheckResourceAttr("docker_hub_repository_tag_retention.test", "tags_to_delete.#", "0"),
+					resource.TestCheckResourceAttr("docker_hub_repository_tag_retention.test", "deleted_tags.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+// The dry run never deletes anything, so it is safe to run against a public
+// repository.
+func TestAccRepositoryTagRetentionDryRun(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "docker_hub_repository_tag_retention" "test" {
+  repo_id   = "library/hello-world"
+  keep_last = 0
+  include   = ["latest"]
+  dry_run   = true
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("docker_hub_repository_tag_retention.test", "tags_to_delete.#", "1"),
+					resource.TestCheckResourceAttr("docker_hub_repository_tag_retention.test", "tags_to_delete.0", "latest"),
+					resource.TestCheckResourceAttr("docker_hub_repository_tag_retention.test", "deleted_tags.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccRepositoryTagRetentionInvalid(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "docker_hub_repository_tag_retention" "test" {
+  repo_id = "library/hello-world"
+  include = ["latest"]
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`(?s)keep_last.*not_pulled_in_days`),
+			},
+			{
+				Config: `
+resource "docker_hub_repository_tag_retention" "test" {
+  repo_id   = "library/hello-world"
+  keep_last = 1
+  exclude   = ["v1.(0"]
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Invalid Tag Pattern`),
+			},
+		},
+	})
+}
+
+func testAccRepositoryTagRetention(orgName, repoName string) string {
+	return fmt.Sprintf(`
+resource "docker_hub_repository" "test" {
+  namespace = "%[1]s"
+  name      = "%[2]s"
+}
+
+resource "docker_hub_repository_tag_retention" "test" {
+  repo_id            = docker_hub_repository.test.id
+  keep_last          = 5
+  not_pulled_in_days = 30
+  exclude            = ["latest"]
+}
+`, orgName, repoName)
+}
@@ -59,7 +59,7 @@ func (r *RepositoryTeamPermissionResource) Configure(ctx context.Context, req re
 		return
 	}
 
-	client, ok := req.ProviderData.(*hubclient.Client)
+	providerData, ok := req.ProviderData.(*ProviderData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
@@ -70,7 +70,7 @@ func (r *RepositoryTeamPermissionResource) Configure(ctx context.Context, req re
 		return
 	}
 
-	r.client = client
+	r.client = providerData.Hub
 }
 
 func (r *RepositoryTeamPermissionResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -169,7 +169,7 @@ func (r *RepositoryTeamPermissionResource) Read(ctx context.Context, req resourc
 		data.TeamID.ValueInt64(),
 	)
 	// Treat HTTP 404 Not Found status as a signal to recreate resource and return early
-	if isNotFound(err) {
+	if hubclient.IsNotFound(err) {
 		resp.State.RemoveResource(ctx)
 		return
 	} else if err != nil {
@@ -214,7 +214,7 @@ func (r *RepositoryTeamPermissionResource) Delete(ctx context.Context, req resou
 		data.RepoID.ValueString(),
 		data.TeamID.ValueInt64(),
 	)
-	if isNotFound(err) {
+	if hubclient.IsNotFound(err) {
 		return
 	} else if err != nil {
 		resp.Diagnostics.AddError("Unable to delete repository_team_permission resource", err.Error())
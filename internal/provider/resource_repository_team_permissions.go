@@ -0,0 +1,358 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/docker/terraform-provider-docker/internal/hubclient"
+	"github.com/docker/terraform-provider-docker/internal/repositoryutils"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource                = &RepositoryTeamPermissionsResource{}
+	_ resource.ResourceWithConfigure   = &RepositoryTeamPermissionsResource{}
+	_ resource.ResourceWithImportState = &RepositoryTeamPermissionsResource{}
+)
+
+func NewRepositoryTeamPermissionsResource() resource.Resource {
+	return &RepositoryTeamPermissionsResource{}
+}
+
+type RepositoryTeamPermissionsResource struct {
+	client *hubclient.Client
+}
+
+type RepositoryTeamPermissionsResourceModel struct {
+	ID          types.String `tfsdk:"id"`
+	RepoID      types.String `tfsdk:"repo_id"`
+	Permissions types.Map    `tfsdk:"permissions"`
+}
+
+func (r *RepositoryTeamPermissionsResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *provider.ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = providerData.Hub
+}
+
+func (r *RepositoryTeamPermissionsResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_hub_repository_team_permissions"
+}
+
+func (r *RepositoryTeamPermissionsResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: `Manages the complete set of team permissions of an image repository.
+
+This resource is authoritative: permissions granted outside of Terraform, for
+example through the Docker Hub UI, show up as drift and are revoked on the next
+apply. Use ` + "`docker_hub_repository_team_permission`" + ` instead to grant
+individual permissions on a repository that is also managed elsewhere. Do not
+use both resources for the same repository.
+
+-> **Note** When used with a Personal Access Token authentication (PAT), the PAT should
+   have the "Read, Write, and Delete" scope to create and delete team permissions. The
+   owner of the PAT must be an editor of the org.
+
+-> **Note**: Team permissions are read using the provider's ` + "`max_page_results`" + ` limit. Reading a repository that grants more teams than that fails rather than leaving out the grants on the remaining pages, set ` + "`max_page_results`" + ` to 0 to read every page.
+
+## Example Usage
+
+` + "```hcl" + `
+resource "docker_hub_repository" "my_repo" {
+  namespace        = "my-org"
+  name             = "my-repo"
+}
+
+resource "docker_hub_repository_team_permissions" "my_repo" {
+  repo_id = docker_hub_repository.my_repo.id
+  permissions = {
+    developers = "write"
+    release    = "admin"
+    everyone   = "read"
+  }
+}
+` + "```" + `
+
+## Import
+
+Team permissions can be imported with the repository namespace and name:
+
+` + "```shell" + `
+terraform import docker_hub_repository_team_permissions.my_repo my-org/my-repo
+` + "```" + `
+`,
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The namespace/name of the repository",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"repo_id": schema.StringAttribute{
+				MarkdownDescription: "The namespace/name of the repository. The namespace must be an organization.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"permissions": schema.MapAttribute{
+				MarkdownDescription: "The permission of every team with access to the repository, by team name. Teams that are not listed have no access.",
+				Required:            true,
+				ElementType:         types.StringType,
+				Validators: []validator.Map{
+					mapvalidator.ValueStringsAre(
+						stringvalidator.OneOf(
+							hubclient.TeamRepoPermissionLevelRead,
+							hubclient.TeamRepoPermissionLevelWrite,
+							hubclient.TeamRepoPermissionLevelAdmin,
+						),
+					),
+				},
+			},
+		},
+	}
+}
+
+func (r *RepositoryTeamPermissionsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data RepositoryTeamPermissionsResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	current, err := r.client.ListPermissionsForRepo(ctx, data.RepoID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to read repository team permissions", listingError(err))
+		return
+	}
+
+	data.ID = data.RepoID
+	r.reconcile(ctx, &data, current.Results, &resp.Diagnostics)
+
+	// Save data into Terraform state, even on partial failure, so that the
+	// next plan shows the remaining difference.
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RepositoryTeamPermissionsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data RepositoryTeamPermissionsResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	current, err := r.client.ListPermissionsForRepo(ctx, data.RepoID.ValueString())
+	// Treat HTTP 404 Not Found status as a signal to recreate resource and return early
+	if hubclient.IsNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	} else if err != nil {
+		resp.Diagnostics.AddError("Unable to read repository_team_permissions resource", listingError(err))
+		return
+	}
+
+	permissions := make(map[string]string, len(current.Results))
+	for _, perm := range current.Results {
+		permissions[perm.TeamName] = perm.Permission
+	}
+	permissionsValue, diags := types.MapValueFrom(ctx, types.StringType, permissions)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.ID = data.RepoID
+	data.Permissions = permissionsValue
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RepositoryTeamPermissionsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data RepositoryTeamPermissionsResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	current, err := r.client.ListPermissionsForRepo(ctx, data.RepoID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to read repository team permissions", listingError(err))
+		return
+	}
+
+	r.reconcile(ctx, &data, current.Results, &resp.Diagnostics)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RepositoryTeamPermissionsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data RepositoryTeamPermissionsResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	current, err := r.client.ListPermissionsForRepo(ctx, data.RepoID.ValueString())
+	if hubclient.IsNotFound(err) {
+		return
+	} else if err != nil {
+		resp.Diagnostics.AddError("Unable to read repository team permissions", listingError(err))
+		return
+	}
+
+	// Revoke every permission, including those granted since the last refresh.
+	data.Permissions = types.MapValueMust(types.StringType, nil)
+	r.reconcile(ctx, &data, current.Results, &resp.Diagnostics)
+}
+
+func (r *RepositoryTeamPermissionsResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	namespace, name := repositoryutils.SplitID(req.ID)
+	if !validRepositoryIDPart(namespace) || !validRepositoryIDPart(name) {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected import identifier with format: repo_namespace/repo_name. Got: %q", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("repo_id"), req.ID)...)
+}
+
+// reconcile grants, updates and revokes team permissions until the
+// repository has exactly the permissions in data.Permissions. Callers pass
+// the current permissions as read from Docker Hub right before, so that
+// permissions granted since the last refresh are revoked too.
+//
+// On failure, data.Permissions is set to the permissions the repository ends
+// up with.
+func (r *RepositoryTeamPermissionsResource) reconcile(ctx context.Context, data *RepositoryTeamPermissionsResourceModel, current []hubclient.TeamRepoPermission, diags *diag.Diagnostics) {
+	repoID := data.RepoID.ValueString()
+	namespace, _ := repositoryutils.SplitID(repoID)
+
+	desired := map[string]string{}
+	diags.Append(data.Permissions.ElementsAs(ctx, &desired, false)...)
+	if diags.HasError() {
+		return
+	}
+
+	actual := make(map[string]string, len(current))
+	currentByTeam := make(map[string]hubclient.TeamRepoPermission, len(current))
+	for _, perm := range current {
+		actual[perm.TeamName] = perm.Permission
+		currentByTeam[perm.TeamName] = perm
+	}
+
+	failed := false
+
+	// Revoke first, to never grant more access than configured.
+	for _, teamName := range sortedMapKeys(currentByTeam) {
+		if _, ok := desired[teamName]; ok {
+			continue
+		}
+		err := r.client.DeletePermissionForTeamAndRepo(ctx, repoID, currentByTeam[teamName].TeamID)
+		if err != nil && !hubclient.IsNotFound(err) {
+			diags.AddError("Unable to revoke team permission", fmt.Sprintf("Unable to revoke the permission of team %s on %s: %v", teamName, repoID, err))
+			failed = true
+			continue
+		}
+		delete(actual, teamName)
+	}
+
+	for _, teamName := range sortedMapKeys(desired) {
+		permission := desired[teamName]
+		if perm, ok := currentByTeam[teamName]; ok {
+			if perm.Permission == permission {
+				continue
+			}
+			if _, err := r.client.UpdatePermissionForTeamAndRepo(ctx, repoID, perm.TeamID, permission); err != nil {
+				diags.AddError("Unable to update team permission", fmt.Sprintf("Unable to update the permission of team %s on %s: %v", teamName, repoID, err))
+				failed = true
+				continue
+			}
+			actual[teamName] = permission
+			continue
+		}
+
+		team, err := r.client.GetOrgTeam(ctx, namespace, teamName)
+		if err != nil {
+			diags.AddError("Unable to grant team permission", fmt.Sprintf("Unable to find team %s in organization %s: %v", teamName, namespace, err))
+			failed = true
+			continue
+		}
+		if _, err := r.client.CreatePermissionForTeamAndRepo(ctx, repoID, team.ID, permission); err != nil {
+			diags.AddError("Unable to grant team permission", fmt.Sprintf("Unable to grant team %s %s access to %s: %v", teamName, permission, repoID, err))
+			failed = true
+			continue
+		}
+		actual[teamName] = permission
+	}
+
+	if !failed {
+		return
+	}
+
+	// Record what actually happened, so that the next plan retries the
+	// failed changes.
+	permissions, d := types.MapValueFrom(ctx, types.StringType, actual)
+	diags.Append(d...)
+	data.Permissions = permissions
+}
+
+func sortedMapKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
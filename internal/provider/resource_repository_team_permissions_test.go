@@ -0,0 +1,99 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/docker/terraform-provider-docker/internal/envvar"
+	"github.com/docker/terraform-provider-docker/internal/hubclient"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccRepositoryTeamPermissions(t *testing.T) {
+	orgName := envvar.GetWithDefault(envvar.AccTestOrganization)
+	teamA := "test" + randString(10)
+	teamB := "test" + randString(10)
+	repoName := "test" + randString(10)
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// create
+				Config: testAccRepositoryTeamPermissions(orgName, teamA, teamB, repoName, `
+    (docker_org_team.a.team_name) = "read"
+    (docker_org_team.b.team_name) = "admin"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("docker_hub_repository_team_permissions.test", "id", "docker_hub_repository.test", "id"),
+					resource.TestCheckResourceAttr("docker_hub_repository_team_permissions.test", "permissions.%", "2"),
+					resource.TestCheckResourceAttr("docker_hub_repository_team_permissions.test", "permissions."+teamA, hubclient.TeamRepoPermissionLevelRead),
+					resource.TestCheckResourceAttr("docker_hub_repository_team_permissions.test", "permissions."+teamB, hubclient.TeamRepoPermissionLevelAdmin),
+				),
+			},
+			{
+				// import
+				ResourceName:      "docker_hub_repository_team_permissions.test",
+				ImportState:       true,
+				ImportStateId:     orgName + "/" + repoName,
+				ImportStateVerify: true,
+			},
+			{
+				// update one permission and revoke the other
+				Config: testAccRepositoryTeamPermissions(orgName, teamA, teamB, repoName, `
+    (docker_org_team.a.team_name) = "write"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("docker_hub_repository_team_permissions.test", "permissions.%", "1"),
+					resource.TestCheckResourceAttr("docker_hub_repository_team_permissions.test", "permissions."+teamA, hubclient.TeamRepoPermissionLevelWrite),
+				),
+			},
+			{
+				// revoke all permissions
+				Config: testAccRepositoryTeamPermissions(orgName, teamA, teamB, repoName, ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("docker_hub_repository_team_permissions.test", "permissions.%", "0"),
+				),
+			},
+		},
+	})
+}
+
+func testAccRepositoryTeamPermissions(orgName, teamA, teamB, repoName, permissions string) string {
+	return fmt.Sprintf(`
+resource "docker_org_team" "a" {
+  org_name  = "%[1]s"
+  team_name = "%[2]s"
+}
+
+resource "docker_org_team" "b" {
+  org_name  = "%[1]s"
+  team_name = "%[3]s"
+}
+
+resource "docker_hub_repository" "test" {
+  namespace = "%[1]s"
+  name      = "%[4]s"
+}
+
+resource "docker_hub_repository_team_permissions" "test" {
+  repo_id = docker_hub_repository.test.id
+  permissions = {%[5]s
+  }
+}
+`, orgName, teamA, teamB, repoName, permissions)
+}
@@ -18,6 +18,7 @@ package provider
 
 import (
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/docker/terraform-provider-docker/internal/envvar"
@@ -153,6 +154,31 @@ func TestAccRepositoryResourceImmutableTags(t *testing.T) {
 	})
 }
 
+func TestAccRepositoryResourceImmutableTagsInvalidRule(t *testing.T) {
+	namespace := envvar.GetWithDefault(envvar.AccTestOrganization)
+	name := "immutable-repo" + randString(10)
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "docker_hub_repository" "test" {
+  name      = "%[2]s"
+  namespace = "%[1]s"
+
+  immutable_tags_settings = {
+    enabled = true
+    rules   = ["v(1|2"]
+  }
+}`, namespace, name),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Invalid Immutable Tag Rule`),
+			},
+		},
+	})
+}
+
 func testRepositoryResourceConfigImmutableTags(namespace, name string) string {
 	return fmt.Sprintf(`
 resource "docker_hub_repository" "test" {
@@ -0,0 +1,300 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/docker/terraform-provider-docker/internal/hubclient"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource                = &RepositoryWebhookResource{}
+	_ resource.ResourceWithConfigure   = &RepositoryWebhookResource{}
+	_ resource.ResourceWithImportState = &RepositoryWebhookResource{}
+)
+
+func NewRepositoryWebhookResource() resource.Resource {
+	return &RepositoryWebhookResource{}
+}
+
+type RepositoryWebhookResource struct {
+	client *hubclient.Client
+}
+
+type RepositoryWebhookResourceModel struct {
+	RepoID              types.String `tfsdk:"repo_id"`
+	Name                types.String `tfsdk:"name"`
+	URL                 types.String `tfsdk:"url"`
+	ExpectFinalCallback types.Bool   `tfsdk:"expect_final_callback"`
+	Registry            types.String `tfsdk:"registry"`
+	Slug                types.String `tfsdk:"slug"`
+}
+
+func (r *RepositoryWebhookResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *provider.ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = providerData.Hub
+}
+
+func (r *RepositoryWebhookResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_hub_repository_webhook"
+}
+
+func (r *RepositoryWebhookResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: `Manages a webhook of an image repository. Docker Hub calls the webhook URL with a JSON payload every time an image is pushed to the repository.
+
+-> **Note** When used with a Personal Access Token authentication (PAT), the PAT should
+   have the "Read, Write, and Delete" scope to create and delete webhooks. The owner
+   of the PAT must be an admin of the repository.
+
+## Example Usage
+
+` + "```hcl" + `
+resource "docker_hub_repository" "my_repo" {
+  namespace = "my-namespace"
+  name      = "my-repo"
+}
+
+resource "docker_hub_repository_webhook" "ci" {
+  repo_id = docker_hub_repository.my_repo.id
+  name    = "ci"
+  url     = "https://ci.example.com/hooks/docker"
+}
+` + "```" + `
+
+## Import
+
+Webhooks can be imported with the repository namespace, repository name and webhook name:
+
+` + "```shell" + `
+terraform import docker_hub_repository_webhook.ci my-namespace/my-repo/ci
+` + "```" + `
+`,
+
+		Attributes: map[string]schema.Attribute{
+			"repo_id": schema.StringAttribute{
+				MarkdownDescription: "The namespace/name of the repository",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "The name of the webhook, unique within the repository",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"url": schema.StringAttribute{
+				MarkdownDescription: "The URL Docker Hub calls when an image is pushed",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^https?://[^/\s]+`), "Must be an http or https URL"),
+				},
+			},
+			"expect_final_callback": schema.BoolAttribute{
+				MarkdownDescription: "Whether Docker Hub waits for the webhook to call back the `callback_url` of the payload before marking the delivery successful. Defaults to `false`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"registry": schema.StringAttribute{
+				MarkdownDescription: "The registry the webhook listens to. Defaults to `" + hubclient.DefaultWebhookRegistry + "`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString(hubclient.DefaultWebhookRegistry),
+			},
+			"slug": schema.StringAttribute{
+				MarkdownDescription: "The identifier Docker Hub derives from the webhook name",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *RepositoryWebhookResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data RepositoryWebhookResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	webhook, err := r.client.CreateRepositoryWebhook(ctx, data.RepoID.ValueString(), data.params())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to create repository_webhook resource", err.Error())
+		return
+	}
+
+	data.fromWebhook(webhook)
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RepositoryWebhookResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data RepositoryWebhookResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var webhook hubclient.RepositoryWebhook
+	var err error
+	found := true
+	if data.Slug.IsNull() || data.Slug.ValueString() == "" {
+		// Imported webhooks are only known by name until the first read.
+		webhook, found, err = r.findWebhookByName(ctx, data.RepoID.ValueString(), data.Name.ValueString())
+	} else {
+		webhook, err = r.client.GetRepositoryWebhook(ctx, data.RepoID.ValueString(), data.Slug.ValueString())
+	}
+	// Treat HTTP 404 Not Found status as a signal to recreate resource and return early
+	if !found || hubclient.IsNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	} else if err != nil {
+		resp.Diagnostics.AddError("Unable to read repository_webhook resource", err.Error())
+		return
+	}
+
+	data.fromWebhook(webhook)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RepositoryWebhookResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data RepositoryWebhookResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	webhook, err := r.client.UpdateRepositoryWebhook(ctx, data.RepoID.ValueString(), data.Slug.ValueString(), data.params())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to update repository_webhook resource", err.Error())
+		return
+	}
+
+	data.fromWebhook(webhook)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RepositoryWebhookResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data RepositoryWebhookResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeleteRepositoryWebhook(ctx, data.RepoID.ValueString(), data.Slug.ValueString())
+	if hubclient.IsNotFound(err) {
+		return
+	} else if err != nil {
+		resp.Diagnostics.AddError("Unable to delete repository_webhook resource", err.Error())
+		return
+	}
+}
+
+func (r *RepositoryWebhookResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	idParts := strings.SplitN(req.ID, "/", 3)
+
+	if len(idParts) != 3 || idParts[0] == "" || idParts[1] == "" || idParts[2] == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected import identifier with format: repo_namespace/repo_name/webhook_name. Got: %q", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("repo_id"), idParts[0]+"/"+idParts[1])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), idParts[2])...)
+}
+
+// findWebhookByName looks up a webhook of a repository by name.
+func (r *RepositoryWebhookResource) findWebhookByName(ctx context.Context, repository, name string) (hubclient.RepositoryWebhook, bool, error) {
+	webhooks, err := r.client.ListRepositoryWebhooks(ctx, repository)
+	if err != nil {
+		return hubclient.RepositoryWebhook{}, true, err
+	}
+	for _, webhook := range webhooks.Results {
+		if webhook.Name == name {
+			return webhook, true, nil
+		}
+	}
+	return hubclient.RepositoryWebhook{}, false, nil
+}
+
+func (m *RepositoryWebhookResourceModel) params() hubclient.RepositoryWebhookParams {
+	return hubclient.NewRepositoryWebhookParams(
+		m.Name.ValueString(),
+		m.URL.ValueString(),
+		m.ExpectFinalCallback.ValueBool(),
+		m.Registry.ValueString(),
+	)
+}
+
+func (m *RepositoryWebhookResourceModel) fromWebhook(webhook hubclient.RepositoryWebhook) {
+	m.Name = types.StringValue(webhook.Name)
+	m.URL = types.StringValue(webhook.URL())
+	m.ExpectFinalCallback = types.BoolValue(webhook.ExpectFinalCallback)
+	m.Registry = types.StringValue(webhook.Registry)
+	m.Slug = types.StringValue(webhook.Slug)
+}
@@ -0,0 +1,109 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/docker/terraform-provider-docker/internal/envvar"
+	"github.com/docker/terraform-provider-docker/internal/hubclient"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccRepositoryWebhook(t *testing.T) {
+	orgName := envvar.GetWithDefault(envvar.AccTestOrganization)
+	repoName := "test" + randString(10)
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// create
+				Config: testAccRepositoryWebhook(orgName, repoName, "https://example.com/hook", false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("docker_hub_repository_webhook.test", "repo_id", "docker_hub_repository.test", "id"),
+					resource.TestCheckResourceAttr("docker_hub_repository_webhook.test", "name", "ci"),
+					resource.TestCheckResourceAttr("docker_hub_repository_webhook.test", "url", "https://example.com/hook"),
+					resource.TestCheckResourceAttr("docker_hub_repository_webhook.test", "expect_final_callback", "false"),
+					resource.TestCheckResourceAttr("docker_hub_repository_webhook.test", "registry", hubclient.DefaultWebhookRegistry),
+					resource.TestCheckResourceAttrSet("docker_hub_repository_webhook.test", "slug"),
+				),
+			},
+			{
+				// import
+				ResourceName:                         "docker_hub_repository_webhook.test",
+				ImportState:                          true,
+				ImportStateId:                        orgName + "/" + repoName + "/ci",
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "name",
+			},
+			{
+				// update
+				Config: testAccRepositoryWebhook(orgName, repoName, "https://example.com/hook2", true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("docker_hub_repository_webhook.test", "url", "https://example.com/hook2"),
+					resource.TestCheckResourceAttr("docker_hub_repository_webhook.test", "expect_final_callback", "true"),
+				),
+			},
+			{
+				// data source
+				Config: testAccRepositoryWebhook(orgName, repoName, "https://example.com/hook2", true) + testAccRepositoryWebhooksDataSource,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.docker_hub_repository_webhooks.test", "id", "docker_hub_repository.test", "id"),
+					resource.TestCheckResourceAttr("data.docker_hub_repository_webhooks.test", "webhooks.#", "1"),
+					resource.TestCheckResourceAttr("data.docker_hub_repository_webhooks.test", "webhooks.0.name", "ci"),
+					resource.TestCheckResourceAttr("data.docker_hub_repository_webhooks.test", "webhooks.0.url", "https://example.com/hook2"),
+				),
+			},
+			{
+				// delete
+				Config: testAccRepositoryWebhookBase(orgName, repoName),
+			},
+		},
+	})
+}
+
+func testAccRepositoryWebhookBase(orgName, repoName string) string {
+	return fmt.Sprintf(`
+resource "docker_hub_repository" "test" {
+  namespace = "%[1]s"
+  name      = "%[2]s"
+}`, orgName, repoName)
+}
+
+func testAccRepositoryWebhook(orgName, repoName, url string, expectFinalCallback bool) string {
+	return fmt.Sprintf(`
+%[1]s
+
+resource "docker_hub_repository_webhook" "test" {
+  repo_id               = docker_hub_repository.test.id
+  name                  = "ci"
+  url                   = "%[2]s"
+  expect_final_callback = %[3]t
+}
+`, testAccRepositoryWebhookBase(orgName, repoName), url, expectFinalCallback)
+}
+
+const testAccRepositoryWebhooksDataSource = `
+data "docker_hub_repository_webhooks" "test" {
+  namespace = docker_hub_repository.test.namespace
+  name      = docker_hub_repository.test.name
+
+  depends_on = [docker_hub_repository_webhook.test]
+}
+`
@@ -0,0 +1,169 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/docker/terraform-provider-docker/internal/hubclient"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+// tagRetentionPolicy selects the tags of a repository that should be
+// deleted.
+type tagRetentionPolicy struct {
+	// KeepLast is the number of most recently pushed tags that are never
+	// deleted. Negative means unset.
+	KeepLast int
+	// NotPulledIn deletes tags that have not been pulled for this long. Zero
+	// means unset.
+	NotPulledIn time.Duration
+	// Include limits the policy to tags matching any of the patterns. All
+	// tags are included if empty.
+	Include []*regexp.Regexp
+	// Exclude protects tags matching any of the patterns.
+	Exclude []*regexp.Regexp
+	// Immutable are the immutable tag settings of the repository. Immutable
+	// tags are never deleted.
+	Immutable hubclient.ImmutableTagsSettings
+}
+
+// compileTagPatterns compiles include or exclude patterns. Like immutable
+// tag rules, patterns must match the whole tag.
+func compileTagPatterns(patterns []string) ([]*regexp.Regexp, error) {
+	res := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := hubclient.CompileTagPattern(pattern)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, re)
+	}
+	return res, nil
+}
+
+func matchesAny(res []*regexp.Regexp, tag string) bool {
+	for _, re := range res {
+		if re.MatchString(tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseHubTime parses a Docker Hub timestamp, returning the zero time if it
+// is empty or malformed.
+func parseHubTime(s string) time.Time {
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// tagLastPushed returns when a tag was last pushed, falling back to when it
+// was last updated for tags Docker Hub has no push date for.
+func tagLastPushed(tag hubclient.Tag) time.Time {
+	if t := parseHubTime(tag.TagLastPushed); !t.IsZero() {
+		return t
+	}
+	return parseHubTime(tag.LastUpdated)
+}
+
+// SelectTags returns the names of the tags to delete, sorted by name.
+func (p tagRetentionPolicy) SelectTags(tags []hubclient.Tag, now time.Time) ([]string, error) {
+	var candidates []hubclient.Tag
+	for _, tag := range tags {
+		if len(p.Include) > 0 && !matchesAny(p.Include, tag.Name) {
+			continue
+		}
+		if matchesAny(p.Exclude, tag.Name) {
+			continue
+		}
+		immutable, err := p.Immutable.Protects(tag.Name)
+		if err != nil {
+			return nil, err
+		}
+		if immutable {
+			continue
+		}
+		candidates = append(candidates, tag)
+	}
+
+	// Most recently pushed first.
+	sort.SliceStable(candidates, func(i, j int) bool {
+		ti, tj := tagLastPushed(candidates[i]), tagLastPushed(candidates[j])
+		if !ti.Equal(tj) {
+			return ti.After(tj)
+		}
+		return candidates[i].Name < candidates[j].Name
+	})
+	if p.KeepLast >= 0 {
+		if p.KeepLast >= len(candidates) {
+			return []string{}, nil
+		}
+		candidates = candidates[p.KeepLast:]
+	}
+
+	selected := []string{}
+	for _, tag := range candidates {
+		if p.NotPulledIn > 0 {
+			// Tags that were never pulled are as stale as their last push.
+			lastUsed := parseHubTime(tag.TagLastPulled)
+			if lastUsed.IsZero() {
+				lastUsed = tagLastPushed(tag)
+			}
+			if now.Sub(lastUsed) < p.NotPulledIn {
+				continue
+			}
+		}
+		selected = append(selected, tag.Name)
+	}
+	sort.Strings(selected)
+	return selected, nil
+}
+
+var tagPatternValidator validator.String = tagPatternStringValidator{}
+
+// tagPatternStringValidator checks that a string is a valid include or
+// exclude pattern of a tag retention policy.
+type tagPatternStringValidator struct{}
+
+func (v tagPatternStringValidator) Description(_ context.Context) string {
+	return "must be a valid regular expression (RE2 syntax)"
+}
+
+func (v tagPatternStringValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v tagPatternStringValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if _, err := hubclient.CompileTagPattern(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Tag Pattern",
+			err.Error(),
+		)
+	}
+}
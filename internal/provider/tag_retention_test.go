@@ -0,0 +1,111 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package provider
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/docker/terraform-provider-docker/internal/hubclient"
+)
+
+func TestTagRetentionPolicySelectTags(t *testing.T) {
+	now := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	tag := func(name string, pushedDaysAgo, pulledDaysAgo int) hubclient.Tag {
+		tag := hubclient.Tag{
+			Name:          name,
+			TagLastPushed: now.Add(-time.Duration(pushedDaysAgo) * day).Format(time.RFC3339),
+		}
+		if pulledDaysAgo >= 0 {
+			tag.TagLastPulled = now.Add(-time.Duration(pulledDaysAgo) * day).Format(time.RFC3339)
+		}
+		return tag
+	}
+	tags := []hubclient.Tag{
+		tag("latest", 1, 0),
+		tag("v1.0", 100, 50),
+		tag("v1.1", 60, 5),
+		tag("v1.2", 10, -1),
+		tag("pr-1", 200, -1),
+		tag("pr-2", 3, -1),
+	}
+
+	tests := []struct {
+		name   string
+		policy tagRetentionPolicy
+		want   []string
+	}{
+		{
+			name:   "keep last",
+			policy: tagRetentionPolicy{KeepLast: 3},
+			want:   []string{"pr-1", "v1.0", "v1.1"},
+		},
+		{
+			name:   "not pulled",
+			policy: tagRetentionPolicy{KeepLast: -1, NotPulledIn: 30 * day},
+			want:   []string{"pr-1", "v1.0"},
+		},
+		{
+			name:   "keep last and not pulled",
+			policy: tagRetentionPolicy{KeepLast: 5, NotPulledIn: 30 * day},
+			want:   []string{"pr-1"},
+		},
+		{
+			name: "include and exclude",
+			policy: tagRetentionPolicy{
+				KeepLast: 0,
+				Include:  []*regexp.Regexp{regexp.MustCompile(`^(?:v1\..*)$`)},
+				Exclude:  []*regexp.Regexp{regexp.MustCompile(`^(?:v1\.2)$`)},
+			},
+			want: []string{"v1.0", "v1.1"},
+		},
+		{
+			name:   "immutable tags",
+			policy: tagRetentionPolicy{KeepLast: 0, Immutable: hubclient.ImmutableTagsSettings{Enabled: true, Rules: []string{"latest", "v.*"}}},
+			want:   []string{"pr-1", "pr-2"},
+		},
+		{
+			name:   "immutable tags disabled",
+			policy: tagRetentionPolicy{KeepLast: 4, Immutable: hubclient.ImmutableTagsSettings{Enabled: false, Rules: []string{"latest", "v.*"}}},
+			want:   []string{"pr-1", "v1.0"},
+		},
+		{
+			name:   "immutable tags without rules",
+			policy: tagRetentionPolicy{KeepLast: 0, Immutable: hubclient.ImmutableTagsSettings{Enabled: true}},
+			want:   []string{},
+		},
+		{
+			name:   "keep more than exist",
+			policy: tagRetentionPolicy{KeepLast: 10},
+			want:   []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.policy.SelectTags(tags, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
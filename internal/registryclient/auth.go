@@ -0,0 +1,206 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package registryclient
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultTokenLifetime is how long a token is used if the token service
+	// does not say, as recommended by the token authentication spec.
+	defaultTokenLifetime = time.Minute
+	// tokenExpiryMargin renews tokens a bit early, so that they do not
+	// expire in flight.
+	tokenExpiryMargin = 10 * time.Second
+)
+
+// challenge is a parsed WWW-Authenticate header.
+type challenge struct {
+	scheme string
+	params map[string]string
+}
+
+// parseChallenge parses a WWW-Authenticate header such as
+//
+//	Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:library/alpine:pull"
+func parseChallenge(header string) (challenge, bool) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(header), " ")
+	if scheme == "" {
+		return challenge{}, false
+	}
+	c := challenge{scheme: strings.ToLower(scheme), params: map[string]string{}}
+	for rest = strings.TrimSpace(rest); rest != ""; rest = strings.TrimSpace(rest) {
+		name, value, ok := strings.Cut(rest, "=")
+		if !ok {
+			break
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		value = strings.TrimSpace(value)
+		if strings.HasPrefix(value, `"`) {
+			// Quoted values may contain commas.
+			end := strings.Index(value[1:], `"`)
+			if end < 0 {
+				return challenge{}, false
+			}
+			c.params[name] = value[1 : end+1]
+			rest = strings.TrimPrefix(strings.TrimSpace(value[end+2:]), ",")
+			continue
+		}
+		value, rest, _ = strings.Cut(value, ",")
+		c.params[name] = strings.TrimSpace(value)
+	}
+	return c, c.scheme == "basic" || c.scheme == "bearer"
+}
+
+type tokenKey struct {
+	host  string
+	scope string
+}
+
+type cachedToken struct {
+	auth   string
+	expiry time.Time
+}
+
+// tokenCache holds the Authorization header values to send to registries.
+type tokenCache struct {
+	mu     sync.Mutex
+	tokens map[tokenKey]cachedToken
+}
+
+func newTokenCache() *tokenCache {
+	return &tokenCache{tokens: map[tokenKey]cachedToken{}}
+}
+
+func (c *tokenCache) get(key tokenKey) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	token, ok := c.tokens[key]
+	if !ok || time.Now().After(token.expiry) {
+		return "", false
+	}
+	return token.auth, true
+}
+
+func (c *tokenCache) put(key tokenKey, auth string, expiry time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tokens[key] = cachedToken{auth: auth, expiry: expiry}
+}
+
+// authenticate answers a challenge of the registry and returns the
+// Authorization header to retry with.
+func (c *Client) authenticate(ctx context.Context, host string, key tokenKey, scopes []string, ch challenge) (string, error) {
+	var username, secret string
+	if c.credentials != nil {
+		var err error
+		if username, secret, err = c.credentials(ctx, host); err != nil {
+			return "", fmt.Errorf("get credentials for %s: %w", host, err)
+		}
+	}
+
+	if ch.scheme == "basic" {
+		if username == "" && secret == "" {
+			return "", fmt.Errorf("registry %s requires credentials", host)
+		}
+		auth := "Basic " + basicAuth(username, secret)
+		// Basic credentials do not expire, but may be changed in the
+		// credential store.
+		c.tokens.put(key, auth, time.Now().Add(defaultTokenLifetime))
+		return auth, nil
+	}
+
+	token, lifetime, err := c.fetchToken(ctx, ch, scopes, username, secret)
+	if err != nil {
+		return "", err
+	}
+	auth := "Bearer " + token
+	c.tokens.put(key, auth, time.Now().Add(lifetime-tokenExpiryMargin))
+	return auth, nil
+}
+
+// fetchToken gets a bearer token from the token service named in the
+// challenge, as described by the Docker registry token authentication spec.
+func (c *Client) fetchToken(ctx context.Context, ch challenge, scopes []string, username, secret string) (string, time.Duration, error) {
+	realm := ch.params["realm"]
+	if realm == "" {
+		return "", 0, fmt.Errorf("bearer challenge without realm")
+	}
+	u, err := url.Parse(realm)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid token realm %q: %w", realm, err)
+	}
+	query := u.Query()
+	if service := ch.params["service"]; service != "" {
+		query.Set("service", service)
+	}
+	query["scope"] = scopes
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", 0, err
+	}
+	if username != "" || secret != "" {
+		req.Header.Set("Authorization", "Basic "+basicAuth(username, secret))
+	}
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", 0, fmt.Errorf("token request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, maxErrorBodySize))
+		return "", 0, newAPIError(http.MethodGet, realm, res.StatusCode, body)
+	}
+
+	var tokenResponse struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&tokenResponse); err != nil {
+		return "", 0, fmt.Errorf("decode token response: %w", err)
+	}
+	token := tokenResponse.Token
+	if token == "" {
+		token = tokenResponse.AccessToken
+	}
+	if token == "" {
+		return "", 0, fmt.Errorf("token response from %s contains no token", realm)
+	}
+	lifetime := time.Duration(tokenResponse.ExpiresIn) * time.Second
+	if lifetime < defaultTokenLifetime {
+		lifetime = defaultTokenLifetime
+	}
+	return token, lifetime, nil
+}
+
+func basicAuth(username, secret string) string {
+	return base64.StdEncoding.EncodeToString([]byte(username + ":" + secret))
+}
@@ -0,0 +1,91 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package registryclient
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseChallenge(t *testing.T) {
+	tests := []struct {
+		header string
+		want   challenge
+		ok     bool
+	}{
+		{
+			header: `Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:library/alpine:pull,push"`,
+			want: challenge{scheme: "bearer", params: map[string]string{
+				"realm":   "https://auth.docker.io/token",
+				"service": "registry.docker.io",
+				"scope":   "repository:library/alpine:pull,push",
+			}},
+			ok: true,
+		},
+		{
+			header: `Basic realm="Registry Realm"`,
+			want:   challenge{scheme: "basic", params: map[string]string{"realm": "Registry Realm"}},
+			ok:     true,
+		},
+		{
+			header: `Bearer realm=https://ghcr.io/token, service=ghcr.io`,
+			want: challenge{scheme: "bearer", params: map[string]string{
+				"realm":   "https://ghcr.io/token",
+				"service": "ghcr.io",
+			}},
+			ok: true,
+		},
+		{header: "", ok: false},
+		{header: `Negotiate abc`, ok: false},
+		{header: `Bearer realm="unterminated`, ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			got, ok := parseChallenge(tt.header)
+			if ok != tt.ok {
+				t.Fatalf("got ok %v, want %v", ok, tt.ok)
+			}
+			if ok && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegistryHost(t *testing.T) {
+	tests := map[string]string{
+		"docker.io":      DockerHubRegistry,
+		"ghcr.io":        "ghcr.io",
+		"localhost:5000": "localhost:5000",
+	}
+	for registry, want := range tests {
+		if got := RegistryHost(registry); got != want {
+			t.Errorf("RegistryHost(%q) = %q, want %q", registry, got, want)
+		}
+	}
+
+	for host, want := range map[string]string{
+		"ghcr.io":         "https://ghcr.io/v2",
+		"localhost:5000":  "http://localhost:5000/v2",
+		"127.0.0.1:39123": "http://127.0.0.1:39123/v2",
+		"[::1]:5000":      "http://[::1]:5000/v2",
+	} {
+		if got := baseURL(host); got != want {
+			t.Errorf("baseURL(%q) = %q, want %q", host, got, want)
+		}
+	}
+}
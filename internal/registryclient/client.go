@@ -0,0 +1,223 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package registryclient is a client for the OCI distribution API spoken by
+// container registries such as Docker Hub's registry-1.docker.io.
+package registryclient
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// DockerHubRegistry is the host serving the distribution API of Docker
+	// Hub, which image references call "docker.io".
+	DockerHubRegistry = "registry-1.docker.io"
+
+	dockerHubDomain = "docker.io"
+)
+
+// CredentialsFunc returns the username and secret to authenticate to a
+// registry host with. Empty credentials mean anonymous access.
+type CredentialsFunc func(ctx context.Context, host string) (username, secret string, err error)
+
+type Config struct {
+	Transport http.RoundTripper
+	// Credentials looks up the credentials of a registry. Nil means
+	// anonymous access to all registries.
+	Credentials CredentialsFunc
+}
+
+// Client talks to any registry. Bearer tokens are cached per registry and
+// scope until they expire.
+type Client struct {
+	httpClient  *http.Client
+	credentials CredentialsFunc
+	tokens      *tokenCache
+}
+
+func NewClient(config Config) *Client {
+	transport := config.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &Client{
+		httpClient: &http.Client{
+			Timeout:   time.Minute,
+			Transport: transport,
+		},
+		credentials: config.Credentials,
+		tokens:      newTokenCache(),
+	}
+}
+
+// RegistryHost returns the host serving the distribution API of a registry
+// as found in an image reference.
+func RegistryHost(registry string) string {
+	if registry == dockerHubDomain {
+		return DockerHubRegistry
+	}
+	return registry
+}
+
+// baseURL returns the URL of the distribution API of a host. Like the docker
+// daemon, registries on the loopback interface are spoken to over plain
+// http.
+func baseURL(host string) string {
+	scheme := "https"
+	if isLoopback(host) {
+		scheme = "http"
+	}
+	return fmt.Sprintf("%s://%s/v2", scheme, host)
+}
+
+func isLoopback(host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(strings.Trim(host, "[]"))
+	return ip != nil && ip.IsLoopback()
+}
+
+// request is a request to a repository of a registry.
+type request struct {
+	method     string
+	host       string
+	repository string
+	// path is relative to the repository, such as "/manifests/latest".
+	path string
+	// location is a URL returned by the registry to send the request to
+	// instead of path, such as the location of an upload.
+	location string
+	query    url.Values
+	headers  http.Header
+	body     []byte
+	// stream is sent instead of body when set. Streamed requests cannot be
+	// retried, so they should only be sent once a token for the scope is
+	// cached.
+	stream        io.Reader
+	contentLength int64
+	// push asks for push access to the repository in addition to pull.
+	push bool
+	// mountFrom is a repository of the same registry to also ask pull access
+	// to, for cross-repository blob mounts.
+	mountFrom string
+}
+
+func (r request) url() string {
+	raw := fmt.Sprintf("%s/%s%s", baseURL(r.host), r.repository, r.path)
+	u, err := url.Parse(raw)
+	if err != nil {
+		// The request will fail with the same error.
+		return raw
+	}
+	if r.location != "" {
+		// Locations may be relative to the registry.
+		if loc, err := u.Parse(r.location); err == nil {
+			u = loc
+		}
+	}
+	if len(r.query) > 0 {
+		query := u.Query()
+		for name, values := range r.query {
+			query[name] = values
+		}
+		u.RawQuery = query.Encode()
+	}
+	return u.String()
+}
+
+// scopes returns the token scopes the request needs.
+func (r request) scopes() []string {
+	actions := "pull"
+	if r.push {
+		actions = "pull,push"
+	}
+	scopes := []string{fmt.Sprintf("repository:%s:%s", r.repository, actions)}
+	if r.mountFrom != "" && r.mountFrom != r.repository {
+		scopes = append(scopes, fmt.Sprintf("repository:%s:pull", r.mountFrom))
+	}
+	return scopes
+}
+
+// do sends the request, authenticating as asked by the registry. The caller
+// must close the response body. Responses other than 2xx are returned as an
+// APIError.
+func (c *Client) do(ctx context.Context, r request) (*http.Response, error) {
+	key := tokenKey{host: r.host, scope: strings.Join(r.scopes(), " ")}
+	auth, _ := c.tokens.get(key)
+
+	res, err := c.send(ctx, r, auth)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode == http.StatusUnauthorized && r.stream == nil {
+		challenge, ok := parseChallenge(res.Header.Get("WWW-Authenticate"))
+		res.Body.Close()
+		if !ok {
+			return nil, newAPIError(r.method, r.url(), http.StatusUnauthorized, nil)
+		}
+		auth, err = c.authenticate(ctx, r.host, key, r.scopes(), challenge)
+		if err != nil {
+			return nil, err
+		}
+		if res, err = c.send(ctx, r, auth); err != nil {
+			return nil, err
+		}
+	}
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusBadRequest {
+		defer res.Body.Close()
+		body, readErr := io.ReadAll(io.LimitReader(res.Body, maxErrorBodySize))
+		if readErr != nil {
+			return nil, readErr
+		}
+		return nil, newAPIError(r.method, r.url(), res.StatusCode, body)
+	}
+	return res, nil
+}
+
+func (c *Client) send(ctx context.Context, r request, auth string) (*http.Response, error) {
+	var body io.Reader = bytes.NewReader(r.body)
+	if r.stream != nil {
+		body = r.stream
+	}
+	req, err := http.NewRequestWithContext(ctx, r.method, r.url(), body)
+	if err != nil {
+		return nil, err
+	}
+	if r.stream != nil {
+		req.ContentLength = r.contentLength
+	}
+	for name, values := range r.headers {
+		req.Header[name] = values
+	}
+	if auth != "" {
+		req.Header.Set("Authorization", auth)
+	}
+	return c.httpClient.Do(req)
+}
@@ -0,0 +1,253 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package registryclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Image identifies a manifest in a repository of a registry.
+type Image struct {
+	// Host is the host serving the distribution API, see RegistryHost.
+	Host string
+	// Repository is the path of the repository, such as "library/alpine".
+	Repository string
+	// Reference is a tag or a digest.
+	Reference string
+}
+
+func (i Image) String() string {
+	sep := ":"
+	if isDigest(i.Reference) {
+		sep = "@"
+	}
+	return i.Host + "/" + i.Repository + sep + i.Reference
+}
+
+// PutManifest pushes a manifest under a tag or its digest, and returns its
+// digest.
+func (c *Client) PutManifest(ctx context.Context, host, repository, reference, mediaType string, raw []byte) (string, error) {
+	res, err := c.do(ctx, request{
+		method:     http.MethodPut,
+		host:       host,
+		repository: repository,
+		path:       "/manifests/" + reference,
+		headers:    http.Header{"Content-Type": {mediaType}},
+		body:       raw,
+		push:       true,
+	})
+	if err != nil {
+		return "", err
+	}
+	res.Body.Close()
+
+	digest := res.Header.Get("Docker-Content-Digest")
+	if digest == "" {
+		digest = Digest(raw)
+	}
+	return digest, nil
+}
+
+// DeleteManifest deletes a tag, or a manifest and all the tags pointing to
+// it if reference is a digest. Not all registries support deleting tags.
+func (c *Client) DeleteManifest(ctx context.Context, host, repository, reference string) error {
+	res, err := c.do(ctx, request{
+		method:     http.MethodDelete,
+		host:       host,
+		repository: repository,
+		path:       "/manifests/" + reference,
+		push:       true,
+	})
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	return nil
+}
+
+// BlobExists reports whether a repository has a blob.
+func (c *Client) BlobExists(ctx context.Context, host, repository, digest string) (bool, error) {
+	return c.exists(ctx, request{
+		method:     http.MethodHead,
+		host:       host,
+		repository: repository,
+		path:       "/blobs/" + digest,
+	})
+}
+
+// exists sends a HEAD request and reports whether the content exists.
+func (c *Client) exists(ctx context.Context, r request) (bool, error) {
+	res, err := c.do(ctx, r)
+	if IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	res.Body.Close()
+	return true, nil
+}
+
+// CopyImage copies a manifest or index, with everything it references, and
+// tags it in the destination. Blobs are mounted from the source repository
+// when both are on the same registry, and copied otherwise. Content already
+// in the destination is not copied again.
+//
+// The destination reference must be a tag. CopyImage returns the descriptor
+// of the copied manifest.
+func (c *Client) CopyImage(ctx context.Context, src, dst Image) (Descriptor, error) {
+	if isDigest(dst.Reference) {
+		return Descriptor{}, fmt.Errorf("copy destination %s must be a tag", dst)
+	}
+	manifest, err := c.GetManifest(ctx, src.Host, src.Repository, src.Reference)
+	if err != nil {
+		return Descriptor{}, err
+	}
+	if err := c.copyContent(ctx, src, dst, manifest); err != nil {
+		return Descriptor{}, err
+	}
+	if _, err := c.PutManifest(ctx, dst.Host, dst.Repository, dst.Reference, manifest.Descriptor.MediaType, manifest.Raw); err != nil {
+		return Descriptor{}, err
+	}
+	return manifest.Descriptor, nil
+}
+
+// copyContent copies what a manifest references: the manifests of an index,
+// or the config and layers of an image.
+func (c *Client) copyContent(ctx context.Context, src, dst Image, manifest Manifest) error {
+	if manifest.IsIndex() {
+		for _, entry := range manifest.Manifests {
+			// Check with the push scope the copy needs anyway, the
+			// destination repository may not exist yet.
+			exists, err := c.exists(ctx, request{
+				method:     http.MethodHead,
+				host:       dst.Host,
+				repository: dst.Repository,
+				path:       "/manifests/" + entry.Digest,
+				headers:    manifestHeaders(),
+				push:       true,
+			})
+			if err != nil {
+				return err
+			}
+			if exists {
+				continue
+			}
+			child, err := c.GetManifest(ctx, src.Host, src.Repository, entry.Digest)
+			if err != nil {
+				return err
+			}
+			if err := c.copyContent(ctx, src, dst, child); err != nil {
+				return err
+			}
+			if _, err := c.PutManifest(ctx, dst.Host, dst.Repository, entry.Digest, child.Descriptor.MediaType, child.Raw); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var blobs []Descriptor
+	if manifest.Config != nil {
+		blobs = append(blobs, *manifest.Config)
+	}
+	blobs = append(blobs, manifest.Layers...)
+	for _, blob := range blobs {
+		if len(blob.URLs) > 0 {
+			continue
+		}
+		if err := c.copyBlob(ctx, src, dst, blob); err != nil {
+			return fmt.Errorf("copy blob %s: %w", blob.Digest, err)
+		}
+	}
+	return nil
+}
+
+func (c *Client) copyBlob(ctx context.Context, src, dst Image, blob Descriptor) error {
+	mountFrom := ""
+	if src.Host == dst.Host {
+		mountFrom = src.Repository
+	}
+	exists, err := c.exists(ctx, request{
+		method:     http.MethodHead,
+		host:       dst.Host,
+		repository: dst.Repository,
+		path:       "/blobs/" + blob.Digest,
+		push:       true,
+		mountFrom:  mountFrom,
+	})
+	if err != nil || exists {
+		return err
+	}
+
+	upload := request{
+		method:     http.MethodPost,
+		host:       dst.Host,
+		repository: dst.Repository,
+		path:       "/blobs/uploads/",
+		push:       true,
+		mountFrom:  mountFrom,
+	}
+	if mountFrom != "" {
+		upload.query = url.Values{"mount": {blob.Digest}, "from": {mountFrom}}
+	}
+	res, err := c.do(ctx, upload)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	if res.StatusCode == http.StatusCreated {
+		return nil
+	}
+
+	// The registry did not mount the blob and started an upload instead.
+	location := res.Header.Get("Location")
+	if location == "" {
+		return fmt.Errorf("registry %s started an upload without a location", dst.Host)
+	}
+	blobRes, err := c.do(ctx, request{
+		method:     http.MethodGet,
+		host:       src.Host,
+		repository: src.Repository,
+		path:       "/blobs/" + blob.Digest,
+	})
+	if err != nil {
+		return err
+	}
+	defer blobRes.Body.Close()
+
+	res, err = c.do(ctx, request{
+		method:        http.MethodPut,
+		host:          dst.Host,
+		repository:    dst.Repository,
+		location:      location,
+		query:         url.Values{"digest": {blob.Digest}},
+		headers:       http.Header{"Content-Type": {"application/octet-stream"}},
+		stream:        blobRes.Body,
+		contentLength: blob.Size,
+		push:          true,
+		mountFrom:     mountFrom,
+	})
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	return nil
+}
@@ -0,0 +1,97 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package registryclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// maxErrorBodySize limits how much of an error response body we keep, to
+// avoid excessive logs.
+const maxErrorBodySize = 500
+
+// APIError is returned by the client for any non-2xx response from a
+// registry.
+type APIError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// Method is the HTTP method of the failed request.
+	Method string
+	// Path is the request URL.
+	Path string
+	// Code is the distribution error code, such as MANIFEST_UNKNOWN, if the
+	// response contained one.
+	Code string
+	// Message is the human readable error message parsed from the response.
+	Message string
+}
+
+func (e *APIError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+	if e.Code != "" {
+		return fmt.Sprintf("registry response %s %s: %d %s: %s", e.Method, e.Path, e.StatusCode, e.Code, msg)
+	}
+	return fmt.Sprintf("registry response %s %s: %d: %s", e.Method, e.Path, e.StatusCode, msg)
+}
+
+// newAPIError builds an APIError from a response body in the format of the
+// distribution spec:
+//
+//	{"errors": [{"code": "...", "message": "...", "detail": ...}]}
+func newAPIError(method, path string, statusCode int, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: statusCode,
+		Method:     method,
+		Path:       path,
+	}
+
+	var parsed struct {
+		Errors []struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &parsed); err != nil || len(parsed.Errors) == 0 {
+		apiErr.Message = strings.TrimSpace(string(body))
+		return apiErr
+	}
+	apiErr.Code = parsed.Errors[0].Code
+	apiErr.Message = parsed.Errors[0].Message
+	return apiErr
+}
+
+// AsAPIError returns the APIError wrapped in err, if any.
+func AsAPIError(err error) (*APIError, bool) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr, true
+	}
+	return nil, false
+}
+
+// IsNotFound reports whether err is a 404 response from a registry.
+func IsNotFound(err error) bool {
+	apiErr, ok := AsAPIError(err)
+	return ok && apiErr.StatusCode == http.StatusNotFound
+}
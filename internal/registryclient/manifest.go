@@ -0,0 +1,260 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package registryclient
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Media types of manifests and indexes.
+const (
+	MediaTypeOCIIndex           = "application/vnd.oci.image.index.v1+json"
+	MediaTypeOCIManifest        = "application/vnd.oci.image.manifest.v1+json"
+	MediaTypeDockerManifestList = "application/vnd.docker.distribution.manifest.list.v2+json"
+	MediaTypeDockerManifest     = "application/vnd.docker.distribution.manifest.v2+json"
+)
+
+const (
+	// maxManifestSize is the largest manifest registries are required to
+	// accept.
+	maxManifestSize = 4 << 20
+	// maxConfigSize limits how much of an image config blob is read.
+	maxConfigSize = 16 << 20
+)
+
+// acceptedManifestTypes are the manifest media types the client understands,
+// most preferred first.
+var acceptedManifestTypes = []string{
+	MediaTypeOCIIndex,
+	MediaTypeDockerManifestList,
+	MediaTypeOCIManifest,
+	MediaTypeDockerManifest,
+}
+
+// Platform describes the platform an image of an index runs on.
+type Platform struct {
+	Architecture string   `json:"architecture"`
+	OS           string   `json:"os"`
+	OSVersion    string   `json:"os.version,omitempty"`
+	OSFeatures   []string `json:"os.features,omitempty"`
+	Variant      string   `json:"variant,omitempty"`
+}
+
+// String returns the platform in the os/architecture[/variant] form used by
+// the docker CLI.
+func (p Platform) String() string {
+	s := p.OS + "/" + p.Architecture
+	if p.Variant != "" {
+		s += "/" + p.Variant
+	}
+	return s
+}
+
+// Descriptor points to content in a registry.
+type Descriptor struct {
+	MediaType    string            `json:"mediaType"`
+	ArtifactType string            `json:"artifactType,omitempty"`
+	Digest       string            `json:"digest"`
+	Size         int64             `json:"size"`
+	Platform     *Platform         `json:"platform,omitempty"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
+	// URLs are the locations of non-distributable layers, such as the base
+	// layers of Windows images, which registries do not store.
+	URLs []string `json:"urls,omitempty"`
+}
+
+// Manifest is an image manifest or an index (manifest list) of manifests.
+type Manifest struct {
+	SchemaVersion int               `json:"schemaVersion"`
+	MediaType     string            `json:"mediaType,omitempty"`
+	ArtifactType  string            `json:"artifactType,omitempty"`
+	Config        *Descriptor       `json:"config,omitempty"`
+	Layers        []Descriptor      `json:"layers,omitempty"`
+	Manifests     []Descriptor      `json:"manifests,omitempty"`
+	Annotations   map[string]string `json:"annotations,omitempty"`
+
+	// Descriptor describes the manifest itself.
+	Descriptor Descriptor `json:"-"`
+	// Raw is the manifest as returned by the registry, which its digest is
+	// computed from.
+	Raw []byte `json:"-"`
+}
+
+// IsIndex reports whether the manifest is an index of per-platform
+// manifests.
+func (m Manifest) IsIndex() bool {
+	switch m.Descriptor.MediaType {
+	case MediaTypeOCIIndex, MediaTypeDockerManifestList:
+		return true
+	}
+	return false
+}
+
+// ImageConfig is the part of an image config blob the client exposes.
+type ImageConfig struct {
+	Architecture string `json:"architecture"`
+	OS           string `json:"os"`
+	Variant      string `json:"variant,omitempty"`
+	Created      string `json:"created,omitempty"`
+	Config       struct {
+		Labels map[string]string `json:"Labels"`
+	} `json:"config"`
+}
+
+// GetManifest fetches the manifest or index of a tag or digest. A manifest
+// fetched by digest is verified against it.
+func (c *Client) GetManifest(ctx context.Context, host, repository, reference string) (Manifest, error) {
+	res, err := c.do(ctx, request{
+		method:     http.MethodGet,
+		host:       host,
+		repository: repository,
+		path:       "/manifests/" + reference,
+		headers:    manifestHeaders(),
+	})
+	if err != nil {
+		return Manifest{}, err
+	}
+	defer res.Body.Close()
+
+	raw, err := io.ReadAll(io.LimitReader(res.Body, maxManifestSize+1))
+	if err != nil {
+		return Manifest{}, fmt.Errorf("read manifest: %w", err)
+	}
+	if len(raw) > maxManifestSize {
+		return Manifest{}, fmt.Errorf("manifest %s@%s is larger than %d bytes", repository, reference, maxManifestSize)
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(raw, &manifest); err != nil {
+		return Manifest{}, fmt.Errorf("decode manifest: %w", err)
+	}
+	manifest.Raw = raw
+	manifest.Descriptor = Descriptor{
+		MediaType: contentType(res.Header),
+		Digest:    res.Header.Get("Docker-Content-Digest"),
+		Size:      int64(len(raw)),
+	}
+	if manifest.Descriptor.MediaType == "" || manifest.Descriptor.MediaType == "application/json" {
+		manifest.Descriptor.MediaType = manifest.MediaType
+	}
+
+	computed := Digest(raw)
+	if isDigest(reference) {
+		if strings.HasPrefix(reference, "sha256:") && reference != computed {
+			return Manifest{}, fmt.Errorf("manifest of %s has digest %s, want %s", repository, computed, reference)
+		}
+		manifest.Descriptor.Digest = reference
+	} else if manifest.Descriptor.Digest == "" {
+		manifest.Descriptor.Digest = computed
+	}
+	return manifest, nil
+}
+
+// HeadManifest returns the descriptor of a tag or digest without fetching
+// the manifest. Unlike GET requests for manifests, HEAD requests do not
+// count towards the Docker Hub pull rate limit.
+func (c *Client) HeadManifest(ctx context.Context, host, repository, reference string) (Descriptor, error) {
+	res, err := c.do(ctx, request{
+		method:     http.MethodHead,
+		host:       host,
+		repository: repository,
+		path:       "/manifests/" + reference,
+		headers:    manifestHeaders(),
+	})
+	if err != nil {
+		return Descriptor{}, err
+	}
+	res.Body.Close()
+
+	desc := Descriptor{
+		MediaType: contentType(res.Header),
+		Digest:    res.Header.Get("Docker-Content-Digest"),
+	}
+	desc.Size, _ = strconv.ParseInt(res.Header.Get("Content-Length"), 10, 64)
+	if desc.Digest == "" {
+		// The digest header is optional, fall back to computing it.
+		manifest, err := c.GetManifest(ctx, host, repository, reference)
+		if err != nil {
+			return Descriptor{}, err
+		}
+		return manifest.Descriptor, nil
+	}
+	return desc, nil
+}
+
+// GetImageConfig fetches and verifies the config blob of an image manifest.
+func (c *Client) GetImageConfig(ctx context.Context, host, repository, digest string) (ImageConfig, error) {
+	res, err := c.do(ctx, request{
+		method:     http.MethodGet,
+		host:       host,
+		repository: repository,
+		path:       "/blobs/" + digest,
+	})
+	if err != nil {
+		return ImageConfig{}, err
+	}
+	defer res.Body.Close()
+
+	raw, err := io.ReadAll(io.LimitReader(res.Body, maxConfigSize+1))
+	if err != nil {
+		return ImageConfig{}, fmt.Errorf("read image config: %w", err)
+	}
+	if len(raw) > maxConfigSize {
+		return ImageConfig{}, fmt.Errorf("image config %s is larger than %d bytes", digest, maxConfigSize)
+	}
+	if strings.HasPrefix(digest, "sha256:") && Digest(raw) != digest {
+		return ImageConfig{}, fmt.Errorf("image config of %s has digest %s, want %s", repository, Digest(raw), digest)
+	}
+
+	var config ImageConfig
+	if err := json.Unmarshal(raw, &config); err != nil {
+		return ImageConfig{}, fmt.Errorf("decode image config: %w", err)
+	}
+	return config, nil
+}
+
+// Digest returns the sha256 digest of content.
+func Digest(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func isDigest(reference string) bool {
+	return strings.Contains(reference, ":")
+}
+
+func manifestHeaders() http.Header {
+	return http.Header{"Accept": {strings.Join(acceptedManifestTypes, ", ")}}
+}
+
+// contentType returns the media type of a response, without parameters.
+func contentType(header http.Header) string {
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		return ""
+	}
+	return mediaType
+}
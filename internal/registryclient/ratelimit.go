@@ -0,0 +1,119 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package registryclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	// RateLimitRepository is the repository Docker Hub provides to check the
+	// pull rate limit with.
+	RateLimitRepository = "ratelimitpreview/test"
+	// RateLimitTag is the tag of RateLimitRepository to check the pull rate
+	// limit with.
+	RateLimitTag = "latest"
+)
+
+// RateLimit is the pull rate limit status reported by a registry.
+type RateLimit struct {
+	// Limited is false when the registry did not report a limit, such as
+	// for Docker Hub accounts without pull limits.
+	Limited bool
+	// Limit is how many pulls are allowed per window.
+	Limit int64
+	// Remaining is how many pulls are left in the current window.
+	Remaining int64
+	// WindowSeconds is the length of the window in seconds.
+	WindowSeconds int64
+	// Source is what the limit applies to: the IP address for anonymous
+	// pulls or the account ID for authenticated pulls.
+	Source string
+}
+
+// GetPullRateLimit returns the pull rate limit status of the caller by
+// sending a HEAD request for the manifest of an image, which does not count
+// towards the limit.
+func (c *Client) GetPullRateLimit(ctx context.Context, host, repository, reference string) (RateLimit, error) {
+	res, err := c.do(ctx, request{
+		method:     http.MethodHead,
+		host:       host,
+		repository: repository,
+		path:       "/manifests/" + reference,
+		headers:    manifestHeaders(),
+	})
+	if err != nil {
+		return RateLimit{}, err
+	}
+	res.Body.Close()
+	return parseRateLimit(res.Header)
+}
+
+// parseRateLimit parses the rate limit headers of Docker Hub:
+//
+//	ratelimit-limit: 100;w=21600
+//	ratelimit-remaining: 76;w=21600
+//	docker-ratelimit-source: 192.0.2.1
+func parseRateLimit(header http.Header) (RateLimit, error) {
+	limitHeader := header.Get("RateLimit-Limit")
+	if limitHeader == "" {
+		return RateLimit{Source: header.Get("Docker-RateLimit-Source")}, nil
+	}
+
+	limit, window, err := parseRateLimitValue(limitHeader)
+	if err != nil {
+		return RateLimit{}, fmt.Errorf("parse ratelimit-limit header: %w", err)
+	}
+	remaining, remainingWindow, err := parseRateLimitValue(header.Get("RateLimit-Remaining"))
+	if err != nil {
+		return RateLimit{}, fmt.Errorf("parse ratelimit-remaining header: %w", err)
+	}
+	if window == 0 {
+		window = remainingWindow
+	}
+	return RateLimit{
+		Limited:       true,
+		Limit:         limit,
+		Remaining:     remaining,
+		WindowSeconds: window,
+		Source:        header.Get("Docker-RateLimit-Source"),
+	}, nil
+}
+
+// parseRateLimitValue parses a value of the form "<count>;w=<seconds>",
+// where the window is optional.
+func parseRateLimitValue(value string) (count, window int64, err error) {
+	parts := strings.Split(value, ";")
+	count, err = strconv.ParseInt(strings.TrimSpace(parts[0]), 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid count in %q", value)
+	}
+	for _, param := range parts[1:] {
+		name, val, _ := strings.Cut(strings.TrimSpace(param), "=")
+		if name != "w" {
+			continue
+		}
+		if window, err = strconv.ParseInt(val, 10, 64); err != nil {
+			return 0, 0, fmt.Errorf("invalid window in %q", value)
+		}
+	}
+	return count, window, nil
+}
@@ -0,0 +1,86 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package registryclient
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  http.Header
+		want    RateLimit
+		wantErr bool
+	}{
+		{
+			name: "anonymous",
+			header: http.Header{
+				"Ratelimit-Limit":         {"100;w=21600"},
+				"Ratelimit-Remaining":     {"76;w=21600"},
+				"Docker-Ratelimit-Source": {"192.0.2.1"},
+			},
+			want: RateLimit{Limited: true, Limit: 100, Remaining: 76, WindowSeconds: 21600, Source: "192.0.2.1"},
+		},
+		{
+			name: "window on remaining only",
+			header: http.Header{
+				"Ratelimit-Limit":     {"200"},
+				"Ratelimit-Remaining": {"0; w=3600"},
+			},
+			want: RateLimit{Limited: true, Limit: 200, Remaining: 0, WindowSeconds: 3600},
+		},
+		{
+			name:   "unlimited",
+			header: http.Header{"Docker-Ratelimit-Source": {"0b1c2d3e"}},
+			want:   RateLimit{Source: "0b1c2d3e"},
+		},
+		{
+			name:    "invalid limit",
+			header:  http.Header{"Ratelimit-Limit": {"lots"}, "Ratelimit-Remaining": {"1"}},
+			wantErr: true,
+		},
+		{
+			name:    "missing remaining",
+			header:  http.Header{"Ratelimit-Limit": {"100;w=21600"}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid window",
+			header:  http.Header{"Ratelimit-Limit": {"100;w=6h"}, "Ratelimit-Remaining": {"1"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseRateLimit(tt.header)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("got %+v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
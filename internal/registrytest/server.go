@@ -0,0 +1,592 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package registrytest provides an in-memory fake of a container registry
+// speaking the OCI distribution API, with a token service in the style of
+// Docker Hub, so that the registryclient package and the provider can be
+// tested without network access.
+package registrytest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/terraform-provider-docker/internal/registryclient"
+)
+
+const (
+	// Service is the service name the token service issues tokens for.
+	Service = "registrytest"
+
+	// DefaultTokenTTL is how long tokens issued by the token service are
+	// valid for.
+	DefaultTokenTTL = 5 * time.Minute
+)
+
+// Server is a fake registry. Pulling from public repositories works
+// anonymously, pulling from private repositories and pushing requires the
+// credentials of a user.
+type Server struct {
+	*httptest.Server
+
+	// TokenTTL is the lifetime of tokens issued by the token service.
+	TokenTTL time.Duration
+
+	mu            sync.Mutex
+	users         map[string]string
+	repos         map[string]*repository
+	tokens        map[string]grant
+	uploads       map[string]string
+	tokenRequests int
+	blobUploads   int
+
+	// pullLimit is the number of manifest pulls allowed per source, or 0
+	// for no limit.
+	pullLimit  int
+	pullWindow time.Duration
+	pulls      map[string]int
+}
+
+type repository struct {
+	private   bool
+	manifests map[string]manifest
+	tags      map[string]string
+	blobs     map[string][]byte
+}
+
+type manifest struct {
+	mediaType string
+	raw       []byte
+}
+
+// grant is what a token allows.
+type grant struct {
+	username string
+	actions  map[string]bool
+	expiry   time.Time
+}
+
+// NewServer starts a new fake registry. The caller should call Close when
+// finished, to shut it down.
+func NewServer() *Server {
+	s := &Server{
+		TokenTTL: DefaultTokenTTL,
+		users:    map[string]string{},
+		repos:    map[string]*repository{},
+		tokens:   map[string]grant{},
+		uploads:  map[string]string{},
+		pulls:    map[string]int{},
+	}
+	s.Server = httptest.NewServer(s.routes())
+	return s
+}
+
+// Host returns the host and port of the registry, as used in image
+// references.
+func (s *Server) Host() string {
+	return strings.TrimPrefix(s.URL, "http://")
+}
+
+// AddUser registers a user that can authenticate with the given password.
+func (s *Server) AddUser(username, password string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users[username] = password
+}
+
+// SetPrivate makes a repository require authentication to pull from.
+func (s *Server) SetPrivate(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.repository(name).private = true
+}
+
+// TokenRequests returns how many tokens the token service issued.
+func (s *Server) TokenRequests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tokenRequests
+}
+
+// BlobUploads returns how many blobs were uploaded, rather than mounted from
+// another repository.
+func (s *Server) BlobUploads() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.blobUploads
+}
+
+// SetPullRateLimit limits how many manifests every user, or every IP
+// address for anonymous pulls, can pull. Like Docker Hub, manifest responses
+// report the limit in ratelimit headers, and HEAD requests do not count
+// towards it.
+func (s *Server) SetPullRateLimit(limit int, window time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pullLimit = limit
+	s.pullWindow = window
+}
+
+// Tag returns the digest a tag points to, if it exists.
+func (s *Server) Tag(name, tag string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	repo, ok := s.repos[name]
+	if !ok {
+		return "", false
+	}
+	digest, ok := repo.tags[tag]
+	return digest, ok
+}
+
+// Image describes an image to add with AddImage.
+type Image struct {
+	// Platforms are the platforms of the image. An image with more than one
+	// platform is pushed as an index. Defaults to linux/amd64.
+	Platforms []registryclient.Platform
+	// Labels are the labels of the image config of every platform.
+	Labels map[string]string
+}
+
+// AddImage pushes an image built from placeholder content and tags it. It
+// returns the digest of the tag, the index if the image has more than one
+// platform.
+func (s *Server) AddImage(name, tag string, image Image) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repo := s.repository(name)
+	platforms := image.Platforms
+	if len(platforms) == 0 {
+		platforms = []registryclient.Platform{{OS: "linux", Architecture: "amd64"}}
+	}
+
+	var descriptors []registryclient.Descriptor
+	for _, platform := range platforms {
+		config := registryclient.ImageConfig{
+			Architecture: platform.Architecture,
+			OS:           platform.OS,
+			Variant:      platform.Variant,
+			Created:      "2024-01-01T00:00:00Z",
+		}
+		config.Config.Labels = image.Labels
+		configDesc := repo.addBlob("application/vnd.oci.image.config.v1+json", mustMarshal(config))
+		layerDesc := repo.addBlob("application/vnd.oci.image.layer.v1.tar+gzip", []byte(fmt.Sprintf("%s:%s %s", name, tag, platform)))
+
+		desc := repo.addManifest(registryclient.MediaTypeOCIManifest, mustMarshal(registryclient.Manifest{
+			SchemaVersion: 2,
+			MediaType:     registryclient.MediaTypeOCIManifest,
+			Config:        &configDesc,
+			Layers:        []registryclient.Descriptor{layerDesc},
+		}))
+		platform := platform
+		desc.Platform = &platform
+		descriptors = append(descriptors, desc)
+	}
+
+	desc := descriptors[0]
+	if len(descriptors) > 1 {
+		desc = repo.addManifest(registryclient.MediaTypeOCIIndex, mustMarshal(registryclient.Manifest{
+			SchemaVersion: 2,
+			MediaType:     registryclient.MediaTypeOCIIndex,
+			Manifests:     descriptors,
+		}))
+	}
+	repo.tags[tag] = desc.Digest
+	return desc.Digest
+}
+
+// repository returns a repository, creating it if needed. The caller must
+// hold s.mu.
+func (s *Server) repository(name string) *repository {
+	repo, ok := s.repos[name]
+	if !ok {
+		repo = &repository{
+			manifests: map[string]manifest{},
+			tags:      map[string]string{},
+			blobs:     map[string][]byte{},
+		}
+		s.repos[name] = repo
+	}
+	return repo
+}
+
+func (r *repository) blob(digest string) ([]byte, bool) {
+	if r == nil {
+		return nil, false
+	}
+	blob, ok := r.blobs[digest]
+	return blob, ok
+}
+
+func (r *repository) addBlob(mediaType string, content []byte) registryclient.Descriptor {
+	digest := registryclient.Digest(content)
+	r.blobs[digest] = content
+	return registryclient.Descriptor{MediaType: mediaType, Digest: digest, Size: int64(len(content))}
+}
+
+func (r *repository) addManifest(mediaType string, raw []byte) registryclient.Descriptor {
+	digest := registryclient.Digest(raw)
+	r.manifests[digest] = manifest{mediaType: mediaType, raw: raw}
+	return registryclient.Descriptor{MediaType: mediaType, Digest: digest, Size: int64(len(raw))}
+}
+
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /token", s.handleToken)
+	mux.HandleFunc("GET /v2/{$}", s.handleBase)
+	mux.HandleFunc("/v2/", s.handleRepository)
+	return mux
+}
+
+func (s *Server) handleToken(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	username := ""
+	if header := r.Header.Get("Authorization"); header != "" {
+		user, password, ok := r.BasicAuth()
+		if !ok || user == "" || s.users[user] != password {
+			writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "incorrect username or password")
+			return
+		}
+		username = user
+	}
+
+	g := grant{username: username, actions: map[string]bool{}, expiry: time.Now().Add(s.TokenTTL)}
+	for _, scope := range r.URL.Query()["scope"] {
+		parts := strings.Split(scope, ":")
+		if len(parts) != 3 || parts[0] != "repository" {
+			continue
+		}
+		repo, exists := s.repos[parts[1]]
+		for _, action := range strings.Split(parts[2], ",") {
+			switch {
+			case action == "pull" && (username != "" || !exists || !repo.private):
+				g.actions[parts[1]+":pull"] = true
+			case action == "push" && username != "":
+				g.actions[parts[1]+":push"] = true
+			}
+		}
+	}
+
+	token := randomHex(16)
+	s.tokens[token] = g
+	s.tokenRequests++
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"token":      token,
+		"expires_in": int(s.TokenTTL.Seconds()),
+		"issued_at":  time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+func (s *Server) handleBase(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{})
+}
+
+func (s *Server) handleRepository(w http.ResponseWriter, r *http.Request) {
+	rest := strings.TrimPrefix(r.URL.Path, "/v2/")
+	var name, kind, reference string
+	for _, k := range []string{"manifests", "blobs"} {
+		if i := strings.LastIndex(rest, "/"+k+"/"); i > 0 {
+			name, kind, reference = rest[:i], k, rest[i+len(k)+2:]
+			break
+		}
+	}
+	if name == "" || reference == "" {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "unknown endpoint")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	action := "pull"
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		action = "push"
+	}
+	if !s.authorize(w, r, name, action) {
+		return
+	}
+	repo, ok := s.repos[name]
+	if !ok {
+		if action == "pull" {
+			writeError(w, http.StatusNotFound, "NAME_UNKNOWN", "repository name not known to registry")
+			return
+		}
+		repo = s.repository(name)
+	}
+
+	switch {
+	case kind == "manifests" && (r.Method == http.MethodGet || r.Method == http.MethodHead):
+		s.handleGetManifest(w, r, repo, reference)
+	case kind == "manifests" && r.Method == http.MethodPut:
+		s.handlePutManifest(w, r, repo, reference)
+	case kind == "manifests" && r.Method == http.MethodDelete:
+		s.handleDeleteManifest(w, r, repo, reference)
+	case kind == "blobs" && reference == "uploads/" && r.Method == http.MethodPost:
+		s.handleStartUpload(w, r, name, repo)
+	case kind == "blobs" && strings.HasPrefix(reference, "uploads/") && r.Method == http.MethodPut:
+		s.handleCompleteUpload(w, r, name, repo, strings.TrimPrefix(reference, "uploads/"))
+	case kind == "blobs" && (r.Method == http.MethodGet || r.Method == http.MethodHead):
+		s.handleGetBlob(w, r, repo, reference)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "UNSUPPORTED", "the operation is unsupported")
+	}
+}
+
+func (s *Server) handleGetManifest(w http.ResponseWriter, r *http.Request, repo *repository, reference string) {
+	digest := reference
+	if !strings.Contains(reference, ":") {
+		digest = repo.tags[reference]
+	}
+	m, ok := repo.manifests[digest]
+	if !ok {
+		writeError(w, http.StatusNotFound, "MANIFEST_UNKNOWN", "manifest unknown")
+		return
+	}
+	if accept := r.Header.Get("Accept"); accept != "" && !strings.Contains(accept, m.mediaType) {
+		writeError(w, http.StatusNotFound, "MANIFEST_UNKNOWN", "manifest unknown for the accepted media types")
+		return
+	}
+
+	if !s.countPull(w, r) {
+		return
+	}
+	w.Header().Set("Content-Type", m.mediaType)
+	w.Header().Set("Docker-Content-Digest", digest)
+	w.Header().Set("Content-Length", fmt.Sprint(len(m.raw)))
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodGet {
+		_, _ = w.Write(m.raw)
+	}
+}
+
+func (s *Server) handleGetBlob(w http.ResponseWriter, r *http.Request, repo *repository, digest string) {
+	blob, ok := repo.blobs[digest]
+	if !ok {
+		writeError(w, http.StatusNotFound, "BLOB_UNKNOWN", "blob unknown to registry")
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Docker-Content-Digest", digest)
+	w.Header().Set("Content-Length", fmt.Sprint(len(blob)))
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodGet {
+		_, _ = w.Write(blob)
+	}
+}
+
+func (s *Server) handlePutManifest(w http.ResponseWriter, r *http.Request, repo *repository, reference string) {
+	raw, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "MANIFEST_INVALID", err.Error())
+		return
+	}
+	var m registryclient.Manifest
+	if err := json.Unmarshal(raw, &m); err != nil {
+		writeError(w, http.StatusBadRequest, "MANIFEST_INVALID", err.Error())
+		return
+	}
+	digest := registryclient.Digest(raw)
+	if strings.Contains(reference, ":") && reference != digest {
+		writeError(w, http.StatusBadRequest, "DIGEST_INVALID", "manifest does not match the digest")
+		return
+	}
+
+	// Like real registries, refuse manifests referencing missing content.
+	for _, entry := range m.Manifests {
+		if _, ok := repo.manifests[entry.Digest]; !ok {
+			writeError(w, http.StatusBadRequest, "MANIFEST_UNKNOWN", fmt.Sprintf("manifest %s is unknown", entry.Digest))
+			return
+		}
+	}
+	blobs := m.Layers
+	if m.Config != nil {
+		blobs = append(blobs, *m.Config)
+	}
+	for _, blob := range blobs {
+		if _, ok := repo.blobs[blob.Digest]; !ok {
+			writeError(w, http.StatusBadRequest, "MANIFEST_BLOB_UNKNOWN", fmt.Sprintf("blob %s is unknown", blob.Digest))
+			return
+		}
+	}
+
+	mediaType := r.Header.Get("Content-Type")
+	if mediaType == "" {
+		mediaType = m.MediaType
+	}
+	repo.manifests[digest] = manifest{mediaType: mediaType, raw: raw}
+	if !strings.Contains(reference, ":") {
+		repo.tags[reference] = digest
+	}
+	w.Header().Set("Docker-Content-Digest", digest)
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) handleDeleteManifest(w http.ResponseWriter, r *http.Request, repo *repository, reference string) {
+	if !strings.Contains(reference, ":") {
+		if _, ok := repo.tags[reference]; !ok {
+			writeError(w, http.StatusNotFound, "MANIFEST_UNKNOWN", "manifest unknown")
+			return
+		}
+		delete(repo.tags, reference)
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	if _, ok := repo.manifests[reference]; !ok {
+		writeError(w, http.StatusNotFound, "MANIFEST_UNKNOWN", "manifest unknown")
+		return
+	}
+	delete(repo.manifests, reference)
+	for tag, digest := range repo.tags {
+		if digest == reference {
+			delete(repo.tags, tag)
+		}
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (s *Server) handleStartUpload(w http.ResponseWriter, r *http.Request, name string, repo *repository) {
+	query := r.URL.Query()
+	if digest, from := query.Get("mount"), query.Get("from"); digest != "" && from != "" {
+		// Mounting needs pull access to the source repository.
+		source, ok := s.repos[from]
+		token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if blob, found := source.blob(digest); ok && found && s.tokens[token].actions[from+":pull"] {
+			repo.blobs[digest] = blob
+			w.Header().Set("Location", fmt.Sprintf("/v2/%s/blobs/%s", name, digest))
+			w.Header().Set("Docker-Content-Digest", digest)
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+	}
+
+	id := randomHex(16)
+	s.uploads[id] = name
+	w.Header().Set("Location", fmt.Sprintf("/v2/%s/blobs/uploads/%s", name, id))
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (s *Server) handleCompleteUpload(w http.ResponseWriter, r *http.Request, name string, repo *repository, id string) {
+	if s.uploads[id] != name {
+		writeError(w, http.StatusNotFound, "BLOB_UPLOAD_UNKNOWN", "blob upload unknown to registry")
+		return
+	}
+	content, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "BLOB_UPLOAD_INVALID", err.Error())
+		return
+	}
+	digest := r.URL.Query().Get("digest")
+	if registryclient.Digest(content) != digest {
+		writeError(w, http.StatusBadRequest, "DIGEST_INVALID", "provided digest did not match uploaded content")
+		return
+	}
+	delete(s.uploads, id)
+	repo.blobs[digest] = content
+	s.blobUploads++
+	w.Header().Set("Location", fmt.Sprintf("/v2/%s/blobs/%s", name, digest))
+	w.Header().Set("Docker-Content-Digest", digest)
+	w.WriteHeader(http.StatusCreated)
+}
+
+// authorize checks that the request carries a token allowing the action on
+// the repository, and challenges the client otherwise. The caller must hold
+// s.mu.
+// countPull counts a manifest pull towards the rate limit of its source and
+// sets the ratelimit headers. It reports whether the pull is allowed.
+func (s *Server) countPull(w http.ResponseWriter, r *http.Request) bool {
+	if s.pullLimit == 0 {
+		return true
+	}
+
+	source := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(source); err == nil {
+		source = host
+	}
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		if g, found := s.tokens[token]; found && g.username != "" {
+			source = g.username
+		}
+	}
+
+	window := int(s.pullWindow.Seconds())
+	w.Header().Set("Docker-RateLimit-Source", source)
+	w.Header().Set("RateLimit-Limit", fmt.Sprintf("%d;w=%d", s.pullLimit, window))
+	if r.Method == http.MethodGet {
+		if s.pulls[source] >= s.pullLimit {
+			w.Header().Set("RateLimit-Remaining", fmt.Sprintf("0;w=%d", window))
+			writeError(w, http.StatusTooManyRequests, "TOOMANYREQUESTS", "You have reached your pull rate limit.")
+			return false
+		}
+		s.pulls[source]++
+	}
+	w.Header().Set("RateLimit-Remaining", fmt.Sprintf("%d;w=%d", s.pullLimit-s.pulls[source], window))
+	return true
+}
+
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request, name, action string) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if ok {
+		g, found := s.tokens[token]
+		if found && time.Now().Before(g.expiry) && g.actions[name+":"+action] {
+			return true
+		}
+	}
+
+	scope := fmt.Sprintf("repository:%s:%s", name, action)
+	w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="%s",scope="%s"`, s.URL, Service, scope))
+	writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "authentication required")
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, map[string]interface{}{
+		"errors": []map[string]string{{"code": code, "message": message}},
+	})
+}
+
+func mustMarshal(v interface{}) []byte {
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,257 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package registrytest
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/docker/terraform-provider-docker/internal/registryclient"
+)
+
+func newTestClient(username, password string) *registryclient.Client {
+	return registryclient.NewClient(registryclient.Config{
+		Credentials: func(ctx context.Context, host string) (string, string, error) {
+			return username, password, nil
+		},
+	})
+}
+
+func newSeededServer(t *testing.T) *Server {
+	t.Helper()
+	server := NewServer()
+	t.Cleanup(server.Close)
+
+	server.AddUser("alice", "secret")
+	return server
+}
+
+func TestGetManifest(t *testing.T) {
+	ctx := context.Background()
+	server := newSeededServer(t)
+	digest := server.AddImage("library/alpine", "3.20", Image{Labels: map[string]string{"maintainer": "alice"}})
+	client := newTestClient("", "")
+
+	manifest, err := client.GetManifest(ctx, server.Host(), "library/alpine", "3.20")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if manifest.Descriptor.Digest != digest || manifest.Descriptor.MediaType != registryclient.MediaTypeOCIManifest || manifest.IsIndex() {
+		t.Errorf("got manifest descriptor %+v, want an image manifest with digest %s", manifest.Descriptor, digest)
+	}
+	if manifest.Config == nil || len(manifest.Layers) != 1 {
+		t.Fatalf("got manifest %+v, want a config and a layer", manifest)
+	}
+
+	config, err := client.GetImageConfig(ctx, server.Host(), "library/alpine", manifest.Config.Digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.OS != "linux" || config.Architecture != "amd64" || config.Config.Labels["maintainer"] != "alice" {
+		t.Errorf("got image config %+v", config)
+	}
+
+	byDigest, err := client.GetManifest(ctx, server.Host(), "library/alpine", digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(byDigest.Raw) != string(manifest.Raw) {
+		t.Errorf("got a different manifest by digest")
+	}
+
+	desc, err := client.HeadManifest(ctx, server.Host(), "library/alpine", "3.20")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if desc.Digest != digest || desc.Size != manifest.Descriptor.Size {
+		t.Errorf("got descriptor %+v, want digest %s", desc, digest)
+	}
+
+	// The token of the first request is reused.
+	if n := server.TokenRequests(); n != 1 {
+		t.Errorf("got %d token requests, want 1", n)
+	}
+
+	_, err = client.GetManifest(ctx, server.Host(), "library/alpine", "missing")
+	if apiErr, ok := registryclient.AsAPIError(err); !ok || apiErr.Code != "MANIFEST_UNKNOWN" || !registryclient.IsNotFound(err) {
+		t.Errorf("got %v, want a manifest unknown error", err)
+	}
+}
+
+func TestGetIndex(t *testing.T) {
+	ctx := context.Background()
+	server := newSeededServer(t)
+	digest := server.AddImage("library/alpine", "latest", Image{
+		Platforms: []registryclient.Platform{
+			{OS: "linux", Architecture: "amd64"},
+			{OS: "linux", Architecture: "arm64", Variant: "v8"},
+		},
+	})
+	client := newTestClient("", "")
+
+	index, err := client.GetManifest(ctx, server.Host(), "library/alpine", "latest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if index.Descriptor.Digest != digest || !index.IsIndex() || len(index.Manifests) != 2 {
+		t.Fatalf("got index %+v, want two manifests", index)
+	}
+	if p := index.Manifests[1].Platform; p == nil || p.String() != "linux/arm64/v8" {
+		t.Errorf("got platform %v, want linux/arm64/v8", p)
+	}
+
+	manifest, err := client.GetManifest(ctx, server.Host(), "library/alpine", index.Manifests[1].Digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if manifest.IsIndex() || manifest.Config == nil {
+		t.Errorf("got manifest %+v, want an image manifest", manifest)
+	}
+}
+
+func TestPrivateRepository(t *testing.T) {
+	ctx := context.Background()
+	server := newSeededServer(t)
+	server.AddImage("alice/app", "v1", Image{})
+	server.SetPrivate("alice/app")
+
+	_, err := newTestClient("", "").GetManifest(ctx, server.Host(), "alice/app", "v1")
+	if apiErr, ok := registryclient.AsAPIError(err); !ok || apiErr.StatusCode != 401 {
+		t.Errorf("got %v, want an unauthorized error", err)
+	}
+
+	_, err = newTestClient("alice", "wrong").GetManifest(ctx, server.Host(), "alice/app", "v1")
+	if apiErr, ok := registryclient.AsAPIError(err); !ok || apiErr.StatusCode != 401 {
+		t.Errorf("got %v, want an unauthorized error", err)
+	}
+
+	if _, err := newTestClient("alice", "secret").GetManifest(ctx, server.Host(), "alice/app", "v1"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCopyImage(t *testing.T) {
+	ctx := context.Background()
+	server := newSeededServer(t)
+	digest := server.AddImage("alice/app-staging", "abc123", Image{
+		Platforms: []registryclient.Platform{
+			{OS: "linux", Architecture: "amd64"},
+			{OS: "linux", Architecture: "arm64", Variant: "v8"},
+		},
+	})
+	client := newTestClient("alice", "secret")
+	src := registryclient.Image{Host: server.Host(), Repository: "alice/app-staging", Reference: "abc123"}
+	dst := registryclient.Image{Host: server.Host(), Repository: "alice/app", Reference: "1.2.3"}
+
+	desc, err := client.CopyImage(ctx, src, dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if desc.Digest != digest {
+		t.Errorf("got digest %s, want %s", desc.Digest, digest)
+	}
+	if got, _ := server.Tag("alice/app", "1.2.3"); got != digest {
+		t.Errorf("got tag digest %q, want %s", got, digest)
+	}
+	if n := server.BlobUploads(); n != 0 {
+		t.Errorf("got %d blob uploads, want blobs to be mounted", n)
+	}
+
+	// Copying again is a no-op.
+	if _, err := client.CopyImage(ctx, src, dst); err != nil {
+		t.Fatal(err)
+	}
+
+	// Blobs are copied between registries.
+	other := NewServer()
+	t.Cleanup(other.Close)
+	other.AddUser("alice", "secret")
+	desc, err = client.CopyImage(ctx, src, registryclient.Image{Host: other.Host(), Repository: "alice/app", Reference: "1.2.3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := other.Tag("alice/app", "1.2.3"); got != digest || desc.Digest != digest {
+		t.Errorf("got tag digest %q, want %s", got, digest)
+	}
+	if n := other.BlobUploads(); n != 4 {
+		t.Errorf("got %d blob uploads, want a config and a layer per platform", n)
+	}
+
+	_, err = newTestClient("", "").CopyImage(ctx, src, registryclient.Image{Host: server.Host(), Repository: "alice/other", Reference: "v1"})
+	if apiErr, ok := registryclient.AsAPIError(err); !ok || apiErr.StatusCode != 401 {
+		t.Errorf("got %v, want an unauthorized error for an anonymous push", err)
+	}
+	if _, err := client.CopyImage(ctx, src, registryclient.Image{Host: server.Host(), Repository: "alice/app", Reference: digest}); err == nil {
+		t.Error("copied to a digest, want an error")
+	}
+
+	if err := client.DeleteManifest(ctx, server.Host(), "alice/app", "1.2.3"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := server.Tag("alice/app", "1.2.3"); ok {
+		t.Error("tag still exists after deleting it")
+	}
+	if _, err := client.HeadManifest(ctx, server.Host(), "alice/app", digest); err != nil {
+		t.Errorf("got %v, want the manifest to outlive its tag", err)
+	}
+}
+
+func TestPullRateLimit(t *testing.T) {
+	ctx := context.Background()
+	server := newSeededServer(t)
+	server.AddImage(registryclient.RateLimitRepository, registryclient.RateLimitTag, Image{})
+	server.SetPullRateLimit(2, 6*time.Hour)
+	anonymous := newTestClient("", "")
+	alice := newTestClient("alice", "secret")
+
+	limit, err := anonymous.GetPullRateLimit(ctx, server.Host(), registryclient.RateLimitRepository, registryclient.RateLimitTag)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := registryclient.RateLimit{Limited: true, Limit: 2, Remaining: 2, WindowSeconds: 21600, Source: "127.0.0.1"}
+	if limit != want {
+		t.Errorf("got %+v, want %+v", limit, want)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := anonymous.GetManifest(ctx, server.Host(), registryclient.RateLimitRepository, registryclient.RateLimitTag); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, err = anonymous.GetManifest(ctx, server.Host(), registryclient.RateLimitRepository, registryclient.RateLimitTag)
+	if apiErr, ok := registryclient.AsAPIError(err); !ok || apiErr.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("got error %v, want a 429 response", err)
+	}
+
+	// HEAD requests do not count and still report the limit.
+	if limit, err = anonymous.GetPullRateLimit(ctx, server.Host(), registryclient.RateLimitRepository, registryclient.RateLimitTag); err != nil {
+		t.Fatal(err)
+	}
+	if limit.Remaining != 0 {
+		t.Errorf("got %d remaining pulls, want 0", limit.Remaining)
+	}
+
+	// Authenticated pulls are limited per user.
+	if limit, err = alice.GetPullRateLimit(ctx, server.Host(), registryclient.RateLimitRepository, registryclient.RateLimitTag); err != nil {
+		t.Fatal(err)
+	}
+	if limit.Source != "alice" || limit.Remaining != 2 {
+		t.Errorf("got %+v, want 2 remaining pulls for alice", limit)
+	}
+}
@@ -0,0 +1,84 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package totp computes time-based one-time passwords (RFC 6238), the codes
+// authenticator apps show for accounts with two-factor authentication.
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// Period is how long a code is valid for.
+	Period = 30 * time.Second
+	// Digits is the number of digits of a code.
+	Digits = 6
+)
+
+// ParseSecret decodes a base32 secret as shown by authenticator setup
+// screens. Spaces and case are ignored, and padding is optional.
+func ParseSecret(secret string) ([]byte, error) {
+	normalized := strings.ToUpper(strings.ReplaceAll(secret, " ", ""))
+	normalized = strings.TrimRight(normalized, "=")
+	if normalized == "" {
+		return nil, fmt.Errorf("TOTP secret is empty")
+	}
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(normalized)
+	if err != nil {
+		return nil, fmt.Errorf("TOTP secret is not valid base32: %v", err)
+	}
+	return key, nil
+}
+
+// Generate returns the code of a base32 secret at time t.
+func Generate(secret string, t time.Time) (string, error) {
+	key, err := ParseSecret(secret)
+	if err != nil {
+		return "", err
+	}
+	return code(key, uint64(t.Unix())/uint64(Period.Seconds())), nil
+}
+
+// Validate reports whether code is the code of a base32 secret at time t,
+// or of the periods right before or after it to allow for clock skew.
+func Validate(secret, code string, t time.Time) bool {
+	for _, skew := range []time.Duration{0, -Period, Period} {
+		if want, err := Generate(secret, t.Add(skew)); err == nil && hmac.Equal([]byte(want), []byte(code)) {
+			return true
+		}
+	}
+	return false
+}
+
+// code computes the HOTP value (RFC 4226) of a counter.
+func code(key []byte, counter uint64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], counter)
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", Digits, value%1000000)
+}
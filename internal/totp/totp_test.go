@@ -0,0 +1,86 @@
+/*
+   Copyright 2024 Docker Terraform Provider authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package totp
+
+import (
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA-1 secret of the RFC 6238 test vectors,
+// "12345678901234567890", in base32.
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestGenerate(t *testing.T) {
+	// The test vectors of RFC 6238, truncated to 6 digits.
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{unix: 59, want: "287082"},
+		{unix: 1111111109, want: "081804"},
+		{unix: 1111111111, want: "050471"},
+		{unix: 1234567890, want: "005924"},
+		{unix: 2000000000, want: "279037"},
+		{unix: 20000000000, want: "353130"},
+	}
+	for _, tt := range tests {
+		got, err := Generate(rfcSecret, time.Unix(tt.unix, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("Generate at %d: got %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestParseSecret(t *testing.T) {
+	want, err := ParseSecret(rfcSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"gezd gnbv gy3t qojq gezd gnbv gy3t qojq", rfcSecret + "===="} {
+		got, err := ParseSecret(secret)
+		if err != nil {
+			t.Fatalf("ParseSecret(%q): %v", secret, err)
+		}
+		if string(got) != string(want) {
+			t.Errorf("ParseSecret(%q): got %q, want %q", secret, got, want)
+		}
+	}
+
+	for _, secret := range []string{"", "not base32!", "GEZDGNBVGY3TQOJ1"} {
+		if _, err := ParseSecret(secret); err == nil {
+			t.Errorf("ParseSecret(%q): got no error", secret)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	code, err := Generate(rfcSecret, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Validate(rfcSecret, code, now.Add(Period)) {
+		t.Error("got the code of the previous period rejected")
+	}
+	if Validate(rfcSecret, code, now.Add(3*Period)) {
+		t.Error("got an old code accepted")
+	}
+}